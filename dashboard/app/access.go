@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"strings"
 
+	"golang.org/x/net/xsrftoken"
 	db "google.golang.org/appengine/v2/datastore"
 	"google.golang.org/appengine/v2/log"
 	"google.golang.org/appengine/v2/user"
@@ -46,6 +47,30 @@ func checkAccessLevel(c context.Context, r *http.Request, level AccessLevel) err
 	return ErrAccess
 }
 
+// xsrfToken returns a token for the admin form that performs the action on behalf of the current user.
+// It returns an empty string if XSRF tokens are not configured or the user is not signed in,
+// the forms must not be shown then.
+func xsrfToken(c context.Context, action string) string {
+	key := getConfig(c).XSRFKey
+	u := user.Current(c)
+	if key == "" || u == nil {
+		return ""
+	}
+	return xsrftoken.Generate(key, u.Email, action)
+}
+
+// checkXSRFToken checks that the form that performs the action was generated by the app
+// for the current user, and not submitted by a third-party site.
+func checkXSRFToken(c context.Context, r *http.Request, action string) error {
+	key := getConfig(c).XSRFKey
+	u := user.Current(c)
+	if key == "" || u == nil || !xsrftoken.Valid(r.FormValue("xsrf"), key, u.Email, action) {
+		log.Warningf(c, "bad XSRF token for %q, url %.100s", action, getCurrentURL(c))
+		return ErrAccess
+	}
+	return nil
+}
+
 // AuthDomain is broken in AppEngine tests.
 var isBrokenAuthDomainInTest = false
 
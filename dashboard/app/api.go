@@ -71,6 +71,7 @@ var apiNamespaceHandlers = map[string]APINamespaceHandler{
 	"update_report":       apiUpdateReport,
 	"add_build_assets":    apiAddBuildAssets,
 	"log_to_repro":        apiLogToReproduce,
+	"series_findings":     apiSeriesFindings,
 }
 
 type JSONHandler func(c context.Context, r *http.Request) (interface{}, error)
@@ -846,6 +847,10 @@ func reportCrash(c context.Context, build *Build, req *dashapi.Crash) (*Bug, err
 		}
 	}
 
+	var stackFrames []string
+	if save && !req.Corrupted {
+		stackFrames = reportStackFrames(req.Report)
+	}
 	tx := func(c context.Context) error {
 		bug = new(Bug)
 		if err := db.Get(c, bugKey, bug); err != nil {
@@ -868,10 +873,17 @@ func reportCrash(c context.Context, build *Build, req *dashapi.Crash) (*Bug, err
 		if len(req.Report) != 0 {
 			bug.HasReport = true
 		}
+		// Prefer frames from crashes with reproducers, they are less likely to be corrupted.
+		if len(stackFrames) != 0 && (len(bug.StackFrames) == 0 || reproLevel != ReproLevelNone) {
+			bug.StackFrames = stackFrames
+		}
 		if calculateSubsystems {
 			bug.SetAutoSubsystems(c, newSubsystems, now, getNsConfig(c, ns).Subsystems.Revision)
 		}
 		bug.increaseCrashStats(now)
+		if getNsConfig(c, ns).CrashRegressions != nil {
+			bug.increaseBuildCrashes(build.ID)
+		}
 		bug.HappenedOn = mergeString(bug.HappenedOn, build.Manager)
 		// Migration of older entities (for new bugs Title is always in MergedTitles).
 		bug.MergedTitles = mergeString(bug.MergedTitles, bug.Title)
@@ -1177,9 +1189,16 @@ func apiManagerStats(c context.Context, ns string, r *http.Request, payload []by
 	}
 	now := timeNow(c)
 	err := updateManager(c, ns, req.Name, func(mgr *Manager, stats *ManagerStats) error {
+		if mgr.CurrentBuild != "" && req.FuzzingTime != 0 && getNsConfig(c, ns).CrashRegressions != nil {
+			mgr.increaseBuildFuzzingTime(mgr.CurrentBuild, req.FuzzingTime)
+		}
 		mgr.Link = req.Addr
 		mgr.LastAlive = now
 		mgr.CurrentUpTime = req.UpTime
+		if req.MachineChecked {
+			// The previous diff describes an older check result.
+			mgr.MachineCheckDiff = req.MachineCheckDiff
+		}
 		if cur := int64(req.Corpus); cur > stats.MaxCorpus {
 			stats.MaxCorpus = cur
 		}
@@ -1204,7 +1223,10 @@ func apiManagerStats(c context.Context, ns string, r *http.Request, payload []by
 		}
 		return nil
 	})
-	return nil, err
+	if err != nil {
+		return nil, err
+	}
+	return nil, nil
 }
 
 func apiBugList(c context.Context, ns string, r *http.Request, payload []byte) (interface{}, error) {
@@ -1892,3 +1914,75 @@ func apiSaveCoverage(c context.Context, r *http.Request, payload []byte) (interf
 	}
 	return nil, err
 }
+
+func apiSeriesFindings(c context.Context, ns string, r *http.Request, payload []byte) (interface{}, error) {
+	req := new(dashapi.SeriesFindingsReq)
+	if err := json.Unmarshal(payload, req); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal request: %w", err)
+	}
+	cfg := getNsConfig(c, ns).SeriesFindings
+	if cfg == nil {
+		return nil, fmt.Errorf("series findings are not enabled for %v", ns)
+	}
+	if req.MessageID == "" || len(req.Findings) == 0 {
+		return nil, fmt.Errorf("no series thread or findings")
+	}
+	findings, err := newSeriesFindings(c, ns, req.Findings)
+	if err != nil || len(findings) == 0 {
+		return nil, err
+	}
+	req.Findings = findings
+	if err := emailSeriesFindings(c, cfg, req); err != nil {
+		return nil, err
+	}
+	now := timeNow(c)
+	var keys []*db.Key
+	var entities []*SeriesFinding
+	for _, finding := range findings {
+		keys = append(keys, seriesFindingKey(c, ns, finding.Title))
+		entities = append(entities, &SeriesFinding{
+			Namespace: ns,
+			Title:     finding.Title,
+			MessageID: req.MessageID,
+			Time:      now,
+		})
+	}
+	if _, err := db.PutMulti(c, keys, entities); err != nil {
+		return nil, fmt.Errorf("failed to save series findings: %w", err)
+	}
+	return nil, nil
+}
+
+// newSeriesFindings leaves only reproduced crashes that are neither known bugs nor were reported before.
+// The findings are not moderated, so we don't want to spam the series threads with flaky or known crashes.
+func newSeriesFindings(c context.Context, ns string, findings []dashapi.SeriesFinding) ([]dashapi.SeriesFinding, error) {
+	var ret []dashapi.SeriesFinding
+	for _, finding := range findings {
+		if len(finding.ReproSyz) == 0 {
+			continue
+		}
+		err := db.Get(c, seriesFindingKey(c, ns, finding.Title), new(SeriesFinding))
+		if err == nil {
+			log.Infof(c, "series finding %q was already reported", finding.Title)
+			continue
+		} else if err != db.ErrNoSuchEntity {
+			return nil, fmt.Errorf("failed to get series finding: %w", err)
+		}
+		bugs, err := db.NewQuery("Bug").
+			Filter("Namespace=", ns).
+			Filter("Title=", finding.Title).
+			Order("-Seq").
+			Limit(1).
+			KeysOnly().
+			GetAll(c, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query bugs: %w", err)
+		}
+		if len(bugs) != 0 {
+			log.Infof(c, "series finding %q is a known bug", finding.Title)
+			continue
+		}
+		ret = append(ret, finding)
+	}
+	return ret, nil
+}
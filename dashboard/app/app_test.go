@@ -46,6 +46,7 @@ var testConfig = &GlobalConfig{
 	Clients: map[string]string{
 		"reporting": "reportingkeyreportingkeyreportingkey",
 	},
+	XSRFKey: "xsrfkeyxsrfkeyxsrfkeyxsrfkey",
 	EmailBlocklist: []string{
 		"\"Bar\" <Blocked@Domain.com>",
 	},
@@ -66,6 +67,12 @@ var testConfig = &GlobalConfig{
 			Key:                   "test1keytest1keytest1key",
 			FixBisectionAutoClose: true,
 			SimilarityDomain:      testDomain,
+			CrashRegressions: &CrashRegressionsConfig{
+				MinFuzzingTime: time.Hour,
+				Config: &EmailConfig{
+					Email: "regressions@syzkaller.com",
+				},
+			},
 			Clients: map[string]string{
 				client1: password1,
 				"oauth": auth.OauthMagic + "111111122222222",
@@ -349,6 +356,31 @@ var testConfig = &GlobalConfig{
 				},
 			},
 		},
+		"webhook-reporting": {
+			AccessLevel: AccessPublic,
+			Key:         "webhookkeywebhookkeywebhookkey",
+			Clients: map[string]string{
+				clientWebhook: keyWebhook,
+			},
+			Repos: []KernelRepo{
+				{
+					URL:    "git://syzkaller.org/webhook.git",
+					Branch: "webhook",
+					Alias:  "webhook",
+				},
+			},
+			Reporting: []Reporting{
+				{
+					AccessLevel: AccessPublic,
+					Name:        "webhook-reporting1",
+					DailyLimit:  1000,
+					Config: &WebhookConfig{
+						URL:    "https://tracker.syzkaller.com/syzbot",
+						Secret: testWebhookSecret,
+					},
+				},
+			},
+		},
 		"fs-bugs-reporting": {
 			AccessLevel: AccessPublic,
 			Key:         "fspublickeypublickeypublickey",
@@ -620,6 +652,9 @@ const (
 	keySubsystemRemind    = "keySubsystemRemindkeySubsystemRemind"
 	clientTreeTests       = "clientTreeTestsclientTreeTests"
 	keyTreeTests          = "keyTreeTestskeyTreeTestskeyTreeTests"
+	clientWebhook         = "client-webhook"
+	keyWebhook            = "keyWebhookkeyWebhookkeyWebhook"
+	testWebhookSecret     = "webhooksecretwebhooksecret"
 
 	restrictedManager     = "restricted-manager"
 	noFixBisectionManager = "no-fix-bisection-manager"
@@ -14,6 +14,7 @@ import (
 
 	"github.com/google/syzkaller/dashboard/dashapi"
 	"github.com/google/syzkaller/pkg/email"
+	"github.com/stretchr/testify/assert"
 	db "google.golang.org/appengine/v2/datastore"
 )
 
@@ -1238,3 +1239,190 @@ func addBisectFixJob(c *Ctx, build *dashapi.Build) (*dashapi.JobPollResp, *dasha
 	c.expectTrue(len(dbBug.Commits) == 0)
 	return resp, done, jobID
 }
+
+// Test that authors of unconfirmed culprits are not mailed.
+func TestBisectCauseUnconfirmed(t *testing.T) {
+	c := NewCtx(t)
+	defer c.Close()
+
+	build := testBuild(1)
+	c.client2.UploadBuild(build)
+	crash := testCrashWithRepro(build, 1)
+	c.client2.ReportCrash(crash)
+	msg := c.client2.pollEmailBug()
+
+	pollResp := c.client2.pollJobs(build.Manager)
+	c.expectEQ(pollResp.Type, dashapi.JobBisectCause)
+	done := &dashapi.JobDoneReq{
+		ID:          pollResp.ID,
+		Build:       *build,
+		Log:         []byte("bisect log"),
+		CrashTitle:  "bisect crash title",
+		CrashLog:    []byte("bisect crash log"),
+		CrashReport: []byte("bisect crash report"),
+		Commits: []dashapi.Commit{
+			{
+				Hash:       "36e65cb4a0448942ec316b24d60446bbd5cc7827",
+				Title:      "kernel: add a bug",
+				Author:     "author@kernel.org",
+				AuthorName: "Author Kernelov",
+				CC:         []string{"reviewer1@kernel.org"},
+				Date:       time.Date(2000, 2, 9, 4, 5, 6, 7, time.UTC),
+			},
+		},
+		Flags: dashapi.BisectResultUnconfirmed,
+	}
+	done.Build.ID = pollResp.ID
+	c.expectOK(c.client2.JobDone(done))
+
+	{
+		msg := c.pollEmailBug()
+		c.expectTrue(strings.Contains(msg.Body, "syzbot has bisected this issue to:"))
+		c.expectTrue(strings.Contains(msg.Body, "Note: the crash is still reproduced with this commit reverted"))
+	}
+
+	c.incomingEmail(msg.Sender, "#syz upstream")
+	{
+		msg := c.pollEmailBug()
+		c.expectTrue(strings.Contains(msg.Body, "The issue was bisected to:"))
+		c.expectTrue(strings.Contains(msg.Body, "Note: the crash is still reproduced with this commit reverted"))
+		c.expectEQ(msg.To, []string{
+			"bugs@syzkaller.com",
+			"default@maintainers.com",
+		})
+	}
+}
+
+func TestBisectConfig(t *testing.T) {
+	c := NewCtx(t)
+	defer c.Close()
+
+	build := testBuild(1)
+	c.client.UploadBuild(build)
+	crash := testCrashWithRepro(build, 1)
+	c.client.ReportCrash(crash)
+	rep := c.client.pollBug()
+
+	// Config bisection is only started on request.
+	jobs := dashapi.ManagerJobs{BisectConfig: true}
+	pollResp := c.client.pollSpecificJobs(build.Manager, jobs)
+	c.expectEQ(pollResp.ID, "")
+
+	page, err := c.AuthGET(AccessAdmin, "/bug?extid="+rep.ID)
+	c.expectOK(err)
+	assert.Contains(t, string(page), "Find config options that enable the bug")
+	token := xsrfTokenRe.FindSubmatch(page)
+	if !assert.NotNil(t, token) {
+		return
+	}
+
+	// The form must come from the dashboard.
+	form := map[string][]string{"bisect_config": {"1"}}
+	_, err = c.POSTForm("/bug?extid="+rep.ID, form)
+	expectFailureStatus(t, err, http.StatusForbidden)
+
+	form["xsrf"] = []string{string(token[1])}
+	_, err = c.POSTForm("/bug?extid="+rep.ID, form)
+	expectFailureStatus(t, err, http.StatusSeeOther)
+	// There can be only one pending config bisection.
+	_, err = c.POSTForm("/bug?extid="+rep.ID, form)
+	c.expectBadReqest(err)
+
+	pollResp = c.client.pollSpecificJobs(build.Manager, jobs)
+	c.expectNE(pollResp.ID, "")
+	c.expectEQ(pollResp.Type, dashapi.JobBisectConfig)
+	c.expectEQ(pollResp.KernelConfig, build.KernelConfig)
+
+	done := &dashapi.JobDoneReq{
+		ID:            pollResp.ID,
+		Build:         *build,
+		Log:           []byte("bisect log"),
+		CrashTitle:    crash.Title,
+		CrashLog:      []byte("bisect crash log"),
+		CrashReport:   []byte("bisect crash report"),
+		ConfigOptions: []string{"CONFIG_KASAN=y", "CONFIG_FOO=y"},
+	}
+	done.Build.ID = pollResp.ID
+	done.Build.KernelConfig = []byte("CONFIG_KASAN=y\nCONFIG_FOO=y\n")
+	c.expectOK(c.client.JobDone(done))
+	c.client.pollNotifs(0)
+
+	// Config bisection does not affect the bug bisection status.
+	bug, _, _ := c.loadBug(rep.ID)
+	c.expectEQ(bug.BisectCause, BisectNot)
+
+	page, err = c.AuthGET(AccessAdmin, "/bug?extid="+rep.ID)
+	c.expectOK(err)
+	assert.Contains(t, string(page), "Config bisections")
+	assert.Contains(t, string(page), "CONFIG_KASAN=y CONFIG_FOO=y")
+}
+
+func TestFixSearch(t *testing.T) {
+	c := NewCtx(t)
+	defer c.Close()
+
+	build := testBuild(1)
+	c.client.UploadBuild(build)
+	crash := testCrashWithRepro(build, 1)
+	crash.Title = "KASAN: slab-use-after-free Read in ext4_xattr_inode_dec_ref"
+	crash.Report = []byte(testKASANReport)
+	crash.GuiltyFiles = []string{"fs/ext4/xattr.c"}
+	c.client.ReportCrash(crash)
+	rep := c.client.pollBug()
+
+	// The bug is too new, there are no new commits to search yet.
+	jobs := dashapi.ManagerJobs{FixSearch: true}
+	pollResp := c.client.pollSpecificJobs(build.Manager, jobs)
+	c.expectEQ(pollResp.ID, "")
+
+	c.advanceTime(15 * 24 * time.Hour)
+	pollResp = c.client.pollSpecificJobs(build.Manager, jobs)
+	c.expectNE(pollResp.ID, "")
+	c.expectEQ(pollResp.Type, dashapi.JobFixSearch)
+	c.expectEQ(pollResp.KernelCommit, build.KernelCommit)
+	c.expectEQ(pollResp.CrashTitle, crash.Title)
+	c.expectEQ(pollResp.GuiltyFile, "fs/ext4/xattr.c")
+	c.expectEQ(pollResp.StackFrames[0], "ext4_xattr_inode_dec_ref")
+
+	done := &dashapi.JobDoneReq{
+		ID:    pollResp.ID,
+		Build: *build,
+		Log:   []byte("fix search log"),
+		Commits: []dashapi.Commit{
+			{
+				Hash:       "46e65cb4a0448942ec316b24d60446bbd5cc7827",
+				Title:      "ext4: fix use-after-free in ext4_xattr_inode_dec_ref",
+				Author:     "author@kernel.org",
+				AuthorName: "Author Kernelov",
+				Date:       time.Date(2000, 2, 9, 4, 5, 6, 7, time.UTC),
+			},
+		},
+	}
+	done.Build.ID = pollResp.ID
+	c.expectOK(c.client.JobDone(done))
+	c.client.pollNotifs(0)
+
+	// The search is not repeated right away.
+	c.advanceTime(24 * time.Hour)
+	pollResp = c.client.pollSpecificJobs(build.Manager, jobs)
+	c.expectEQ(pollResp.ID, "")
+
+	// The found commit is proposed as the fix candidate.
+	info, err := c.client.LoadFullBug(&dashapi.LoadFullBugReq{BugID: rep.ID})
+	c.expectOK(err)
+	if assert.NotNil(t, info.FixCandidate) && assert.NotNil(t, info.FixCandidate.BisectFix) {
+		assert.Equal(t, "ext4: fix use-after-free in ext4_xattr_inode_dec_ref",
+			info.FixCandidate.BisectFix.Commit.Title)
+	}
+
+	page, err := c.AuthGET(AccessAdmin, "/bug?extid="+rep.ID)
+	c.expectOK(err)
+	assert.Contains(t, string(page), "Fix searches")
+	assert.Contains(t, string(page), "Fix candidate: found among commits modifying the guilty function")
+	assert.Contains(t, string(page), "ext4: fix use-after-free in ext4_xattr_inode_dec_ref")
+
+	// The bug has a fix candidate, so there are no more searches.
+	c.advanceTime(30 * 24 * time.Hour)
+	pollResp = c.client.pollSpecificJobs(build.Manager, jobs)
+	c.expectEQ(pollResp.ID, "")
+}
@@ -64,6 +64,9 @@ type GlobalConfig struct {
 	DiscussionEmails []DiscussionEmailConfig
 	// Incoming request throttling.
 	Throttle ThrottleConfig
+	// Secret key for XSRF tokens of the admin forms on the web pages (e.g. marking a bug as a duplicate).
+	// The forms are not shown if the key is not set.
+	XSRFKey string
 }
 
 // Per-namespace config.
@@ -99,6 +102,12 @@ type Config struct {
 	RetestMissingBackports bool
 	// If set, dashboard will create patch testing jobs to determine bug origin trees.
 	FindBugOriginTrees bool
+	// If set, dashboard will periodically run reproducers on the HEAD of all Repos
+	// to find out which of the kernel trees are affected by each bug.
+	TrackAffectedTrees bool
+	// If set, crashes found by syz-ci while fuzzing proposed patch series
+	// are sent as replies to the series threads using this email config.
+	SeriesFindings *EmailConfig
 	// Managers contains some special additional info about syz-manager instances.
 	Managers map[string]ConfigManager
 	// Reporting config.
@@ -123,6 +132,22 @@ type Config struct {
 	CacheUIPages bool
 	// Enables coverage aggregation.
 	Coverage *CoverageConfig
+	// If set, the dashboard detects crash rate regressions on new kernel builds.
+	CrashRegressions *CrashRegressionsConfig
+}
+
+// CrashRegressionsConfig describes detection of crash rate regressions.
+// Crash rates of each bug (and the total crash rate) on the current build of each manager
+// are compared with the rates on the previous builds of the same manager.
+type CrashRegressionsConfig struct {
+	// Report regressions only if the crash rate grows at least MinRatio times (10 by default).
+	MinRatio float64
+	// Both the current build and the previous builds need at least MinFuzzingTime
+	// of total VM fuzzing time to be compared (24h by default).
+	MinFuzzingTime time.Duration
+	// If set, detected regressions are sent there.
+	// For now, only EmailConfig is supported.
+	Config ReportingType
 }
 
 const defaultDashboardClientName = "coverage-merger"
@@ -250,7 +275,8 @@ type Reporting struct {
 	// Upstream reports into next reporting after this period.
 	Embargo time.Duration
 	// Type of reporting and its configuration.
-	// The app has one built-in type, EmailConfig, which reports bugs by email.
+	// The app has two built-in types: EmailConfig, which reports bugs by email,
+	// and WebhookConfig, which POSTs reports to an HTTP endpoint and accepts commands back.
 	// And ExternalConfig which can be used to attach any external reporting system (e.g. Bugzilla).
 	Config ReportingType
 	// List of labels to notify about (keys are strings of form "label:value").
@@ -389,6 +415,7 @@ func installConfig(cfg *GlobalConfig) {
 		marshaledConfig = cfg.marshalJSON()
 	}
 	initEmailReporting()
+	initWebhookReporting()
 	initHTTPHandlers()
 	initAPIHandlers()
 	initKcidb()
@@ -529,9 +556,43 @@ func checkNamespace(ns string, cfg *Config, namespaces, clientNames map[string]b
 	if cfg.Kcidb != nil {
 		checkKcidb(ns, cfg.Kcidb)
 	}
+	if cfg.SeriesFindings != nil {
+		if err := cfg.SeriesFindings.Validate(); err != nil {
+			panic(fmt.Sprintf("%v: series findings: %v", ns, err))
+		}
+	}
 	checkKernelRepos(ns, cfg, cfg.Repos)
 	checkNamespaceReporting(ns, cfg)
 	checkSubsystems(ns, cfg)
+	checkCrashRegressions(ns, cfg)
+}
+
+func checkCrashRegressions(ns string, cfg *Config) {
+	regs := cfg.CrashRegressions
+	if regs == nil {
+		return
+	}
+	if regs.MinRatio == 0 {
+		regs.MinRatio = 10
+	}
+	if regs.MinRatio <= 1 {
+		panic(fmt.Sprintf("%v: CrashRegressions.MinRatio must be > 1", ns))
+	}
+	if regs.MinFuzzingTime == 0 {
+		regs.MinFuzzingTime = 24 * time.Hour
+	}
+	if regs.MinFuzzingTime < 0 {
+		panic(fmt.Sprintf("%v: CrashRegressions.MinFuzzingTime must be > 0", ns))
+	}
+	if regs.Config == nil {
+		return
+	}
+	if _, ok := regs.Config.(*EmailConfig); !ok {
+		panic(fmt.Sprintf("%v: CrashRegressions.Config must be EmailConfig", ns))
+	}
+	if err := regs.Config.Validate(); err != nil {
+		panic(fmt.Sprintf("%v: CrashRegressions.Config: %v", ns, err))
+	}
 }
 
 func checkSubsystems(ns string, cfg *Config) {
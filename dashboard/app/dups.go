@@ -0,0 +1,358 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/google/syzkaller/dashboard/dashapi"
+	"google.golang.org/appengine/v2"
+	db "google.golang.org/appengine/v2/datastore"
+	"google.golang.org/appengine/v2/log"
+)
+
+// Bugs are merged only by exact titles, but the same root cause may manifest as crashes
+// with different titles (e.g. a WARNING and a KASAN report, or different inlined callers).
+// To help catching such cases, we periodically compare top stack frames of open bugs
+// and suggest the most similar ones as possible duplicates on the bug page.
+
+const (
+	maxStackFrames   = 12
+	minDupSimilarity = 0.4
+	minDupFrames     = 2
+	maxPossibleDups  = 5
+)
+
+var (
+	// Matches both "func+0x12/0x34 file.c:56" and "func file.c:56 [inline]" frames.
+	stackFrameRe = regexp.MustCompile(`^\s*(?:\[<?[0-9a-f]+>?\]\s*)?(?:\? )?([a-zA-Z0-9_.]+)` +
+		`(?:\+0x[0-9a-f]+/0x[0-9a-f]+| [a-zA-Z0-9/_.-]+:[0-9]+ \[inline\])`)
+	// Frames of the error reporting machinery, they are not related to the root cause.
+	skipStackFrameRe = regexp.MustCompile(`^(dump_stack|__dump_stack|show_stack|print_|printk|_printk|` +
+		`kasan_|__kasan_|__asan_|kmsan_|__msan_|ubsan_|__ubsan_|__warn|warn_|report_bug|handle_bug|` +
+		`exc_|asm_exc_|panic|check_panic_on_warn|__might_resched|__might_sleep|__might_fault|` +
+		`check_memory_region|kcsan_|__tsan_|__sanitizer_)`)
+	// Frames that are common to lots of unrelated stacks, we stop at them.
+	lastStackFrameRe = regexp.MustCompile(`^(do_syscall_64|do_syscall_x64|x64_sys_call|entry_SYSCALL|` +
+		`el0_svc|invoke_syscall|ret_from_fork|kthread|worker_thread|process_one_work|` +
+		`do_softirq|__do_softirq|handle_softirqs|irq_exit)`)
+)
+
+// reportStackFrames extracts top frames of the first stack trace in a crash report.
+func reportStackFrames(report []byte) []string {
+	var frames []string
+	started := false
+	for s := bufio.NewScanner(bytes.NewReader(report)); s.Scan(); {
+		match := stackFrameRe.FindSubmatch(s.Bytes())
+		if match == nil {
+			if started && len(bytes.TrimSpace(s.Bytes())) == 0 {
+				break
+			}
+			continue
+		}
+		started = true
+		// Strip compiler-generated suffixes like .constprop.0 and .isra.0.
+		frame, _, _ := strings.Cut(string(match[1]), ".")
+		if lastStackFrameRe.MatchString(frame) {
+			break
+		}
+		if skipStackFrameRe.MatchString(frame) || frame == "" ||
+			len(frames) != 0 && frames[len(frames)-1] == frame {
+			continue
+		}
+		frames = append(frames, frame)
+		if len(frames) == maxStackFrames {
+			break
+		}
+	}
+	return frames
+}
+
+// stackSimilarity returns similarity of two stacks in the [0, 1] range and the common frames.
+// It's a weighted Jaccard index where frames closer to the top have higher weight.
+func stackSimilarity(a, b []string) (float64, []string) {
+	weights := func(frames []string) map[string]float64 {
+		res := make(map[string]float64)
+		for i, frame := range frames {
+			if _, ok := res[frame]; !ok {
+				res[frame] = 1 / float64(i+1)
+			}
+		}
+		return res
+	}
+	wa, wb := weights(a), weights(b)
+	var common []string
+	var intersection, union float64
+	for _, frame := range a {
+		if wb[frame] != 0 && !stringInList(common, frame) {
+			common = append(common, frame)
+		}
+	}
+	for frame, w := range wa {
+		intersection += min(w, wb[frame])
+		union += max(w, wb[frame])
+	}
+	for frame, w := range wb {
+		if wa[frame] == 0 {
+			union += w
+		}
+	}
+	if union == 0 {
+		return 0, nil
+	}
+	return intersection / union, common
+}
+
+type uiPossibleDups struct {
+	Bugs []*uiPossibleDup
+	// XSRFToken is set if the user can mark the bug as a duplicate.
+	XSRFToken string
+}
+
+type uiPossibleDup struct {
+	Bug        *uiBug
+	ID         string
+	Similarity int // in percents
+	Frames     []string
+}
+
+func loadPossibleDupsUI(c context.Context, bug *Bug, state *ReportingState, managers []string,
+	accessLevel AccessLevel) (*uiPossibleDups, error) {
+	ret := &uiPossibleDups{}
+	if accessLevel == AccessAdmin && lastReportedReporting(bug) != nil {
+		ret.XSRFToken = xsrfToken(c, xsrfBugAction)
+	}
+	if bug.Status != BugStatusOpen || bug.DupOf != "" || len(bug.PossibleDups) == 0 {
+		return ret, nil
+	}
+	var keys []*db.Key
+	for _, hash := range bug.PossibleDups {
+		keys = append(keys, db.NewKey(c, "Bug", hash, 0, nil))
+	}
+	bugs := make([]*Bug, len(keys))
+	if err := db.GetMulti(c, keys, bugs); err != nil {
+		var merr appengine.MultiError
+		if !errors.As(err, &merr) {
+			return nil, fmt.Errorf("failed to load possible dups: %w", err)
+		}
+		// Some of the bugs may be already deleted.
+		for i, err := range merr {
+			if err != nil && !errors.Is(err, db.ErrNoSuchEntity) {
+				return nil, fmt.Errorf("failed to load possible dups: %w", err)
+			}
+			if err != nil {
+				bugs[i] = nil
+			}
+		}
+	}
+	for i, candidate := range bugs {
+		// The list is updated periodically, so the bugs may have changed since then.
+		if candidate == nil || candidate.Status != BugStatusOpen || candidate.DupOf != "" ||
+			accessLevel < candidate.sanitizeAccess(c, accessLevel) {
+			continue
+		}
+		similarity, frames := stackSimilarity(bug.StackFrames, candidate.StackFrames)
+		ret.Bugs = append(ret.Bugs, &uiPossibleDup{
+			Bug:        createUIBug(c, candidate, state, managers),
+			ID:         keys[i].StringID(),
+			Similarity: int(similarity * 100),
+			Frames:     frames,
+		})
+	}
+	return ret, nil
+}
+
+const xsrfBugAction = "bug"
+
+func handleUpdatePossibleDups(w http.ResponseWriter, r *http.Request) {
+	c := appengine.NewContext(r)
+	for ns := range getConfig(c).Namespaces {
+		if err := updatePossibleDups(c, ns); err != nil {
+			log.Errorf(c, "%v: failed to update possible dups: %v", ns, err)
+		}
+	}
+}
+
+// updatePossibleDups compares stacks of all open bugs of the namespace and saves the most similar
+// ones in Bug.PossibleDups. It also extracts StackFrames of older bugs from their crash reports.
+func updatePossibleDups(c context.Context, ns string) error {
+	bugs, keys, err := loadAllBugs(c, func(query *db.Query) *db.Query {
+		return query.Filter("Namespace=", ns).
+			Filter("Status=", BugStatusOpen)
+	})
+	if err != nil {
+		return err
+	}
+	frames, err := backfillStackFrames(c, bugs)
+	if err != nil {
+		return err
+	}
+	dups := findPossibleDups(bugs, keys, frames)
+	for i, bug := range bugs {
+		if frames[i] == nil && slices.Equal(bug.PossibleDups, dups[i]) {
+			continue
+		}
+		if err := savePossibleDups(c, keys[i], frames[i], dups[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillStackFrames extracts stack frames for bugs that were created before the frames
+// were saved on crash reporting. It returns the new frames, nil if the bug frames are not changed.
+func backfillStackFrames(c context.Context, bugs []*Bug) ([][]string, error) {
+	const maxBackfill = 100
+	frames := make([][]string, len(bugs))
+	var missing []int
+	for i, bug := range bugs {
+		if len(bug.StackFrames) == 0 && bug.HasReport && bug.DupOf == "" {
+			missing = append(missing, i)
+		}
+	}
+	// Some reports don't have stacks at all, shuffle to not retry the same bugs every time.
+	rand.Shuffle(len(missing), func(i, j int) { missing[i], missing[j] = missing[j], missing[i] })
+	if len(missing) > maxBackfill {
+		missing = missing[:maxBackfill]
+	}
+	for _, i := range missing {
+		crash, _, err := findCrashForBug(c, bugs[i])
+		if err != nil {
+			return nil, err
+		}
+		report, _, err := getText(c, textCrashReport, crash.Report)
+		if err != nil {
+			return nil, err
+		}
+		frames[i] = reportStackFrames(report)
+		if len(frames[i]) == 0 {
+			frames[i] = nil
+		}
+	}
+	return frames, nil
+}
+
+// findPossibleDups returns key hashes of bugs with the most similar stacks for each bug.
+// Only bugs that share at least minDupFrames frames are compared.
+func findPossibleDups(bugs []*Bug, keys []*db.Key, newFrames [][]string) [][]string {
+	frames := make([][]string, len(bugs))
+	index := make(map[string][]int)
+	for i, bug := range bugs {
+		frames[i] = bug.StackFrames
+		if newFrames[i] != nil {
+			frames[i] = newFrames[i]
+		}
+		if bug.DupOf != "" {
+			continue
+		}
+		for _, frame := range uniqueFrames(frames[i]) {
+			index[frame] = append(index[frame], i)
+		}
+	}
+	dups := make([][]string, len(bugs))
+	for i, bug := range bugs {
+		if bug.DupOf != "" {
+			continue
+		}
+		shared := make(map[int]int)
+		for _, frame := range uniqueFrames(frames[i]) {
+			for _, j := range index[frame] {
+				if j != i {
+					shared[j]++
+				}
+			}
+		}
+		type candidate struct {
+			hash       string
+			similarity float64
+		}
+		var candidates []candidate
+		for j, n := range shared {
+			if n < minDupFrames {
+				continue
+			}
+			similarity, _ := stackSimilarity(frames[i], frames[j])
+			if similarity < minDupSimilarity {
+				continue
+			}
+			candidates = append(candidates, candidate{keys[j].StringID(), similarity})
+		}
+		sort.Slice(candidates, func(a, b int) bool {
+			if candidates[a].similarity != candidates[b].similarity {
+				return candidates[a].similarity > candidates[b].similarity
+			}
+			return candidates[a].hash < candidates[b].hash
+		})
+		if len(candidates) > maxPossibleDups {
+			candidates = candidates[:maxPossibleDups]
+		}
+		for _, candidate := range candidates {
+			dups[i] = append(dups[i], candidate.hash)
+		}
+	}
+	return dups
+}
+
+func uniqueFrames(frames []string) []string {
+	var ret []string
+	for _, frame := range frames {
+		if !stringInList(ret, frame) {
+			ret = append(ret, frame)
+		}
+	}
+	return ret
+}
+
+func savePossibleDups(c context.Context, bugKey *db.Key, frames, dups []string) error {
+	tx := func(c context.Context) error {
+		bug := new(Bug)
+		if err := db.Get(c, bugKey, bug); err != nil {
+			return fmt.Errorf("failed to get bug: %w", err)
+		}
+		if len(bug.StackFrames) == 0 {
+			bug.StackFrames = frames
+		}
+		bug.PossibleDups = dups
+		if _, err := db.Put(c, bugKey, bug); err != nil {
+			return fmt.Errorf("failed to put bug: %w", err)
+		}
+		return nil
+	}
+	return db.RunInTransaction(c, tx, nil)
+}
+
+// markBugDup marks the bug as a duplicate of the bug with the given key hash
+// via the same path as the "#syz dup" command.
+func markBugDup(c context.Context, bug *Bug, dupHash string) error {
+	dup := new(Bug)
+	if err := db.Get(c, db.NewKey(c, "Bug", dupHash, 0, nil), dup); err != nil {
+		return fmt.Errorf("%w: failed to load the dup bug: %w", ErrClientBadRequest, err)
+	}
+	bugReporting, dupReporting := lastReportedReporting(bug), lastReportedReporting(dup)
+	if bugReporting == nil || dupReporting == nil {
+		return fmt.Errorf("%w: the bugs are not reported yet", ErrClientBadRequest)
+	}
+	ok, reason, err := incomingCommand(c, &dashapi.BugUpdate{
+		ID:     bugReporting.ID,
+		Status: dashapi.BugStatusDup,
+		DupOf:  dupReporting.ID,
+	})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: %v", ErrClientBadRequest, reason)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/syzkaller/dashboard/dashapi"
+	"github.com/stretchr/testify/assert"
+	db "google.golang.org/appengine/v2/datastore"
+)
+
+var xsrfTokenRe = regexp.MustCompile(`name="xsrf" value="([^"]+)"`)
+
+const testKASANReport = `==================================================================
+BUG: KASAN: slab-use-after-free in ext4_xattr_inode_dec_ref fs/ext4/xattr.c:1052 [inline]
+Read of size 4 at addr ffff88807a4c3b10 by task syz-executor/5120
+
+CPU: 1 PID: 5120 Comm: syz-executor Not tainted 6.8.0-syzkaller #0
+Call Trace:
+ <TASK>
+ __dump_stack lib/dump_stack.c:88 [inline]
+ dump_stack_lvl+0x1e7/0x2e0 lib/dump_stack.c:106
+ print_address_description mm/kasan/report.c:377 [inline]
+ print_report+0x169/0x550 mm/kasan/report.c:488
+ kasan_report+0x143/0x180 mm/kasan/report.c:601
+ ext4_xattr_inode_dec_ref fs/ext4/xattr.c:1052 [inline]
+ ext4_xattr_inode_dec_ref_all.constprop.0+0x6f1/0x9b0 fs/ext4/xattr.c:1182
+ ext4_xattr_delete_inode+0x3b2/0x7c0 fs/ext4/xattr.c:2938
+ ext4_evict_inode+0xe47/0x1490 fs/ext4/inode.c:289
+ evict+0x2a8/0x630 fs/inode.c:667
+ do_unlinkat+0x512/0x830 fs/namei.c:4426
+ __do_sys_unlink fs/namei.c:4474 [inline]
+ __se_sys_unlink fs/namei.c:4472 [inline]
+ __x64_sys_unlink+0x49/0x50 fs/namei.c:4472
+ do_syscall_64+0xfb/0x240
+ entry_SYSCALL_64_after_hwframe+0x6d/0x75
+ </TASK>
+
+Allocated by task 5120:
+ kasan_save_stack mm/kasan/common.c:47 [inline]
+ kmem_cache_alloc+0x136/0x320 mm/slub.c:3813
+`
+
+func TestReportStackFrames(t *testing.T) {
+	assert.Equal(t, []string{
+		"ext4_xattr_inode_dec_ref",
+		"ext4_xattr_inode_dec_ref_all",
+		"ext4_xattr_delete_inode",
+		"ext4_evict_inode",
+		"evict",
+		"do_unlinkat",
+		"__do_sys_unlink",
+		"__se_sys_unlink",
+		"__x64_sys_unlink",
+	}, reportStackFrames([]byte(testKASANReport)))
+	assert.Empty(t, reportStackFrames([]byte("no stack here\n")))
+}
+
+func TestStackSimilarity(t *testing.T) {
+	a := []string{"foo", "bar", "baz", "qux"}
+	similarity, frames := stackSimilarity(a, a)
+	assert.Equal(t, 1.0, similarity)
+	assert.Equal(t, a, frames)
+
+	similarity, frames = stackSimilarity(a, []string{"x", "y", "z"})
+	assert.Equal(t, 0.0, similarity)
+	assert.Empty(t, frames)
+
+	// An additional inlined frame on top should still give high similarity.
+	similarity, frames = stackSimilarity(a, []string{"inlined", "foo", "bar", "baz", "qux"})
+	assert.Greater(t, similarity, minDupSimilarity)
+	assert.Equal(t, a, frames)
+
+	// Matching only the bottom frames gives low similarity.
+	similarity, _ = stackSimilarity(a, []string{"x", "y", "baz", "qux"})
+	assert.Less(t, similarity, minDupSimilarity)
+
+	similarity, _ = stackSimilarity(nil, nil)
+	assert.Equal(t, 0.0, similarity)
+}
+
+func TestPossibleDups(t *testing.T) {
+	c := NewCtx(t)
+	defer c.Close()
+
+	build := testBuild(1)
+	c.client.UploadBuild(build)
+
+	crash1 := testCrash(build, 1)
+	crash1.Title = "KASAN: slab-use-after-free Read in ext4_xattr_inode_dec_ref_all"
+	crash1.Report = []byte(testKASANReport)
+	c.client.ReportCrash(crash1)
+	rep1 := c.client.pollBug()
+
+	crash2 := testCrash(build, 2)
+	crash2.Title = "WARNING in ext4_xattr_inode_dec_ref_all"
+	crash2.Report = []byte(strings.Replace(testKASANReport,
+		" kasan_report+0x143/0x180 mm/kasan/report.c:601\n",
+		" __warn+0x1e7/0x2e0 kernel/panic.c:700\n", 1))
+	c.client.ReportCrash(crash2)
+	rep2 := c.client.pollBug()
+
+	crash3 := testCrash(build, 3)
+	crash3.Title = "unrelated bug"
+	crash3.Report = []byte("Call Trace:\n foo+0x1/0x2 a.c:1\n bar+0x1/0x2 a.c:2\n")
+	c.client.ReportCrash(crash3)
+	c.client.pollBug()
+
+	// Simulate a bug that was created before stack frames were saved.
+	bug1, _, _ := c.loadBug(rep1.ID)
+	bug1.StackFrames = nil
+	_, err := db.Put(c.ctx, bug1.key(c.ctx), bug1)
+	c.expectOK(err)
+
+	// The possible dups are calculated periodically.
+	page, err := c.AuthGET(AccessAdmin, "/bug?extid="+rep2.ID)
+	c.expectOK(err)
+	assert.NotContains(t, string(page), "Possible duplicates")
+
+	_, err = c.GET("/cron/possible_dups")
+	c.expectOK(err)
+	bug1, _, _ = c.loadBug(rep1.ID)
+	assert.Equal(t, reportStackFrames([]byte(testKASANReport)), bug1.StackFrames)
+	bug2, _, _ := c.loadBug(rep2.ID)
+	assert.Equal(t, []string{bug1.keyHash(c.ctx)}, bug2.PossibleDups)
+	assert.Equal(t, []string{bug2.keyHash(c.ctx)}, bug1.PossibleDups)
+
+	page, err = c.AuthGET(AccessAdmin, "/bug?extid="+rep2.ID)
+	c.expectOK(err)
+	assert.Contains(t, string(page), "Possible duplicates (1)")
+	assert.Contains(t, string(page), crash1.Title)
+	assert.NotContains(t, string(page), crash3.Title)
+	token := xsrfTokenRe.FindSubmatch(page)
+	if !assert.NotNil(t, token) {
+		return
+	}
+
+	// The form must come from the dashboard.
+	_, err = c.POSTForm("/bug?extid="+rep2.ID, map[string][]string{"dup_of": {bug1.keyHash(c.ctx)}})
+	var httpErr *HTTPError
+	c.expectTrue(errors.As(err, &httpErr))
+	c.expectEQ(httpErr.Code, http.StatusForbidden)
+
+	_, err = c.POSTForm("/bug?extid="+rep2.ID, map[string][]string{
+		"dup_of": {bug1.keyHash(c.ctx)},
+		"xsrf":   {string(token[1])},
+	})
+	c.expectTrue(errors.As(err, &httpErr))
+	c.expectEQ(httpErr.Code, http.StatusSeeOther)
+	bug2, _, _ = c.loadBug(rep2.ID)
+	c.expectEQ(bug2.Status, BugStatusDup)
+	c.expectEQ(bug2.DupOf, bug1.keyHash(c.ctx))
+
+	c.client.updateBug(rep1.ID, dashapi.BugStatusInvalid, "")
+}
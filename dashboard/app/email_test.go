@@ -1403,3 +1403,66 @@ Author: default@sender.com
 #syz fix: some: commit title
 `)
 }
+
+func TestSeriesFindings(t *testing.T) {
+	c := NewCtx(t)
+	defer c.Close()
+
+	req := &dashapi.SeriesFindingsReq{
+		Manager:      "mgr",
+		MessageID:    "<series-0>",
+		Subject:      "[PATCH v2 0/2] net: fix things",
+		Cc:           []string{"author@kernel.org", "netdev@vger.kernel.org"},
+		KernelRepo:   "git://syzkaller.org",
+		KernelBranch: "branch10",
+		BaseCommit:   "1111111111111111111111111111111111111111",
+		Findings: []dashapi.SeriesFinding{
+			{Title: "KASAN: use-after-free in foo", Report: []byte("foo report"), ReproSyz: []byte("foo repro")},
+			// Not reproduced.
+			{Title: "WARNING in bar", Report: []byte("bar report")},
+		},
+	}
+	// The reporting is not enabled by default.
+	err := c.client2.ReportSeriesFindings(req)
+	assert.Error(t, err)
+
+	c.transformContext = func(c context.Context) context.Context {
+		newConfig := replaceNamespaceConfig(c, "test2", func(cfg *Config) *Config {
+			ret := *cfg
+			ret.SeriesFindings = &EmailConfig{
+				Email:         "series@syzkaller.com",
+				SubjectPrefix: "[syzbot]",
+			}
+			return &ret
+		})
+		return contextWithConfig(c, newConfig)
+	}
+	c.expectOK(c.client2.ReportSeriesFindings(req))
+	msg := c.pollEmailBug()
+	c.expectEQ(msg.Sender, fromAddr(c.ctx))
+	c.expectEQ(msg.To, []string{"author@kernel.org", "netdev@vger.kernel.org", "series@syzkaller.com"})
+	c.expectEQ(msg.Subject, "Re: [PATCH v2 0/2] net: fix things")
+	c.expectEQ(msg.Headers["In-Reply-To"], []string{"<series-0>"})
+	assert.Contains(t, msg.Body, "base commit:    1111111111111111111111111111111111111111")
+	assert.Contains(t, msg.Body, "KASAN: use-after-free in foo\n\nfoo report\n")
+	assert.Contains(t, msg.Body, "foo repro")
+	assert.NotContains(t, msg.Body, "WARNING in bar")
+
+	// The same crash is reported only once.
+	req.MessageID = "<series-1>"
+	c.expectOK(c.client2.ReportSeriesFindings(req))
+	c.expectNoEmail()
+
+	// Crashes that are known bugs are not reported.
+	build := testBuild(1)
+	c.client2.UploadBuild(build)
+	crash := testCrash(build, 1)
+	crash.Title = "KASAN: use-after-free in baz"
+	c.client2.ReportCrash(crash)
+	c.pollEmailBug()
+	req.Findings = []dashapi.SeriesFinding{
+		{Title: crash.Title, Report: []byte("baz report"), ReproSyz: []byte("baz repro")},
+	}
+	c.expectOK(c.client2.ReportSeriesFindings(req))
+	c.expectNoEmail()
+}
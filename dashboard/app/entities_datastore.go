@@ -36,6 +36,15 @@ type Manager struct {
 	LastAlive         time.Time
 	CurrentUpTime     time.Duration
 	LastGeneratedJob  time.Time
+	// The machine check diff reported after the last machine check (changes in enabled syscalls/features).
+	MachineCheckDiff string `datastore:",noindex"`
+	// Fuzzing time on the latest builds of the manager (collected only if CrashRegressions are enabled).
+	BuildFuzzingTimes []ManagerBuildFuzzingTime `datastore:",noindex"`
+}
+
+type ManagerBuildFuzzingTime struct {
+	BuildID     string
+	FuzzingTime time.Duration
 }
 
 // ManagerStats holds per-day manager runtime stats.
@@ -83,6 +92,36 @@ type Build struct {
 	AssetsLastCheck     time.Time // the last time we checked the assets for deprecation
 }
 
+// CrashRegression is a statistically significant crash rate increase on a build
+// compared to the previous builds of the same manager.
+type CrashRegression struct {
+	Namespace       string
+	Manager         string
+	BuildID         string
+	BugKey          string        // hash of the bug key, empty for the total crash rate
+	Title           string        `datastore:",noindex"`
+	Crashes         int64         `datastore:",noindex"`
+	FuzzingTime     time.Duration `datastore:",noindex"`
+	BaseBuilds      []string      `datastore:",noindex"`
+	BaseCrashes     int64         `datastore:",noindex"`
+	BaseFuzzingTime time.Duration `datastore:",noindex"`
+	PValue          float64       `datastore:",noindex"`
+	Time            time.Time
+}
+
+// SeriesFinding is a crash found while fuzzing a patch series that was reported to the series thread.
+// It's used to report each crash only once per namespace.
+type SeriesFinding struct {
+	Namespace string
+	Title     string
+	MessageID string `datastore:",noindex"`
+	Time      time.Time
+}
+
+func seriesFindingKey(c context.Context, ns, title string) *db.Key {
+	return db.NewKey(c, "SeriesFinding", hash.String([]byte(fmt.Sprintf("%v-%v", ns, title))), 0, nil)
+}
+
 type Bug struct {
 	Namespace    string
 	Seq          int64 // sequences of the bug with the same title
@@ -107,6 +146,7 @@ type Bug struct {
 	LastSavedCrash  time.Time
 	LastReproTime   time.Time
 	LastCauseBisect time.Time
+	LastFixSearch   time.Time
 	FixTime         time.Time // when we become aware of the fixing commit
 	LastActivity    time.Time // last time we observed any activity related to the bug
 	Closed          time.Time
@@ -129,6 +169,17 @@ type Bug struct {
 	// FixCandidateJob holds the key of the latest successful cross-tree fix bisection job.
 	FixCandidateJob string
 	ReproAttempts   []BugReproAttempt
+	// StackFrames are the top stack frames from a crash report of the bug,
+	// they are used to find possible duplicates with different titles.
+	StackFrames []string `datastore:",noindex"`
+	// PossibleDups are key hashes of open bugs with the most similar stacks.
+	// They are recalculated periodically by /cron/possible_dups.
+	PossibleDups []string `datastore:",noindex"`
+	// Number of crashes on the latest builds the bug happened on (collected only if CrashRegressions are enabled).
+	BuildCrashes []BugBuildCrashes `datastore:",noindex"`
+	// WebhookPending is set if the bug state differs from the state last sent
+	// to one of its webhook reportings. It's recalculated on every save.
+	WebhookPending bool
 }
 
 type BugTreeTestInfo struct {
@@ -275,9 +326,15 @@ func (bug *Bug) Load(origProps []db.Property) error {
 }
 
 func (bug *Bug) Save() ([]db.Property, error) {
+	bug.WebhookPending = bug.webhookStatusPending()
 	return db.SaveStruct(bug)
 }
 
+type BugBuildCrashes struct {
+	BuildID string
+	Crashes int64
+}
+
 type BugDailyStats struct {
 	Date       int // YYYYMMDD
 	CrashCount int
@@ -331,6 +388,9 @@ type BugReporting struct {
 	OnHold     time.Time          // if set, the bug must not be upstreamed
 	Reported   time.Time
 	Closed     time.Time
+	// The last bug state sent in a WebhookStatus event (for webhook reportings).
+	// It's set to the open state when the bug is reported.
+	WebhookStatus string
 }
 
 func (r *BugReporting) GetLabels() []string {
@@ -602,6 +662,9 @@ type Job struct {
 	Reported         bool   // have we reported result back to user?
 	InvalidatedBy    string // user who marked this bug as invalid, empty by default
 	BackportedCommit Commit
+
+	// Result of config bisection: options that need to be enabled in the baseline config.
+	ConfigOptions []string
 }
 
 func (job *Job) IsBisection() bool {
@@ -618,6 +681,8 @@ const (
 	JobTestPatch JobType = iota
 	JobBisectCause
 	JobBisectFix
+	JobBisectConfig
+	JobFixSearch
 )
 
 func (typ JobType) toDashapiReportType() dashapi.ReportType {
@@ -626,7 +691,7 @@ func (typ JobType) toDashapiReportType() dashapi.ReportType {
 		return dashapi.ReportTestPatch
 	case JobBisectCause:
 		return dashapi.ReportBisectCause
-	case JobBisectFix:
+	case JobBisectFix, JobFixSearch:
 		return dashapi.ReportBisectFix
 	default:
 		panic(fmt.Sprintf("unknown job type %v", typ))
@@ -649,6 +714,11 @@ func (job *Job) IsCrossTree() bool {
 	return job.MergeBaseRepo != "" && job.IsBisection()
 }
 
+// IsFixCandidate returns true for jobs that may find a fix candidate: cross-tree bisections and fix searches.
+func (job *Job) IsFixCandidate() bool {
+	return job.IsCrossTree() || job.Type == JobFixSearch
+}
+
 // Text holds text blobs (crash logs, reports, reproducers, etc).
 type Text struct {
 	Namespace string
@@ -192,8 +192,8 @@ func handleFoundBugsGraph(c context.Context, w http.ResponseWriter, r *http.Requ
 	return serveTemplate(w, "graph_histogram.html", data)
 }
 
-type funcStyleBodyJS func(ctx context.Context, projectID, ns, subsystem string, periods []coveragedb.TimePeriod,
-) (template.CSS, template.HTML, template.HTML, error)
+type funcStyleBodyJS func(ctx context.Context, storage coveragedb.Storage, ns, subsystem string,
+	periods []coveragedb.TimePeriod) (template.CSS, template.HTML, template.HTML, error)
 
 func handleCoverageHeatmap(c context.Context, w http.ResponseWriter, r *http.Request) error {
 	return handleHeatmap(c, w, r, cover.DoHeatMapStyleBodyJS)
@@ -223,7 +223,7 @@ func handleHeatmap(c context.Context, w http.ResponseWriter, r *http.Request, f
 	periods := coveragedb.GenNPeriodsTill(12, civil.DateOf(time.Now()), pOps)
 	var style template.CSS
 	var body, js template.HTML
-	if style, body, js, err = f(c, "syzkaller", hdr.Namespace, ss, periods); err != nil {
+	if style, body, js, err = f(c, coveragedb.NewSpannerStorage("syzkaller"), hdr.Namespace, ss, periods); err != nil {
 		return fmt.Errorf("failed to generate heatmap: %w", err)
 	}
 	return serveTemplate(w, "custom_content.html", struct {
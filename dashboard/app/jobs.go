@@ -285,7 +285,7 @@ func invalidateBisection(c context.Context, jobKey *db.Key, restart bool) error
 			}
 			if job.Type == JobBisectCause {
 				bug.BisectCause = BisectNot
-			} else if job.IsCrossTree() {
+			} else if job.IsFixCandidate() {
 				bug.FixCandidateJob = ""
 			} else if job.Type == JobBisectFix {
 				bug.BisectFix = BisectNot
@@ -466,6 +466,7 @@ func jobFromBugSample(c context.Context, managers map[string]dashapi.ManagerJobs
 		createPatchRetestingJobs,
 		createTreeTestJobs,
 		createTreeBisectionJobs,
+		createFixSearchJobs,
 	}
 	r.Shuffle(len(funcs), func(i, j int) { funcs[i], funcs[j] = funcs[j], funcs[i] })
 	for _, f := range funcs {
@@ -511,12 +512,67 @@ func createTreeBisectionJobs(c context.Context, bugs []*Bug, bugKeys []*db.Key,
 	return nil, nil, nil
 }
 
+// createFixSearchJobs creates jobs that look for the fixing commit among the commits
+// that modify the guilty function of the bug.
+// This helps to find fixes that lack the Reported-by tag without expensive fix bisection.
+func createFixSearchJobs(c context.Context, bugs []*Bug, bugKeys []*db.Key,
+	managers map[string]dashapi.ManagerJobs) (*Job, *db.Key, error) {
+	// New commits need to accumulate before the search makes sense.
+	const fixSearchRepeat = 24 * 14 * time.Hour
+	takeBugs := 5
+	for i, bug := range bugs {
+		if len(bug.StackFrames) == 0 || bug.FixCandidateJob != "" {
+			continue
+		}
+		if timeSince(c, bug.FirstTime) < fixSearchRepeat || timeSince(c, bug.LastFixSearch) < fixSearchRepeat {
+			continue
+		}
+		searchManagers := make(map[string]bool)
+		for _, mgr := range bug.HappenedOn {
+			newMgr, _ := activeManager(c, mgr, bug.Namespace)
+			if managers[newMgr].FixSearch {
+				searchManagers[newMgr] = true
+			}
+		}
+		if len(searchManagers) == 0 {
+			continue
+		}
+		crashes, crashKeys, err := queryCrashesForBug(c, bugKeys[i], maxCrashes())
+		if err != nil {
+			return nil, nil, err
+		}
+		for ci, crash := range crashes {
+			if crash.ReproSyz == 0 || !searchManagers[crash.Manager] ||
+				len(crash.ReportElements.GuiltyFiles) == 0 {
+				continue
+			}
+			return createBisectJobForBug(c, bug, crash, bugKeys[i], crashKeys[ci], JobFixSearch)
+		}
+		takeBugs--
+		if takeBugs == 0 {
+			break
+		}
+	}
+	return nil, nil, nil
+}
+
+// doneFixSearch proposes the commit found by the fix search as the fix candidate of the bug.
+func doneFixSearch(c context.Context, jobKey *db.Key, job *Job) error {
+	if job.Type != JobFixSearch || job.Error != 0 || len(job.Commits) != 1 {
+		return nil
+	}
+	return updateSingleBug(c, jobKey.Parent(), func(bug *Bug) error {
+		bug.FixCandidateJob = jobKey.Encode()
+		return nil
+	})
+}
+
 func createTreeTestJobs(c context.Context, bugs []*Bug, bugKeys []*db.Key,
 	managers map[string]dashapi.ManagerJobs) (*Job, *db.Key, error) {
 	takeBugs := 5
 	prio, next := []int{}, []int{}
 	for i, bug := range bugs {
-		if !getNsConfig(c, bug.Namespace).FindBugOriginTrees {
+		if nsConfig := getNsConfig(c, bug.Namespace); !nsConfig.FindBugOriginTrees && !nsConfig.TrackAffectedTrees {
 			continue
 		}
 		if timeNow(c).Before(bug.TreeTests.NextPoll) {
@@ -798,8 +854,10 @@ func createBisectJobForBug(c context.Context, bug0 *Bug, crash *Crash, bugKey, c
 		}
 		if jobType == JobBisectCause {
 			bug.BisectCause = BisectPending
-		} else {
+		} else if jobType == JobBisectFix {
 			bug.BisectFix = BisectPending
+		} else if jobType == JobFixSearch {
+			bug.LastFixSearch = now
 		}
 		if _, err := db.Put(c, bugKey, bug); err != nil {
 			return fmt.Errorf("failed to put bug: %w", err)
@@ -816,6 +874,32 @@ func createBisectJobForBug(c context.Context, bug0 *Bug, crash *Crash, bugKey, c
 	return job, jobKey, nil
 }
 
+// addConfigBisectJob creates a job that finds the config options that enable the bug.
+func addConfigBisectJob(c context.Context, bug *Bug) error {
+	bugKey := bug.key(c)
+	jobs, err := queryBugJobs(c, bug, JobBisectConfig)
+	if err != nil {
+		return err
+	}
+	for _, j := range jobs.all() {
+		if !j.job.IsFinished() {
+			return fmt.Errorf("%w: config bisection is already pending", ErrClientBadRequest)
+		}
+	}
+	crashes, crashKeys, err := queryCrashesForBug(c, bugKey, maxCrashes())
+	if err != nil {
+		return err
+	}
+	for i, crash := range crashes {
+		if crash.ReproSyz == 0 {
+			continue
+		}
+		_, _, err := createBisectJobForBug(c, bug, crash, bugKey, crashKeys[i], JobBisectConfig)
+		return err
+	}
+	return fmt.Errorf("%w: the bug has no reproducer", ErrClientBadRequest)
+}
+
 func createJobResp(c context.Context, job *Job, jobKey *db.Key) (*dashapi.JobPollResp, bool, error) {
 	jobID := extJobID(jobKey)
 	patch, _, err := getText(c, textPatch, job.Patch)
@@ -905,6 +989,22 @@ func createJobResp(c context.Context, job *Job, jobKey *db.Key) (*dashapi.JobPol
 		resp.Type = dashapi.JobBisectCause
 	case JobBisectFix:
 		resp.Type = dashapi.JobBisectFix
+	case JobBisectConfig:
+		resp.Type = dashapi.JobBisectConfig
+	case JobFixSearch:
+		resp.Type = dashapi.JobFixSearch
+		bug := new(Bug)
+		if err := db.Get(c, bugKey, bug); err != nil {
+			return nil, false, fmt.Errorf("job %v: failed to get bug: %w", jobID, err)
+		}
+		resp.CrashTitle = crash.Title
+		if resp.CrashTitle == "" {
+			resp.CrashTitle = bug.Title
+		}
+		resp.StackFrames = bug.StackFrames
+		if len(crash.ReportElements.GuiltyFiles) != 0 {
+			resp.GuiltyFile = crash.ReportElements.GuiltyFiles[0]
+		}
 	default:
 		return nil, false, fmt.Errorf("bad job type %v", job.Type)
 	}
@@ -1103,6 +1203,21 @@ func doneJob(c context.Context, req *dashapi.JobDoneReq) error {
 		job.Finished = now
 		job.IsRunning = false
 		job.Flags = req.Flags
+		job.ConfigOptions = req.ConfigOptions
+		if job.Type == JobBisectConfig || job.Type == JobFixSearch {
+			// Config bisection and fix search results are only shown on the bug page.
+			job.Reported = true
+		}
+		if job.Type == JobFixSearch {
+			// The found commit is attached to the bug reports as the fix candidate.
+			jobBug := new(Bug)
+			if err := db.Get(c, jobKey.Parent(), jobBug); err != nil {
+				return fmt.Errorf("job %v: failed to get bug: %w", jobID, err)
+			}
+			if _, bugReporting, _, _, _ := currentReporting(c, jobBug); bugReporting != nil {
+				job.Reporting = bugReporting.Name
+			}
+		}
 		if job.Type == JobBisectCause || job.Type == JobBisectFix {
 			// Update bug.BisectCause/Fix status and also remember current bug reporting to send results.
 			var err error
@@ -1140,6 +1255,9 @@ func postJob(c context.Context, jobKey *db.Key, job *Job) error {
 	if err != nil {
 		return fmt.Errorf("job %s: cross tree bisection handlers failed: %w", jobKey, err)
 	}
+	if err := doneFixSearch(c, jobKey, job); err != nil {
+		return fmt.Errorf("job %s: fix search handlers failed: %w", jobKey, err)
+	}
 	return nil
 }
 
@@ -1317,7 +1435,7 @@ func createBugReportForJob(c context.Context, job *Job, jobKey *db.Key, config i
 		ErrorLink:       externalLink(c, textError, job.Error),
 		PatchLink:       externalLink(c, textPatch, job.Patch),
 	}
-	if job.Type == JobBisectCause || job.Type == JobBisectFix {
+	if job.Type == JobBisectCause || job.Type == JobBisectFix || job.Type == JobFixSearch {
 		rep.Maintainers = append(crash.Maintainers, kernelRepo.CC.Maintainers...)
 		rep.ExtID = bugReporting.ExtID
 		if bugReporting.CC != "" {
@@ -1327,7 +1445,7 @@ func createBugReportForJob(c context.Context, job *Job, jobKey *db.Key, config i
 		switch job.Type {
 		case JobBisectCause:
 			rep.BisectCause, emails = bisectFromJob(c, job)
-		case JobBisectFix:
+		case JobBisectFix, JobFixSearch:
 			rep.BisectFix, emails = bisectFromJob(c, job)
 		}
 		rep.Maintainers = append(rep.Maintainers, emails...)
@@ -1354,8 +1472,10 @@ func bisectFromJob(c context.Context, job *Job) (*dashapi.BisectResult, []string
 		LogLink:         externalLink(c, textLog, job.Log),
 		CrashLogLink:    externalLink(c, textCrashLog, job.CrashLog),
 		CrashReportLink: externalLink(c, textCrashReport, job.CrashReport),
-		Fix:             job.Type == JobBisectFix,
+		Fix:             job.Type == JobBisectFix || job.Type == JobFixSearch,
 		CrossTree:       job.IsCrossTree(),
+		Confirmed:       job.Flags&dashapi.BisectResultConfirmed != 0,
+		Unconfirmed:     job.Flags&dashapi.BisectResultUnconfirmed != 0,
 	}
 	for _, com := range job.Commits {
 		bisect.Commits = append(bisect.Commits, com.toDashapi())
@@ -1364,9 +1484,12 @@ func bisectFromJob(c context.Context, job *Job) (*dashapi.BisectResult, []string
 	if len(bisect.Commits) == 1 {
 		bisect.Commit = bisect.Commits[0]
 		bisect.Commits = nil
-		com := job.Commits[0]
-		newEmails = []string{com.Author}
-		newEmails = append(newEmails, strings.Split(com.CC, "|")...)
+		// Don't bother authors of the commit if reverting it did not fix the crash.
+		if !bisect.Unconfirmed {
+			com := job.Commits[0]
+			newEmails = []string{com.Author}
+			newEmails = append(newEmails, strings.Split(com.CC, "|")...)
+		}
 	}
 	if job.BackportedCommit.Title != "" {
 		bisect.Backported = job.BackportedCommit.toDashapi()
@@ -1475,9 +1598,11 @@ func loadPendingJob(c context.Context, managers map[string]dashapi.ManagerJobs)
 			if !managers[job.Manager].TestPatches {
 				continue
 			}
-		case JobBisectCause, JobBisectFix:
+		case JobBisectCause, JobBisectFix, JobBisectConfig, JobFixSearch:
 			if job.Type == JobBisectCause && !managers[job.Manager].BisectCause ||
-				job.Type == JobBisectFix && !managers[job.Manager].BisectFix {
+				job.Type == JobBisectFix && !managers[job.Manager].BisectFix ||
+				job.Type == JobBisectConfig && !managers[job.Manager].BisectConfig ||
+				job.Type == JobFixSearch && !managers[job.Manager].FixSearch {
 				continue
 			}
 			// Don't retry bisection jobs too often.
@@ -1604,6 +1729,7 @@ func makeJobInfo(c context.Context, job *Job, jobKey *db.Key, bug *Bug, build *B
 		info.Commit = info.Commits[0]
 		info.Commits = nil
 	}
+	info.ConfigOptions = job.ConfigOptions
 	if crash != nil {
 		info.ReproCLink = externalLink(c, textReproC, crash.ReproC)
 		info.ReproSyzLink = externalLink(c, textReproSyz, crash.ReproSyz)
@@ -1662,8 +1788,12 @@ func relevantBackportJobs(c context.Context) ([]*backportInfo, error) {
 	for i, job := range allJobs {
 		// Some assertions just in case.
 		jobKey := allJobKeys[i]
+		if !job.IsFixCandidate() {
+			return nil, fmt.Errorf("job %s: expected to be a fix candidate", jobKey)
+		}
 		if !job.IsCrossTree() {
-			return nil, fmt.Errorf("job %s: expected to be cross-tree", jobKey)
+			// Fix search finds commits in the same tree, there's nothing to backport.
+			continue
 		}
 		if len(job.Commits) != 1 || job.InvalidatedBy != "" ||
 			job.BackportedCommit.Title != "" {
@@ -1810,7 +1940,7 @@ func (b *bugJobs) bestFixCandidate() *bugJob {
 		if j.job.InvalidatedBy != "" {
 			continue
 		}
-		if !j.job.IsCrossTree() {
+		if !j.job.IsFixCandidate() || j.job.Type == JobFixSearch && len(j.job.Commits) != 1 {
 			continue
 		}
 		return j
@@ -75,6 +75,9 @@ func initHTTPHandlers() {
 		http.Handle("/"+ns+"/bug-summaries", handlerWrapper(handleBugSummaries))
 		http.Handle("/"+ns+"/subsystems", handlerWrapper(handleSubsystemsList))
 		http.Handle("/"+ns+"/backports", handlerWrapper(handleBackports))
+		if nsConfig.CrashRegressions != nil {
+			http.Handle("/"+ns+"/regressions", handlerWrapper(handleRegressions))
+		}
 		http.Handle("/"+ns+"/s/", handlerWrapper(handleSubsystemPage))
 		http.Handle("/"+ns+"/manager/", handlerWrapper(handleManagerPage))
 	}
@@ -83,6 +86,8 @@ func initHTTPHandlers() {
 	http.HandleFunc("/cron/deprecate_assets", handleDeprecateAssets)
 	http.HandleFunc("/cron/refresh_subsystems", handleRefreshSubsystems)
 	http.HandleFunc("/cron/subsystem_reports", handleSubsystemReports)
+	http.HandleFunc("/cron/detect_regressions", handleDetectRegressions)
+	http.HandleFunc("/cron/possible_dups", handleUpdatePossibleDups)
 }
 
 type uiMainPage struct {
@@ -217,11 +222,12 @@ type uiAdminPage struct {
 }
 
 type uiManagerPage struct {
-	Header        *uiHeader
-	Manager       *uiManager
-	Message       string
-	ShowReproForm bool
-	Builds        []*uiBuild
+	Header           *uiHeader
+	Manager          *uiManager
+	Message          string
+	ShowReproForm    bool
+	MachineCheckDiff string
+	Builds           []*uiBuild
 }
 
 type uiManager struct {
@@ -287,6 +293,9 @@ type uiBugPage struct {
 	TestPatchJobs   *uiJobList
 	LabelGroups     []*uiBugLabelGroup
 	DebugSubsystems string
+	CanBisectConfig bool
+	XSRFToken       string
+	AffectedTrees   []*affectedTree
 }
 
 type uiBugLabelGroup struct {
@@ -300,6 +309,8 @@ const (
 	sectionDiscussionList = "discussion_list"
 	sectionTestResults    = "test_results"
 	sectionReproAttempts  = "repro_attempts"
+	sectionPossibleDups   = "possible_dups"
+	sectionAffectedTrees  = "affected_trees"
 )
 
 type uiCollapsible struct {
@@ -598,6 +609,12 @@ func handleManagerPage(c context.Context, w http.ResponseWriter, r *http.Request
 	managerPage := &uiManagerPage{Manager: manager, Header: hdr}
 	accessLevel := accessLevel(c, r)
 	if accessLevel >= AccessUser {
+		// The list of managers is cached, but the diff must disappear as soon as the check result changes.
+		mgr, err := loadManager(c, hdr.Namespace, manager.Name)
+		if err != nil {
+			return err
+		}
+		managerPage.MachineCheckDiff = mgr.MachineCheckDiff
 		managerPage.ShowReproForm = true
 		if repro := r.FormValue("send-repro"); repro != "" {
 			err := saveReproTask(c, hdr.Namespace, manager.Name, []byte(repro))
@@ -1050,6 +1067,32 @@ func handleBug(c context.Context, w http.ResponseWriter, r *http.Request) error
 	if r.FormValue("debug_subsystems") != "" && accessLevel == AccessAdmin {
 		return debugBugSubsystems(c, w, bug)
 	}
+	if dupOf := r.FormValue("dup_of"); dupOf != "" && r.Method == http.MethodPost {
+		if accessLevel != AccessAdmin {
+			return ErrAccess
+		}
+		if err := checkXSRFToken(c, r, xsrfBugAction); err != nil {
+			return err
+		}
+		if err := markBugDup(c, bug, dupOf); err != nil {
+			return err
+		}
+		http.Redirect(w, r, r.URL.String(), http.StatusSeeOther)
+		return nil
+	}
+	if r.FormValue("bisect_config") != "" && r.Method == http.MethodPost {
+		if accessLevel != AccessAdmin {
+			return ErrAccess
+		}
+		if err := checkXSRFToken(c, r, xsrfBugAction); err != nil {
+			return err
+		}
+		if err := addConfigBisectJob(c, bug); err != nil {
+			return err
+		}
+		http.Redirect(w, r, r.URL.String(), http.StatusSeeOther)
+		return nil
+	}
 	hdr, err := commonHeader(c, r, w, bug.Namespace)
 	if err != nil {
 		return err
@@ -1124,6 +1167,18 @@ func handleBug(c context.Context, w http.ResponseWriter, r *http.Request) error
 			Value: treeTestJobs,
 		})
 	}
+	affected, err := affectedTrees(c, bug)
+	if err != nil {
+		return err
+	}
+	if len(affected) > 0 {
+		sections = append(sections, &uiCollapsible{
+			Title: fmt.Sprintf("Affected trees (%d)", len(affected)),
+			Show:  true,
+			Type:  sectionAffectedTrees,
+			Value: affected,
+		})
+	}
 	similar, err := loadSimilarBugsUI(c, r, bug, state)
 	if err != nil {
 		return err
@@ -1136,6 +1191,18 @@ func handleBug(c context.Context, w http.ResponseWriter, r *http.Request) error
 			Value: similar,
 		})
 	}
+	possibleDups, err := loadPossibleDupsUI(c, bug, state, managers, accessLevel)
+	if err != nil {
+		return err
+	}
+	if len(possibleDups.Bugs) > 0 {
+		sections = append(sections, &uiCollapsible{
+			Title: fmt.Sprintf("Possible duplicates (%d)", len(possibleDups.Bugs)),
+			Show:  true,
+			Type:  sectionPossibleDups,
+			Value: possibleDups,
+		})
+	}
 	causeBisections, err := queryBugJobs(c, bug, JobBisectCause)
 	if err != nil {
 		return fmt.Errorf("failed to load cause bisections: %w", err)
@@ -1158,12 +1225,22 @@ func handleBug(c context.Context, w http.ResponseWriter, r *http.Request) error
 			return err
 		}
 	}
+	fixSearches, err := queryBugJobs(c, bug, JobFixSearch)
+	if err != nil {
+		return fmt.Errorf("failed to load fix searches: %w", err)
+	}
 	var fixCandidate *uiJob
 	if bug.FixCandidateJob != "" {
 		fixCandidate, err = fixBisections.uiBestFixCandidate(c)
 		if err != nil {
 			return err
 		}
+		if fixCandidate == nil {
+			fixCandidate, err = fixSearches.uiBestFixCandidate(c)
+			if err != nil {
+				return err
+			}
+		}
 	}
 	testPatchJobs, err := loadTestPatchJobs(c, bug)
 	if err != nil {
@@ -1199,6 +1276,7 @@ func handleBug(c context.Context, w http.ResponseWriter, r *http.Request) error
 		Crashes:      crashesTable,
 		LabelGroups:  getLabelGroups(c, bug),
 	}
+	data.AffectedTrees = affected
 	if accessLevel == AccessAdmin && !bug.hasUserSubsystems() {
 		data.DebugSubsystems = html.AmendURL(data.Bug.Link, "debug_subsystems", "1")
 	}
@@ -1227,6 +1305,29 @@ func handleBug(c context.Context, w http.ResponseWriter, r *http.Request) error
 				"Cause bisection attempts", uiList))
 		}
 	}
+	configBisections, err := queryBugJobs(c, bug, JobBisectConfig)
+	if err != nil {
+		return fmt.Errorf("failed to load config bisections: %w", err)
+	}
+	if len(configBisections.all()) > 0 {
+		uiList, err := configBisections.uiAll(c)
+		if err != nil {
+			return err
+		}
+		data.Sections = append(data.Sections, makeCollapsibleBugJobs("Config bisections", uiList))
+	}
+	if len(fixSearches.all()) > 0 {
+		uiList, err := fixSearches.uiAll(c)
+		if err != nil {
+			return err
+		}
+		data.Sections = append(data.Sections, makeCollapsibleBugJobs("Fix searches", uiList))
+	}
+	if accessLevel == AccessAdmin {
+		data.XSRFToken = xsrfToken(c, xsrfBugAction)
+	}
+	data.CanBisectConfig = data.XSRFToken != "" && bug.Status == BugStatusOpen &&
+		bug.ReproLevel != ReproLevelNone
 	if r.FormValue("json") == "1" {
 		w.Header().Set("Content-Type", "application/json")
 		return writeJSONVersionOf(w, data)
@@ -2360,7 +2461,7 @@ func makeUIJob(c context.Context, job *Job, jobKey *db.Key, bug *Bug, crash *Cra
 		JobInfo:           makeJobInfo(c, job, jobKey, bug, build, crash),
 		InvalidateJobLink: invalidateJobLink(c, job, jobKey, false),
 		RestartJobLink:    invalidateJobLink(c, job, jobKey, true),
-		FixCandidate:      job.IsCrossTree(),
+		FixCandidate:      job.IsFixCandidate(),
 	}
 	if crash != nil {
 		ui.Crash = makeUICrash(c, crash, build)
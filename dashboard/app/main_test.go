@@ -466,6 +466,40 @@ func TestManagerPage(t *testing.T) {
 	c.expectEQ(httpErr.Code, http.StatusBadRequest)
 }
 
+func TestManagerMachineCheckDiff(t *testing.T) {
+	c := NewCtx(t)
+	defer c.Close()
+
+	build := testBuild(1)
+	c.expectOK(c.client.UploadBuild(build))
+	const diff = "newly disabled syscalls (1):\nsyz_foo: no such file\n"
+	c.expectOK(c.client.UploadManagerStats(&dashapi.ManagerStatsReq{
+		Name:             build.Manager,
+		MachineChecked:   true,
+		MachineCheckDiff: diff,
+	}))
+	page, err := c.AuthGET(AccessAdmin, "/test1/manager/"+build.Manager)
+	c.expectOK(err)
+	assert.Contains(t, string(page), "syz_foo: no such file")
+
+	// Periodic stats don't change the diff.
+	c.expectOK(c.client.UploadManagerStats(&dashapi.ManagerStatsReq{
+		Name: build.Manager,
+	}))
+	page, err = c.AuthGET(AccessAdmin, "/test1/manager/"+build.Manager)
+	c.expectOK(err)
+	assert.Contains(t, string(page), "syz_foo: no such file")
+
+	// A new check without changes resets the diff.
+	c.expectOK(c.client.UploadManagerStats(&dashapi.ManagerStatsReq{
+		Name:           build.Manager,
+		MachineChecked: true,
+	}))
+	page, err = c.AuthGET(AccessAdmin, "/test1/manager/"+build.Manager)
+	c.expectOK(err)
+	assert.NotContains(t, string(page), "Machine check changes")
+}
+
 func TestReproSubmitAccess(t *testing.T) {
 	c := NewCtx(t)
 	defer c.Close()
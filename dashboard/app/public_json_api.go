@@ -5,6 +5,7 @@ package main
 
 import (
 	"encoding/json"
+	"time"
 )
 
 // publicApiBugDescription is used to serve the /bug HTTP requests
@@ -18,6 +19,8 @@ type publicAPIBugDescription struct {
 	// links to the discussions
 	Discussions []string                    `json:"discussions,omitempty"`
 	Crashes     []publicAPICrashDescription `json:"crashes,omitempty"`
+	// results of running the reproducer on the configured kernel trees
+	AffectedTrees []publicAPIAffectedTree `json:"affected-trees,omitempty"`
 }
 
 type vcsCommit struct {
@@ -42,6 +45,16 @@ type publicAPICrashDescription struct {
 	CrashReport         string `json:"crash-report-link,omitempty"`
 }
 
+type publicAPIAffectedTree struct {
+	Alias        string `json:"alias"`
+	Repo         string `json:"repo"`
+	Branch       string `json:"branch"`
+	Status       string `json:"status"`
+	KernelCommit string `json:"kernel-commit,omitempty"`
+	Date         string `json:"date,omitempty"`
+	CrashTitle   string `json:"crash-title,omitempty"`
+}
+
 func getExtAPIDescrForBugPage(bugPage *uiBugPage) *publicAPIBugDescription {
 	return &publicAPIBugDescription{
 		Version: 1,
@@ -85,7 +98,27 @@ func getExtAPIDescrForBugPage(bugPage *uiBugPage) *publicAPIBugDescription {
 			}
 			return res
 		}(),
+		AffectedTrees: getAffectedTrees(bugPage.AffectedTrees),
+	}
+}
+
+func getAffectedTrees(trees []*affectedTree) []publicAPIAffectedTree {
+	var res []publicAPIAffectedTree
+	for _, tree := range trees {
+		item := publicAPIAffectedTree{
+			Alias:  tree.Alias,
+			Repo:   tree.Repo,
+			Branch: tree.Branch,
+			Status: tree.Status,
+		}
+		if tree.Job != nil {
+			item.KernelCommit = tree.Job.KernelCommit
+			item.Date = tree.Job.Finished.Format(time.DateOnly)
+			item.CrashTitle = tree.Job.CrashTitle
+		}
+		res = append(res, item)
 	}
+	return res
 }
 
 func getBugFixCommits(bug *uiBug) []vcsCommit {
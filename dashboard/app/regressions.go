@@ -0,0 +1,402 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/syzkaller/pkg/hash"
+	"github.com/google/syzkaller/pkg/vcs"
+	"google.golang.org/appengine/v2"
+	db "google.golang.org/appengine/v2/datastore"
+	"google.golang.org/appengine/v2/log"
+)
+
+// Crash rate regressions detection.
+// We count crashes of each bug on the latest builds (Bug.BuildCrashes) and fuzzing time
+// on the latest builds of each manager (Manager.BuildFuzzingTimes). The counters are updated
+// in the bug and manager transactions that already run on every crash and stats upload.
+// Periodically we compare crash rates on the current build of each manager with the rates
+// on the previous builds of the same manager and record statistically significant increases
+// as CrashRegression entities. Bugs that never crashed on the previous builds are not
+// regressions, they are reported as new bugs.
+
+const (
+	// Don't flag regressions with fewer crashes on the new build.
+	minRegressionCrashes = 5
+	// Max probability to observe the crash rate increase by chance.
+	maxRegressionPValue = 1e-3
+	// How many previous builds are used as the baseline.
+	maxRegressionBaseBuilds = 5
+	// Don't use builds older than this as the baseline.
+	maxRegressionBaseAge = 30 * 24 * time.Hour
+	// Max number of builds with crash counts per bug.
+	maxBugBuildCrashes = 30
+	// Max number of builds with fuzzing time per manager.
+	maxManagerBuildFuzzingTimes = 3 * maxRegressionBaseBuilds
+)
+
+func (bug *Bug) increaseBuildCrashes(buildID string) {
+	for i := range bug.BuildCrashes {
+		if bug.BuildCrashes[i].BuildID == buildID {
+			bug.BuildCrashes[i].Crashes++
+			return
+		}
+	}
+	bug.BuildCrashes = append(bug.BuildCrashes, BugBuildCrashes{BuildID: buildID, Crashes: 1})
+	if len(bug.BuildCrashes) > maxBugBuildCrashes {
+		bug.BuildCrashes = bug.BuildCrashes[len(bug.BuildCrashes)-maxBugBuildCrashes:]
+	}
+}
+
+func (mgr *Manager) increaseBuildFuzzingTime(buildID string, fuzzingTime time.Duration) {
+	for i := range mgr.BuildFuzzingTimes {
+		if mgr.BuildFuzzingTimes[i].BuildID == buildID {
+			mgr.BuildFuzzingTimes[i].FuzzingTime += fuzzingTime
+			return
+		}
+	}
+	mgr.BuildFuzzingTimes = append(mgr.BuildFuzzingTimes,
+		ManagerBuildFuzzingTime{BuildID: buildID, FuzzingTime: fuzzingTime})
+	if len(mgr.BuildFuzzingTimes) > maxManagerBuildFuzzingTimes {
+		mgr.BuildFuzzingTimes = mgr.BuildFuzzingTimes[len(mgr.BuildFuzzingTimes)-maxManagerBuildFuzzingTimes:]
+	}
+}
+
+func handleDetectRegressions(w http.ResponseWriter, r *http.Request) {
+	c := appengine.NewContext(r)
+	for ns, cfg := range getConfig(c).Namespaces {
+		if cfg.CrashRegressions == nil || cfg.Decommissioned {
+			continue
+		}
+		if err := detectCrashRegressions(c, ns, cfg.CrashRegressions); err != nil {
+			log.Errorf(c, "%v: failed to detect crash regressions: %v", ns, err)
+		}
+	}
+}
+
+func detectCrashRegressions(c context.Context, ns string, cfg *CrashRegressionsConfig) error {
+	managers, _, err := loadAllManagers(c, ns)
+	if err != nil {
+		return err
+	}
+	crashes, err := loadBuildCrashes(c, ns)
+	if err != nil {
+		return err
+	}
+	for _, mgr := range managers {
+		if mgr.CurrentBuild == "" {
+			continue
+		}
+		regressions, err := managerCrashRegressions(c, ns, mgr, crashes, cfg)
+		if err != nil {
+			return fmt.Errorf("%v: %w", mgr.Name, err)
+		}
+		var fresh []*CrashRegression
+		for _, regression := range regressions {
+			saved, err := saveCrashRegression(c, regression)
+			if err != nil {
+				return err
+			}
+			if saved {
+				fresh = append(fresh, regression)
+			}
+		}
+		if len(fresh) != 0 && cfg.Config != nil {
+			if err := reportCrashRegressions(c, cfg.Config.(*EmailConfig), fresh); err != nil {
+				log.Errorf(c, "failed to report crash regressions: %v", err)
+			}
+		}
+	}
+	return nil
+}
+
+// managerCrashRegressions returns regressions on the current build of the manager,
+// crashes are the crash counts per build and bug key hash.
+func managerCrashRegressions(c context.Context, ns string, mgr *Manager, crashes map[string]map[string]int64,
+	cfg *CrashRegressionsConfig) ([]*CrashRegression, error) {
+	builds, err := loadBuilds(c, ns, mgr.Name, BuildNormal)
+	if err != nil {
+		return nil, err
+	}
+	fuzzingTimes := make(map[string]time.Duration)
+	for _, item := range mgr.BuildFuzzingTimes {
+		fuzzingTimes[item.BuildID] = item.FuzzingTime
+	}
+	pos := -1
+	for i, build := range builds {
+		if build.ID == mgr.CurrentBuild {
+			pos = i
+			break
+		}
+	}
+	if pos == -1 || fuzzingTimes[mgr.CurrentBuild] < cfg.MinFuzzingTime {
+		return nil, nil
+	}
+	build := builds[pos]
+	fuzzingTime := fuzzingTimes[build.ID]
+	var baseBuilds []string
+	var baseTime time.Duration
+	for _, base := range builds[pos+1:] {
+		if len(baseBuilds) == maxRegressionBaseBuilds || build.Time.Sub(base.Time) > maxRegressionBaseAge {
+			break
+		}
+		if fuzzingTimes[base.ID] == 0 {
+			continue
+		}
+		baseBuilds = append(baseBuilds, base.ID)
+		baseTime += fuzzingTimes[base.ID]
+	}
+	if baseTime < cfg.MinFuzzingTime {
+		return nil, nil
+	}
+	buildCrashes := sumBuildCrashes(crashes, []string{build.ID})
+	baseCrashes := sumBuildCrashes(crashes, baseBuilds)
+	var regressions []*CrashRegression
+	for bugHash, count := range buildCrashes {
+		pvalue, ok := crashRateRegression(count, fuzzingTime, baseCrashes[bugHash], baseTime, cfg.MinRatio)
+		if !ok {
+			continue
+		}
+		title := fmt.Sprintf("total crash rate on %v", mgr.Name)
+		if bugHash != "" {
+			bug := new(Bug)
+			if err := db.Get(c, db.NewKey(c, "Bug", bugHash, 0, nil), bug); err != nil {
+				return nil, fmt.Errorf("failed to get bug %v: %w", bugHash, err)
+			}
+			title = bug.displayTitle()
+		}
+		regressions = append(regressions, &CrashRegression{
+			Namespace:       ns,
+			Manager:         mgr.Name,
+			BuildID:         build.ID,
+			BugKey:          bugHash,
+			Title:           title,
+			Crashes:         count,
+			FuzzingTime:     fuzzingTime,
+			BaseBuilds:      baseBuilds,
+			BaseCrashes:     baseCrashes[bugHash],
+			BaseFuzzingTime: baseTime,
+			PValue:          pvalue,
+			Time:            timeNow(c),
+		})
+	}
+	sort.Slice(regressions, func(i, j int) bool {
+		return regressions[i].PValue < regressions[j].PValue
+	})
+	return regressions, nil
+}
+
+// loadBuildCrashes returns the number of crashes of the namespace bugs per build and bug key hash.
+func loadBuildCrashes(c context.Context, ns string) (map[string]map[string]int64, error) {
+	res := make(map[string]map[string]int64)
+	err := foreachBug(c, func(query *db.Query) *db.Query {
+		return query.Filter("Namespace=", ns)
+	}, func(bug *Bug, key *db.Key) error {
+		for _, item := range bug.BuildCrashes {
+			if res[item.BuildID] == nil {
+				res[item.BuildID] = make(map[string]int64)
+			}
+			res[item.BuildID][key.StringID()] += item.Crashes
+		}
+		return nil
+	})
+	return res, err
+}
+
+// sumBuildCrashes returns the number of crashes on the builds per bug key hash,
+// the total number of crashes is stored under the empty key.
+func sumBuildCrashes(crashes map[string]map[string]int64, builds []string) map[string]int64 {
+	res := make(map[string]int64)
+	for _, buildID := range builds {
+		for bugHash, count := range crashes[buildID] {
+			res[bugHash] += count
+			res[""] += count
+		}
+	}
+	return res
+}
+
+// crashRateRegression checks if the crash rate increase is statistically significant.
+// If the rates are equal, crashes are distributed between the new and the base builds
+// proportionally to the fuzzing time, so we test how likely it is to get at least
+// the observed number of crashes on the new build by chance (binomial test).
+// Crashes that did not happen on the base builds at all are new bugs, not regressions.
+func crashRateRegression(crashes int64, fuzzingTime time.Duration, baseCrashes int64,
+	baseFuzzingTime time.Duration, minRatio float64) (float64, bool) {
+	if crashes < minRegressionCrashes || baseCrashes == 0 || fuzzingTime <= 0 || baseFuzzingTime <= 0 {
+		return 1, false
+	}
+	ratio := float64(crashes) / fuzzingTime.Hours() / (float64(baseCrashes) / baseFuzzingTime.Hours())
+	if ratio < minRatio {
+		return 1, false
+	}
+	p := fuzzingTime.Hours() / (fuzzingTime.Hours() + baseFuzzingTime.Hours())
+	pvalue := binomialTail(crashes+baseCrashes, crashes, p)
+	return pvalue, pvalue < maxRegressionPValue
+}
+
+// binomialTail returns P(X >= k) for X ~ Binomial(n, p).
+func binomialTail(n, k int64, p float64) float64 {
+	if k <= 0 {
+		return 1
+	}
+	if p >= 1 {
+		return 1
+	}
+	if p <= 0 {
+		return 0
+	}
+	lgammaN, _ := math.Lgamma(float64(n + 1))
+	sum := 0.0
+	for i := k; i <= n; i++ {
+		lgammaI, _ := math.Lgamma(float64(i + 1))
+		lgammaNI, _ := math.Lgamma(float64(n - i + 1))
+		term := math.Exp(lgammaN - lgammaI - lgammaNI +
+			float64(i)*math.Log(p) + float64(n-i)*math.Log1p(-p))
+		sum += term
+		// Terms decrease after the mode, the rest is negligible.
+		if float64(i) > float64(n)*p && term < sum*1e-12 {
+			break
+		}
+	}
+	return math.Min(sum, 1)
+}
+
+// saveCrashRegression saves the regression, unless it was already detected before.
+func saveCrashRegression(c context.Context, regression *CrashRegression) (bool, error) {
+	key := db.NewKey(c, "CrashRegression", hash.String([]byte(fmt.Sprintf("%v-%v-%v",
+		regression.Namespace, regression.BuildID, regression.BugKey))), 0, nil)
+	saved := false
+	tx := func(c context.Context) error {
+		err := db.Get(c, key, new(CrashRegression))
+		if err == nil {
+			return nil
+		}
+		if err != db.ErrNoSuchEntity {
+			return fmt.Errorf("failed to get crash regression: %w", err)
+		}
+		if _, err := db.Put(c, key, regression); err != nil {
+			return fmt.Errorf("failed to put crash regression: %w", err)
+		}
+		saved = true
+		return nil
+	}
+	if err := db.RunInTransaction(c, tx, nil); err != nil {
+		return false, err
+	}
+	return saved, nil
+}
+
+func reportCrashRegressions(c context.Context, cfg *EmailConfig, regressions []*CrashRegression) error {
+	first := regressions[0]
+	build, err := loadBuild(c, first.Namespace, first.BuildID)
+	if err != nil {
+		return err
+	}
+	var body strings.Builder
+	fmt.Fprintf(&body, "syzbot has detected crash rate regressions on %v\n\n", first.Manager)
+	fmt.Fprintf(&body, "kernel commit: %v %q\n", build.KernelCommit, build.KernelCommitTitle)
+	fmt.Fprintf(&body, "git tree: %v %v\n\n", build.KernelRepo, build.KernelBranch)
+	for _, regression := range regressions {
+		fmt.Fprintf(&body, "%v\n\tcrashes/day: %.1f -> %.1f (%v -> %v crashes)\n",
+			regression.Title, regression.baseRate(), regression.rate(),
+			regression.BaseCrashes, regression.Crashes)
+		if regression.BugKey != "" {
+			fmt.Fprintf(&body, "\t%v/bug?id=%v\n", appURL(c), regression.BugKey)
+		}
+	}
+	fmt.Fprintf(&body, "\nAll detected regressions: %v/%v/regressions\n", appURL(c), first.Namespace)
+	subject := fmt.Sprintf("crash rate regression on %v", first.Manager)
+	return sendMailText(c, cfg, subject, fromAddr(c), []string{cfg.Email}, "", body.String())
+}
+
+// rate returns the number of crashes per day of fuzzing time.
+func (regression *CrashRegression) rate() float64 {
+	return float64(regression.Crashes) / regression.FuzzingTime.Hours() * 24
+}
+
+func (regression *CrashRegression) baseRate() float64 {
+	return float64(regression.BaseCrashes) / regression.BaseFuzzingTime.Hours() * 24
+}
+
+type uiRegressionsPage struct {
+	Header      *uiHeader
+	Regressions []*uiCrashRegression
+}
+
+type uiCrashRegression struct {
+	Time         time.Time
+	Manager      string
+	KernelCommit string
+	KernelLink   string
+	Title        string
+	Link         string
+	Crashes      int64
+	BaseCrashes  int64
+	Rate         string
+	BaseRate     string
+	PValue       string
+}
+
+func handleRegressions(c context.Context, w http.ResponseWriter, r *http.Request) error {
+	hdr, err := commonHeader(c, r, w, "")
+	if err != nil {
+		return err
+	}
+	const limit = 200
+	var regressions []*CrashRegression
+	_, err = db.NewQuery("CrashRegression").
+		Filter("Namespace=", hdr.Namespace).
+		Order("-Time").
+		Limit(limit).
+		GetAll(c, &regressions)
+	if err != nil {
+		return fmt.Errorf("failed to query crash regressions: %w", err)
+	}
+	accessLevel := accessLevel(c, r)
+	page := &uiRegressionsPage{Header: hdr}
+	builds := make(map[string]*Build)
+	for _, regression := range regressions {
+		ui := &uiCrashRegression{
+			Time:        regression.Time,
+			Manager:     regression.Manager,
+			Title:       regression.Title,
+			Crashes:     regression.Crashes,
+			BaseCrashes: regression.BaseCrashes,
+			Rate:        fmt.Sprintf("%.1f", regression.rate()),
+			BaseRate:    fmt.Sprintf("%.1f", regression.baseRate()),
+			PValue:      fmt.Sprintf("%.1e", regression.PValue),
+		}
+		if regression.BugKey != "" {
+			bug := new(Bug)
+			if err := db.Get(c, db.NewKey(c, "Bug", regression.BugKey, 0, nil), bug); err != nil {
+				return fmt.Errorf("failed to get bug: %w", err)
+			}
+			if accessLevel < bug.sanitizeAccess(c, accessLevel) {
+				continue
+			}
+			ui.Title = bug.displayTitle()
+			ui.Link = bugLink(regression.BugKey)
+		}
+		build := builds[regression.BuildID]
+		if build == nil {
+			build, err = loadBuild(c, regression.Namespace, regression.BuildID)
+			if err != nil {
+				return err
+			}
+			builds[regression.BuildID] = build
+		}
+		ui.KernelCommit = build.KernelCommit
+		ui.KernelLink = vcs.CommitLink(build.KernelRepo, build.KernelCommit)
+		page.Regressions = append(page.Regressions, ui)
+	}
+	return serveTemplate(w, "regressions.html", page)
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/dashboard/dashapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBinomialTail(t *testing.T) {
+	assert.Equal(t, 1.0, binomialTail(10, 0, 0.5))
+	assert.InDelta(t, 1.0/1024, binomialTail(10, 10, 0.5), 1e-12)
+	assert.InDelta(t, 638.0/1024, binomialTail(10, 5, 0.5), 1e-12)
+	assert.InDelta(t, 1-math.Pow(0.9, 100), binomialTail(100, 1, 0.1), 1e-9)
+	assert.Equal(t, 0.0, binomialTail(10, 5, 0))
+	// Must not take forever on large numbers.
+	assert.Less(t, binomialTail(1e6, 6e5, 0.5), 1e-10)
+}
+
+func TestCrashRateRegression(t *testing.T) {
+	const minRatio = 10
+	day := 24 * time.Hour
+	tests := []struct {
+		crashes     int64
+		time        time.Duration
+		baseCrashes int64
+		baseTime    time.Duration
+		regression  bool
+	}{
+		// Too few crashes.
+		{4, day, 1, 10 * day, false},
+		// Dormant bug started firing.
+		{100, day, 1, 10 * day, true},
+		// New bugs are not regressions.
+		{100, day, 0, 10 * day, false},
+		{5, day, 0, 5 * day, false},
+		// Growth is too small.
+		{100, day, 200, 10 * day, false},
+		// The same rate.
+		{100, day, 1000, 10 * day, false},
+		// Not enough data to tell.
+		{5, day, 0, day, false},
+	}
+	for i, test := range tests {
+		_, regression := crashRateRegression(test.crashes, test.time, test.baseCrashes, test.baseTime, minRatio)
+		assert.Equal(t, test.regression, regression, "test #%v", i)
+	}
+}
+
+func TestBuildCounters(t *testing.T) {
+	bug := new(Bug)
+	for i := 0; i < maxBugBuildCrashes+2; i++ {
+		bug.increaseBuildCrashes(fmt.Sprint(i))
+	}
+	bug.increaseBuildCrashes(fmt.Sprint(maxBugBuildCrashes + 1))
+	assert.Len(t, bug.BuildCrashes, maxBugBuildCrashes)
+	assert.Equal(t, BugBuildCrashes{BuildID: "2", Crashes: 1}, bug.BuildCrashes[0])
+	assert.Equal(t, BugBuildCrashes{BuildID: fmt.Sprint(maxBugBuildCrashes + 1), Crashes: 2},
+		bug.BuildCrashes[maxBugBuildCrashes-1])
+
+	mgr := new(Manager)
+	mgr.increaseBuildFuzzingTime("a", time.Hour)
+	mgr.increaseBuildFuzzingTime("b", time.Hour)
+	mgr.increaseBuildFuzzingTime("a", time.Hour)
+	assert.Equal(t, []ManagerBuildFuzzingTime{
+		{BuildID: "a", FuzzingTime: 2 * time.Hour},
+		{BuildID: "b", FuzzingTime: time.Hour},
+	}, mgr.BuildFuzzingTimes)
+}
+
+func TestDetectRegressions(t *testing.T) {
+	c := NewCtx(t)
+	defer c.Close()
+
+	build1 := testBuild(1)
+	c.client.UploadBuild(build1)
+	c.expectOK(c.client.UploadManagerStats(&dashapi.ManagerStatsReq{
+		Name:        build1.Manager,
+		FuzzingTime: 10 * time.Hour,
+	}))
+	crash1 := testCrash(build1, 1)
+	c.client.ReportCrash(crash1)
+	c.client.pollBug()
+
+	c.advanceTime(time.Hour)
+	build2 := testBuild(1)
+	build2.ID = "build1-new"
+	c.client.UploadBuild(build2)
+	c.expectOK(c.client.UploadManagerStats(&dashapi.ManagerStatsReq{
+		Name:        build2.Manager,
+		FuzzingTime: 2 * time.Hour,
+	}))
+	crash2 := testCrash(build2, 1)
+	for i := 0; i < 20; i++ {
+		c.client.ReportCrash(crash2)
+	}
+	// A new bug is reported as a new bug, not as a regression.
+	crash3 := testCrash(build2, 3)
+	for i := 0; i < 20; i++ {
+		c.client.ReportCrash(crash3)
+	}
+	c.client.pollBug()
+
+	_, err := c.GET("/cron/detect_regressions")
+	c.expectOK(err)
+	msg := c.pollEmailBug()
+	c.expectEQ(msg.To, []string{"regressions@syzkaller.com"})
+	c.expectEQ(msg.Subject, "crash rate regression on "+build2.Manager)
+	assert.Contains(t, msg.Body, crash1.Title)
+	assert.NotContains(t, msg.Body, crash3.Title)
+
+	// The same regression must not be reported twice.
+	_, err = c.GET("/cron/detect_regressions")
+	c.expectOK(err)
+	c.expectNoEmail()
+
+	page, err := c.AuthGET(AccessAdmin, "/test1/regressions")
+	c.expectOK(err)
+	assert.Contains(t, string(page), crash1.Title)
+}
@@ -822,6 +822,25 @@ func reportingPollClosed(c context.Context, ids []string) ([]string, error) {
 	return closed, err
 }
 
+// reportedBugUpdate returns the update that marks the bug report rep as sent.
+func reportedBugUpdate(rep *dashapi.BugReport) *dashapi.BugUpdate {
+	cmd := &dashapi.BugUpdate{
+		ID:         rep.ID,
+		Status:     dashapi.BugStatusOpen,
+		ReproLevel: dashapi.ReproLevelNone,
+		CrashID:    rep.CrashID,
+	}
+	if len(rep.ReproC) != 0 {
+		cmd.ReproLevel = dashapi.ReproLevelC
+	} else if len(rep.ReproSyz) != 0 {
+		cmd.ReproLevel = dashapi.ReproLevelSyz
+	}
+	for label := range rep.LabelMessages {
+		cmd.Labels = append(cmd.Labels, label)
+	}
+	return cmd
+}
+
 // incomingCommand is entry point to bug status updates.
 func incomingCommand(c context.Context, cmd *dashapi.BugUpdate) (bool, string, error) {
 	log.Infof(c, "got command: %+v", cmd)
@@ -1069,6 +1088,11 @@ func incomingCommandCmd(c context.Context, now time.Time, cmd *dashapi.BugUpdate
 		stateEnt.Sent++
 		if bugReporting.Reported.IsZero() {
 			bugReporting.Reported = now
+			reporting := getNsConfig(c, bug.Namespace).ReportingByName(bugReporting.Name)
+			if reporting != nil && reporting.Config.Type() == webhookType {
+				// Status events are sent when the state changes (see webhookPollStatus).
+				bugReporting.WebhookStatus = string(dashapi.WebhookOpen)
+			}
 		}
 		if bugReporting.OnHold.IsZero() && cmd.OnHold {
 			bugReporting.OnHold = now
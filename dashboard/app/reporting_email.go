@@ -166,21 +166,7 @@ func emailSendBugReport(c context.Context, rep *dashapi.BugReport) error {
 	if err := emailReport(c, rep); err != nil {
 		return fmt.Errorf("failed to report bug: %w", err)
 	}
-	cmd := &dashapi.BugUpdate{
-		ID:         rep.ID,
-		Status:     dashapi.BugStatusOpen,
-		ReproLevel: dashapi.ReproLevelNone,
-		CrashID:    rep.CrashID,
-	}
-	if len(rep.ReproC) != 0 {
-		cmd.ReproLevel = dashapi.ReproLevelC
-	} else if len(rep.ReproSyz) != 0 {
-		cmd.ReproLevel = dashapi.ReproLevelSyz
-	}
-	for label := range rep.LabelMessages {
-		cmd.Labels = append(cmd.Labels, label)
-	}
-	ok, reason, err := incomingCommand(c, cmd)
+	ok, reason, err := incomingCommand(c, reportedBugUpdate(rep))
 	if !ok || err != nil {
 		return fmt.Errorf("failed to update reported bug: ok=%v reason=%v err=%w", ok, reason, err)
 	}
@@ -414,6 +400,20 @@ func emailListReport(c context.Context, rep *dashapi.BugListReport, cfg *EmailCo
 	})
 }
 
+// emailSeriesFindings replies to the patch series thread with the crashes found while fuzzing the series.
+func emailSeriesFindings(c context.Context, cfg *EmailConfig, req *dashapi.SeriesFindingsReq) error {
+	body := new(bytes.Buffer)
+	if err := mailTemplates.ExecuteTemplate(body, "mail_series_findings.txt", req); err != nil {
+		return fmt.Errorf("failed to execute mail_series_findings.txt template: %w", err)
+	}
+	// The thread is not ours, so don't add the subject prefix.
+	replyCfg := *cfg
+	replyCfg.SubjectPrefix = ""
+	to := email.MergeEmailLists([]string{cfg.Email}, req.Cc)
+	log.Infof(c, "sending series findings %q to %q", req.Subject, to)
+	return sendMailText(c, &replyCfg, req.Subject, fromAddr(c), to, req.MessageID, body.String())
+}
+
 type mailSendParams struct {
 	templateName string
 	templateArg  any
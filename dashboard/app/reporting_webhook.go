@@ -0,0 +1,449 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/syzkaller/dashboard/dashapi"
+	"github.com/google/syzkaller/pkg/email"
+	"google.golang.org/appengine/v2"
+	db "google.golang.org/appengine/v2/datastore"
+	"google.golang.org/appengine/v2/log"
+)
+
+// Webhook reporting interface.
+// Bug reports, notifications, job results and bug status changes are POSTed as signed
+// dashapi.WebhookEvent's to the configured URL (e.g. a service that files issues in an issue tracker).
+// Comments with #syz commands come back as dashapi.WebhookReply's to /webhook/reply
+// and are handled the same way as email commands.
+
+func initWebhookReporting() {
+	http.HandleFunc("/cron/webhook_poll", handleWebhookPoll)
+	http.HandleFunc("/webhook/reply", handleWebhookReply)
+}
+
+const (
+	webhookType     = "webhook"
+	maxWebhookReply = 1 << 20
+)
+
+type WebhookConfig struct {
+	// URL receives POST requests with dashapi.WebhookEvent in the body.
+	URL string
+	// Secret is used to sign requests in both directions (see dashapi.WebhookSignature).
+	Secret string
+}
+
+func (cfg *WebhookConfig) Type() string {
+	return webhookType
+}
+
+func (cfg *WebhookConfig) Validate() error {
+	u, err := url.Parse(cfg.URL)
+	if err != nil || u.Scheme != "https" && u.Scheme != "http" || u.Host == "" {
+		return fmt.Errorf("webhook config: bad URL %q", cfg.URL)
+	}
+	if len(cfg.Secret) < 16 {
+		return fmt.Errorf("webhook config: secret is too short")
+	}
+	return nil
+}
+
+// sendWebhook POSTs the JSON-encoded event to the webhook and returns the response body.
+var sendWebhook = func(c context.Context, cfg *WebhookConfig, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(c, "POST", cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(dashapi.WebhookSignatureHeader, dashapi.WebhookSignature(cfg.Secret, body))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxWebhookReply))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("webhook returned %v: %s", resp.Status, data)
+	}
+	return data, nil
+}
+
+// handleWebhookPoll is called by cron and sends new bugs, notifications and job results, if any.
+func handleWebhookPoll(w http.ResponseWriter, r *http.Request) {
+	c := appengine.NewContext(r)
+	stop, err := emergentlyStopped(c)
+	if err != nil {
+		log.Errorf(c, "emergency stop querying failed: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if stop {
+		log.Errorf(c, "aborting webhook poll due to an emergency stop")
+		return
+	}
+	for _, poll := range []func(context.Context) error{
+		webhookPollJobs,
+		webhookPollNotifications,
+		webhookPollBugs,
+		webhookPollStatus,
+	} {
+		if err := poll(c); err != nil {
+			log.Errorf(c, "webhook poll failed: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	w.Write([]byte("OK"))
+}
+
+func webhookPollBugs(c context.Context) error {
+	for _, rep := range reportingPollBugs(c, webhookType) {
+		if err := webhookSendBugReport(c, rep); err != nil {
+			log.Errorf(c, "webhookPollBugs: %v", err)
+		}
+	}
+	return nil
+}
+
+func webhookSendBugReport(c context.Context, rep *dashapi.BugReport) error {
+	cfg, err := webhookConfig(rep.Config)
+	if err != nil {
+		return err
+	}
+	rep.Config = nil
+	resp := new(dashapi.WebhookResponse)
+	event := &dashapi.WebhookEvent{Type: dashapi.WebhookReport, Report: rep}
+	if err := webhookSend(c, cfg, event, resp); err != nil {
+		return fmt.Errorf("failed to report bug: %w", err)
+	}
+	cmd := reportedBugUpdate(rep)
+	cmd.ExtID = resp.ExtID
+	cmd.Link = resp.Link
+	ok, reason, err := incomingCommand(c, cmd)
+	if !ok || err != nil {
+		return fmt.Errorf("failed to update reported bug: ok=%v reason=%v err=%w", ok, reason, err)
+	}
+	return nil
+}
+
+func webhookPollNotifications(c context.Context) error {
+	for _, notif := range reportingPollNotifications(c, webhookType) {
+		if err := webhookSendBugNotif(c, notif); err != nil {
+			log.Errorf(c, "webhookPollNotifications: %v", err)
+		}
+	}
+	return nil
+}
+
+func webhookSendBugNotif(c context.Context, notif *dashapi.BugNotification) error {
+	status := dashapi.BugStatusOpen
+	var statusReason dashapi.BugStatusReason
+	switch notif.Type {
+	case dashapi.BugNotifUpstream:
+		status = dashapi.BugStatusUpstream
+	case dashapi.BugNotifObsoleted:
+		status = dashapi.BugStatusInvalid
+		statusReason = dashapi.BugStatusReason(notif.Text)
+	case dashapi.BugNotifBadCommit, dashapi.BugNotifLabel:
+	default:
+		return fmt.Errorf("bad notification type %v", notif.Type)
+	}
+	cfg, err := webhookConfig(notif.Config)
+	if err != nil {
+		return err
+	}
+	notif.Config = nil
+	event := &dashapi.WebhookEvent{Type: dashapi.WebhookNotification, Notification: notif}
+	if err := webhookSend(c, cfg, event, nil); err != nil {
+		return err
+	}
+	cmd := &dashapi.BugUpdate{
+		ID:           notif.ID,
+		Status:       status,
+		StatusReason: statusReason,
+		Notification: true,
+	}
+	if notif.Label != "" {
+		cmd.Labels = []string{notif.Label}
+	}
+	ok, reason, err := incomingCommand(c, cmd)
+	if !ok || err != nil {
+		return fmt.Errorf("notif update failed: ok=%v reason=%v err=%w", ok, reason, err)
+	}
+	return nil
+}
+
+func webhookPollJobs(c context.Context) error {
+	jobs, err := pollCompletedJobs(c, webhookType)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		cfg, err := webhookConfig(job.Config)
+		if err != nil {
+			log.Errorf(c, "failed to report job: %v", err)
+			continue
+		}
+		job.Config = nil
+		if err := webhookSend(c, cfg, &dashapi.WebhookEvent{Type: dashapi.WebhookJob, Report: job}, nil); err != nil {
+			log.Errorf(c, "failed to report job: %v", err)
+			continue
+		}
+		if err := jobReported(c, job.JobID); err != nil {
+			log.Errorf(c, "failed to mark job reported: %v", err)
+			continue
+		}
+	}
+	return nil
+}
+
+// webhookPollStatus sends status events for bugs reported over webhooks
+// whose state has changed since the report or the previous status event.
+func webhookPollStatus(c context.Context) error {
+	return foreachBug(c, func(query *db.Query) *db.Query {
+		return query.Filter("WebhookPending=", true)
+	}, func(bug *Bug, bugKey *db.Key) error {
+		if err := webhookSendBugStatus(c, bug, bugKey); err != nil {
+			log.Errorf(c, "webhookPollStatus: %v", err)
+		}
+		return nil
+	})
+}
+
+func webhookSendBugStatus(c context.Context, bug *Bug, bugKey *db.Key) error {
+	state := webhookState(bug)
+	for i := range bug.Reporting {
+		bugReporting := &bug.Reporting[i]
+		if !bugReporting.webhookStatusPending(state) {
+			continue
+		}
+		reporting := getNsConfig(c, bug.Namespace).ReportingByName(bugReporting.Name)
+		if reporting == nil {
+			continue
+		}
+		cfg, ok := reporting.Config.(*WebhookConfig)
+		if !ok {
+			continue
+		}
+		status, err := webhookBugStatus(c, bug, bugReporting)
+		if err != nil {
+			return err
+		}
+		event := &dashapi.WebhookEvent{Type: dashapi.WebhookStatus, Status: status}
+		if err := webhookSend(c, cfg, event, nil); err != nil {
+			return err
+		}
+		err = updateSingleBug(c, bugKey, func(bug *Bug) error {
+			if bugReporting := bugReportingByName(bug, reporting.Name); bugReporting != nil {
+				bugReporting.WebhookStatus = state
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// webhookStatusPending returns true if a status event needs to be sent to one of the bug reportings.
+func (bug *Bug) webhookStatusPending() bool {
+	state := webhookState(bug)
+	for i := range bug.Reporting {
+		if bug.Reporting[i].webhookStatusPending(state) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *BugReporting) webhookStatusPending(state string) bool {
+	// WebhookStatus is set only for reportings of the webhook type.
+	return r.WebhookStatus != "" && r.WebhookStatus != state && !r.Dummy
+}
+
+// webhookState returns the bug state as it's stored in BugReporting.WebhookStatus.
+func webhookState(bug *Bug) string {
+	state, commits := webhookBugState(bug)
+	if len(commits) != 0 {
+		return string(state) + ":" + strings.Join(commits, "|")
+	}
+	return string(state)
+}
+
+func webhookBugState(bug *Bug) (dashapi.WebhookBugState, []string) {
+	switch bug.Status {
+	case BugStatusOpen:
+		if len(bug.Commits) != 0 {
+			return dashapi.WebhookFixPending, bug.Commits
+		}
+		return dashapi.WebhookOpen, nil
+	case BugStatusFixed:
+		return dashapi.WebhookFixed, bug.Commits
+	case BugStatusInvalid:
+		return dashapi.WebhookInvalid, bug.Commits
+	case BugStatusDup:
+		return dashapi.WebhookDup, nil
+	}
+	return "", nil
+}
+
+func webhookBugStatus(c context.Context, bug *Bug, bugReporting *BugReporting) (*dashapi.WebhookBugStatus, error) {
+	state, commits := webhookBugState(bug)
+	if state == "" {
+		return nil, fmt.Errorf("unknown bug status %v", bug.Status)
+	}
+	status := &dashapi.WebhookBugStatus{
+		ID:         bugReporting.ID,
+		ExtID:      bugReporting.ExtID,
+		Title:      bug.displayTitle(),
+		Link:       fmt.Sprintf("%v/bug?extid=%v", appURL(c), bugReporting.ID),
+		Status:     state,
+		FixCommits: commits,
+	}
+	if bug.Status == BugStatusDup {
+		canon, err := canonicalBug(c, bug)
+		if err != nil {
+			return nil, err
+		}
+		status.DupOfTitle = canon.displayTitle()
+		if canonReporting := bugReportingByName(canon, bugReporting.Name); canonReporting != nil &&
+			!canonReporting.Reported.IsZero() {
+			status.DupOf = canonReporting.ID
+		}
+	}
+	return status, nil
+}
+
+func webhookConfig(data []byte) (*WebhookConfig, error) {
+	cfg := new(WebhookConfig)
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal webhook config: %w", err)
+	}
+	return cfg, nil
+}
+
+// webhookSend sends the event and unmarshals the response into resp (if it's not nil).
+// Note: the caller must clear reporting configs in the event since they contain the secret.
+func webhookSend(c context.Context, cfg *WebhookConfig, event *dashapi.WebhookEvent, resp any) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	log.Infof(c, "sending webhook %v event to %v", event.Type, cfg.URL)
+	data, err := sendWebhook(c, cfg, body)
+	if err != nil {
+		return err
+	}
+	if resp != nil && len(bytes.TrimSpace(data)) != 0 {
+		if err := json.Unmarshal(data, resp); err != nil {
+			return fmt.Errorf("failed to unmarshal webhook response: %w", err)
+		}
+	}
+	return nil
+}
+
+// handleWebhookReply handles comments on reported bugs coming from the webhook side.
+func handleWebhookReply(w http.ResponseWriter, r *http.Request) {
+	c := appengine.NewContext(r)
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookReply))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	reply := new(dashapi.WebhookReply)
+	if err := json.Unmarshal(body, reply); err != nil {
+		http.Error(w, fmt.Sprintf("failed to unmarshal request: %v", err), http.StatusBadRequest)
+		return
+	}
+	bugInfo, cfg, err := loadWebhookBugInfo(c, reply.ID)
+	if err != nil {
+		log.Warningf(c, "webhook reply: %v", err)
+		http.Error(w, "unknown bug", http.StatusBadRequest)
+		return
+	}
+	signature := r.Header.Get(dashapi.WebhookSignatureHeader)
+	if !hmac.Equal([]byte(signature), []byte(dashapi.WebhookSignature(cfg.Secret, body))) {
+		http.Error(w, "bad signature", http.StatusForbidden)
+		return
+	}
+	if stop, err := emergentlyStopped(c); err != nil || stop {
+		log.Errorf(c, "abort webhook reply processing due to emergency stop (stop %v, err %v)", stop, err)
+		http.Error(w, "the service is stopped", http.StatusServiceUnavailable)
+		return
+	}
+	resp := &dashapi.WebhookReplyResponse{
+		Text: processWebhookReply(c, bugInfo, reply),
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Errorf(c, "failed to encode webhook reply response: %v", err)
+	}
+}
+
+func processWebhookReply(c context.Context, bugInfo *bugInfoResult, reply *dashapi.WebhookReply) string {
+	msg := &email.Email{
+		BugIDs:    []string{reply.ID},
+		MessageID: reply.ExtID,
+		Link:      reply.Link,
+		Author:    reply.Author,
+		Body:      reply.Body,
+		Patch:     email.ParsePatch([]byte(reply.Body)),
+		Commands:  email.ParseCommands(reply.Body),
+	}
+	log.Infof(c, "received webhook reply: bug %v, author %q, link %q, %d cmds",
+		reply.ID, msg.Author, msg.Link, len(msg.Commands))
+	excludeSampleCommands(msg)
+	const maxCommands = 3
+	if len(msg.Commands) > maxCommands {
+		return fmt.Sprintf("Too many commands (%d > %d)", len(msg.Commands), maxCommands)
+	}
+	var replies []string
+	for _, command := range msg.Commands {
+		replies = append(replies, handleBugCommand(c, bugInfo, msg, command))
+	}
+	if len(msg.Commands) == 0 {
+		replies = append(replies, handleBugCommand(c, bugInfo, msg, nil))
+	}
+	return groupEmailReplies(replies)
+}
+
+func loadWebhookBugInfo(c context.Context, id string) (*bugInfoResult, *WebhookConfig, error) {
+	bug, bugKey, err := findBugByReportingID(c, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	bugReporting, _ := bugReportingByID(bug, id)
+	if bugReporting == nil {
+		return nil, nil, fmt.Errorf("can't find bug reporting %q", id)
+	}
+	reporting := getNsConfig(c, bug.Namespace).ReportingByName(bugReporting.Name)
+	if reporting == nil {
+		return nil, nil, fmt.Errorf("can't find reporting for this bug: namespace=%q reporting=%q",
+			bug.Namespace, bugReporting.Name)
+	}
+	cfg, ok := reporting.Config.(*WebhookConfig)
+	if !ok {
+		return nil, nil, fmt.Errorf("reporting is not webhook: namespace=%q reporting=%q config=%q",
+			bug.Namespace, bugReporting.Name, reporting.Config.Type())
+	}
+	return &bugInfoResult{bug, bugKey, bugReporting, reporting}, cfg, nil
+}
@@ -12,10 +12,12 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/syzkaller/dashboard/dashapi"
+	"github.com/google/syzkaller/pkg/hash"
 	"golang.org/x/sync/errgroup"
 	db "google.golang.org/appengine/v2/datastore"
 	"google.golang.org/appengine/v2/log"
@@ -164,10 +166,12 @@ func (ctx *bugTreeContext) pollBugTreeJobs(managers map[string]dashapi.ManagerJo
 			return pollResultError(err)
 		}
 	}
-	return ctx.groupResults([]pollTreeJobResult{
-		ctx.setOriginLabels(),
-		ctx.missingBackports(),
-	})
+	var results []pollTreeJobResult
+	if getNsConfig(ctx.c, ctx.bug.Namespace).FindBugOriginTrees {
+		results = append(results, ctx.setOriginLabels(), ctx.missingBackports())
+	}
+	results = append(results, ctx.testAffectedTrees())
+	return ctx.groupResults(results)
 }
 
 func (ctx *bugTreeContext) setOriginLabels() pollTreeJobResult {
@@ -338,6 +342,25 @@ func (ctx *bugTreeContext) missingBackports() pollTreeJobResult {
 	return pollResultSkip{}
 }
 
+// affectedTreesRetestPeriod is how often the reproducer is rerun on each tree
+// to keep the affected trees information up to date.
+const affectedTreesRetestPeriod = 24 * time.Hour * 7
+
+func (ctx *bugTreeContext) testAffectedTrees() pollTreeJobResult {
+	nsConfig := getNsConfig(ctx.c, ctx.bug.Namespace)
+	if !nsConfig.TrackAffectedTrees {
+		return pollResultSkip{}
+	}
+	var results []pollTreeJobResult
+	for _, repo := range nsConfig.Repos {
+		if repo.NoPoll {
+			continue
+		}
+		results = append(results, ctx.runRepro(repo, wantRecent(affectedTreesRetestPeriod), runOnHEAD{}))
+	}
+	return ctx.groupResults(results)
+}
+
 func (ctx *bugTreeContext) lastDone(results []pollTreeJobResult) time.Time {
 	var maxTime time.Time
 	for _, item := range results {
@@ -379,6 +402,7 @@ type wantFirstOK struct{}
 type wantFirstCrash struct{}
 type wantFirstAny struct{}
 type wantNewAny time.Time
+type wantRecent time.Duration // a result that finished within the period
 
 type runReproOn interface{}
 
@@ -436,8 +460,11 @@ func (ctx *bugTreeContext) doRunRepro(repo KernelRepo, result expectedResult, ru
 		bugTreeTest.Error = ""
 	}
 	if bugTreeTest.Last != "" {
-		const fixRetryTime = 24 * time.Hour * 45
-		result := ctx.ensureRepeatPeriod(bugTreeTest.Last, fixRetryTime)
+		retryTime := 24 * time.Hour * 45
+		if period, ok := result.(wantRecent); ok {
+			retryTime = time.Duration(period)
+		}
+		result := ctx.ensureRepeatPeriod(bugTreeTest.Last, retryTime)
 		if _, ok := result.(pollResultSkip); !ok {
 			return result
 		}
@@ -492,7 +519,7 @@ func (bug *Bug) findResult(c context.Context,
 			key = info.FirstCrash
 		case wantFirstAny:
 			key = info.First
-		case wantNewAny:
+		case wantNewAny, wantRecent:
 			key = info.Last
 		default:
 			return pollResultError(fmt.Errorf("unexpected expected result: %T", result)), nil
@@ -509,6 +536,11 @@ func (bug *Bug) findResult(c context.Context,
 				continue
 			}
 		}
+		if period, ok := result.(wantRecent); ok {
+			if timeNow(c).Sub(job.Finished) >= time.Duration(period) {
+				continue
+			}
+		}
 		return pollResultDone{
 			Crashed:  job.CrashTitle != "",
 			Finished: job.Finished,
@@ -713,6 +745,108 @@ func treeTestJobs(c context.Context, bug *Bug) ([]*dashapi.JobInfo, error) {
 	return ret, nil
 }
 
+// Statuses of the bug on a kernel tree.
+const (
+	treeStatusCrashed    = "crashed"
+	treeStatusNotCrashed = "not crashed"
+	treeStatusError      = "error"
+	treeStatusPending    = "pending"
+	treeStatusUntested   = "untested"
+)
+
+// affectedTree is the latest result of running the reproducer on the HEAD of a kernel tree.
+type affectedTree struct {
+	Alias  string
+	Repo   string
+	Branch string
+	Status string
+	// Job is the latest finished job, if any.
+	Job *dashapi.JobInfo
+}
+
+// affectedTrees returns the bug status on each kernel tree of the namespace.
+// The result only depends on the tree test jobs of the bug, so it's cached by their keys.
+func affectedTrees(c context.Context, bug *Bug) ([]*affectedTree, error) {
+	if !getNsConfig(c, bug.Namespace).TrackAffectedTrees {
+		return nil, nil
+	}
+	jobKeys := []string{bug.keyHash(c)}
+	for _, test := range bug.TreeTests.List {
+		jobKeys = append(jobKeys, test.Last, test.Error, test.Pending)
+	}
+	return cachedObjectList(c,
+		fmt.Sprintf("affected-trees-%v", hash.String([]byte(strings.Join(jobKeys, "|")))),
+		24*time.Hour,
+		func(c context.Context) ([]*affectedTree, error) {
+			return loadAffectedTrees(c, bug)
+		},
+	)
+}
+
+func loadAffectedTrees(c context.Context, bug *Bug) ([]*affectedTree, error) {
+	nsConfig := getNsConfig(c, bug.Namespace)
+	var ret []*affectedTree
+	for _, repo := range nsConfig.Repos {
+		if repo.NoPoll {
+			continue
+		}
+		item := &affectedTree{
+			Alias:  repo.Alias,
+			Repo:   repo.URL,
+			Branch: repo.Branch,
+			Status: treeStatusUntested,
+		}
+		ret = append(ret, item)
+		var last *Job
+		var lastKey *db.Key
+		pending := false
+		for _, i := range bug.matchingTreeTests(repo, runOnHEAD{}) {
+			test := &bug.TreeTests.List[i]
+			if test.MergeBaseRepo != "" {
+				continue
+			}
+			pending = pending || test.Pending != ""
+			for _, key := range []string{test.Last, test.Error} {
+				if key == "" {
+					continue
+				}
+				job, jobKey, err := fetchJob(c, key)
+				if err != nil {
+					return nil, err
+				}
+				if last == nil || job.Finished.After(last.Finished) {
+					last, lastKey = job, jobKey
+				}
+			}
+		}
+		if pending {
+			item.Status = treeStatusPending
+		}
+		if last == nil {
+			continue
+		}
+		switch {
+		case pending:
+			// The previous result is still shown, but the tree is being retested.
+		case last.Error != 0:
+			item.Status = treeStatusError
+		case last.CrashTitle != "":
+			item.Status = treeStatusCrashed
+		default:
+			item.Status = treeStatusNotCrashed
+		}
+		var build *Build
+		if last.BuildID != "" {
+			var err error
+			if build, err = loadBuild(c, last.Namespace, last.BuildID); err != nil {
+				return nil, err
+			}
+		}
+		item.Job = makeJobInfo(c, last, lastKey, bug, build, nil)
+	}
+	return ret, nil
+}
+
 // Create a cross-tree bisection job (if needed).
 // Returns:
 // a) Job object and its key -- in case of success.
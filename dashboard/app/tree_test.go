@@ -4,6 +4,8 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"regexp"
@@ -998,6 +1000,117 @@ func TestTreeConfigAppend(t *testing.T) {
 	assert.True(t, tested)
 }
 
+func TestAffectedTrees(t *testing.T) {
+	c := NewCtx(t)
+	defer c.Close()
+
+	ctx := setUpAffectedTreesTest(c, []KernelRepo{
+		{
+			URL:    `https://upstream.repo/repo`,
+			Branch: `upstream-master`,
+			Alias:  `upstream`,
+		},
+		{
+			URL:    `https://lts.repo/repo`,
+			Branch: `lts-master`,
+			Alias:  `lts`,
+		},
+		{
+			URL:    `https://distro.repo/repo`,
+			Branch: `distro-master`,
+			Alias:  `distro`,
+		},
+		{
+			URL:    `https://old.repo/repo`,
+			Branch: `old-master`,
+			Alias:  `old`,
+			NoPoll: true,
+		},
+	})
+	ctx.uploadBug(`https://upstream.repo/repo`, `upstream-master`, dashapi.ReproLevelC)
+	ctx.entries = []treeTestEntry{
+		{
+			alias:   `upstream`,
+			results: []treeTestEntryPeriod{{fromDay: 0, result: treeTestCrash}},
+		},
+		{
+			alias: `lts`,
+			results: []treeTestEntryPeriod{
+				{fromDay: 0, result: treeTestOK},
+				{fromDay: 50, result: treeTestCrash},
+			},
+		},
+		{
+			alias:   `distro`,
+			results: []treeTestEntryPeriod{{fromDay: 0, result: treeTestError}},
+		},
+	}
+	ctx.jobTestDays = []int{10}
+	ctx.moveToDay(10)
+	c.expectEQ(ctx.entries[0].jobsDone, 1)
+	c.expectEQ(ctx.entries[1].jobsDone, 1)
+	c.expectEQ(ctx.entries[2].jobsDone, 1)
+
+	statuses := func() map[string]string {
+		reply, err := c.AuthGET(AccessAdmin, ctx.bugLink()+"&json=1")
+		c.expectOK(err)
+		var info publicAPIBugDescription
+		c.expectOK(json.Unmarshal(reply, &info))
+		ret := map[string]string{}
+		for _, tree := range info.AffectedTrees {
+			ret[tree.Alias] = tree.Status
+		}
+		return ret
+	}
+	c.expectEQ(statuses(), map[string]string{
+		`upstream`: treeStatusCrashed,
+		`lts`:      treeStatusNotCrashed,
+		`distro`:   treeStatusError,
+	})
+	page, err := c.GET(ctx.bugLink())
+	c.expectOK(err)
+	assert.Contains(t, string(page), "Affected trees (3)")
+
+	// The results are periodically refreshed.
+	ctx.jobTestDays = []int{10, 60}
+	ctx.moveToDay(60)
+	c.expectEQ(ctx.entries[1].jobsDone, 2)
+	c.expectEQ(statuses()[`lts`], treeStatusCrashed)
+
+	// A retest is in progress, the older result must not hide it.
+	c.advanceTime(affectedTreesRetestPeriod)
+	pollResp := ctx.client.pollSpecificJobs(ctx.manager, dashapi.ManagerJobs{TestPatches: true})
+	c.expectEQ(pollResp.KernelRepo, `https://upstream.repo/repo`)
+	c.expectEQ(statuses(), map[string]string{
+		`upstream`: treeStatusPending,
+		`lts`:      treeStatusCrashed,
+		`distro`:   treeStatusError,
+	})
+}
+
+func setUpAffectedTreesTest(ctx *Ctx, repos []KernelRepo) *treeTestCtx {
+	ret := &treeTestCtx{
+		ctx:      ctx,
+		client:   ctx.makeClient(clientTreeTests, keyTreeTests, true),
+		manager:  "test-manager",
+		perAlias: map[string]KernelRepo{},
+	}
+	for _, repo := range repos {
+		ret.perAlias[repo.Alias] = repo
+	}
+	ctx.transformContext = func(c context.Context) context.Context {
+		newConfig := replaceNamespaceConfig(c, "tree-tests", func(cfg *Config) *Config {
+			ret := *cfg
+			ret.Repos = repos
+			ret.FindBugOriginTrees = false
+			ret.TrackAffectedTrees = true
+			return &ret
+		})
+		return contextWithConfig(c, newConfig)
+	}
+	return ret
+}
+
 func setUpTreeTest(ctx *Ctx, repos []KernelRepo) *treeTestCtx {
 	ret := &treeTestCtx{
 		ctx:     ctx,
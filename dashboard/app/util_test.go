@@ -9,6 +9,7 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -42,6 +43,7 @@ type Ctx struct {
 	ctx              context.Context
 	mockedTime       time.Time
 	emailSink        chan *aemail.Message
+	webhookSink      chan *dashapi.WebhookEvent
 	transformContext func(context.Context) context.Context
 	client           *apiClient
 	client2          *apiClient
@@ -77,6 +79,7 @@ func NewCtx(t *testing.T) *Ctx {
 		inst:             inst,
 		mockedTime:       time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
 		emailSink:        make(chan *aemail.Message, 100),
+		webhookSink:      make(chan *dashapi.WebhookEvent, 100),
 		transformContext: func(c context.Context) context.Context { return c },
 	}
 	c.client = c.makeClient(client1, password1, true)
@@ -196,6 +199,12 @@ func (c *Ctx) Close() {
 		for len(c.emailSink) != 0 {
 			c.t.Errorf("ERROR: leftover email: %v", (<-c.emailSink).Body)
 		}
+		// No pending webhook events (tests need to consume them).
+		_, err = c.GET("/cron/webhook_poll")
+		c.expectOK(err)
+		for len(c.webhookSink) != 0 {
+			c.t.Errorf("ERROR: leftover webhook event: %+v", <-c.webhookSink)
+		}
 		// No pending external reports (tests need to consume them).
 		resp, _ := c.client.ReportingPollBugs("test")
 		for _, rep := range resp.Reports {
@@ -490,6 +499,26 @@ func (c *Ctx) expectNoEmail() {
 	}
 }
 
+func (c *Ctx) pollWebhook() *dashapi.WebhookEvent {
+	_, err := c.GET("/cron/webhook_poll")
+	c.expectOK(err)
+	if len(c.webhookSink) == 0 {
+		c.t.Helper()
+		c.t.Fatal("got no webhook events")
+	}
+	return <-c.webhookSink
+}
+
+func (c *Ctx) expectNoWebhook() {
+	_, err := c.GET("/cron/webhook_poll")
+	c.expectOK(err)
+	if len(c.webhookSink) != 0 {
+		event := <-c.webhookSink
+		c.t.Helper()
+		c.t.Fatalf("got unexpected webhook event: %+v", event)
+	}
+}
+
 type apiClient struct {
 	*Ctx
 	*dashapi.Dashboard
@@ -664,6 +693,21 @@ func initMocks() {
 		getRequestContext(c).emailSink <- msg
 		return nil
 	}
+	sendWebhook = func(c context.Context, cfg *WebhookConfig, body []byte) ([]byte, error) {
+		event := new(dashapi.WebhookEvent)
+		if err := json.Unmarshal(body, event); err != nil {
+			return nil, err
+		}
+		getRequestContext(c).webhookSink <- event
+		if event.Type != dashapi.WebhookReport {
+			return nil, nil
+		}
+		// Pretend that the tracker has filed an issue for the bug.
+		return json.Marshal(&dashapi.WebhookResponse{
+			ExtID: "issue-" + event.Report.ID,
+			Link:  "https://tracker.syzkaller.com/issue/" + event.Report.ID,
+		})
+	}
 	maxCrashes = func() int {
 		// dev_appserver is very slow, so let's make tests smaller.
 		const maxCrashesDuringTest = 20
@@ -0,0 +1,133 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/syzkaller/dashboard/dashapi"
+)
+
+func TestWebhookReport(t *testing.T) {
+	c := NewCtx(t)
+	defer c.Close()
+
+	client := c.makeClient(clientWebhook, keyWebhook, true)
+	build := testBuild(1)
+	client.UploadBuild(build)
+	crash := testCrash(build, 1)
+	crash.ReproSyz = []byte("getpid()")
+	client.ReportCrash(crash)
+
+	event := c.pollWebhook()
+	c.expectEQ(event.Type, dashapi.WebhookReport)
+	rep := event.Report
+	c.expectEQ(rep.Title, crash.Title)
+	c.expectEQ(string(rep.ReproSyz), "getpid()")
+	// The config contains the secret, it must not be sent.
+	c.expectEQ(len(rep.Config), 0)
+	c.expectNoWebhook()
+
+	bug, _, _ := c.loadBug(rep.ID)
+	c.expectEQ(bug.Reporting[0].ExtID, "issue-"+rep.ID)
+	c.expectEQ(bug.Reporting[0].Link, "https://tracker.syzkaller.com/issue/"+rep.ID)
+	c.expectEQ(bug.ReproLevel, dashapi.ReproLevelSyz)
+
+	// Replies with bad signatures must be rejected.
+	reply := &dashapi.WebhookReply{
+		ID:     rep.ID,
+		ExtID:  "issue-" + rep.ID,
+		Author: "someone@syzkaller.com",
+		Body:   "#syz invalid",
+	}
+	code, _ := c.webhookReply(reply, "wrongsecretwrongsecret")
+	c.expectEQ(code, http.StatusForbidden)
+	bug, _, _ = c.loadBug(rep.ID)
+	c.expectEQ(bug.Status, BugStatusOpen)
+
+	reply.Body = "#syz fix: some: commit title"
+	c.expectEQ(c.expectWebhookReply(reply), "")
+	event = c.pollWebhook()
+	c.expectEQ(event.Type, dashapi.WebhookStatus)
+	c.expectEQ(event.Status.ID, rep.ID)
+	c.expectEQ(event.Status.ExtID, "issue-"+rep.ID)
+	c.expectEQ(event.Status.Status, dashapi.WebhookFixPending)
+	c.expectEQ(event.Status.FixCommits, []string{"some: commit title"})
+	c.expectNoWebhook()
+
+	reply.Body = "#syz unknown-command"
+	c.expectEQ(c.expectWebhookReply(reply), `unknown command "unknown-command"`)
+	c.expectNoWebhook()
+
+	reply.Body = "#syz invalid"
+	c.expectEQ(c.expectWebhookReply(reply), "")
+	event = c.pollWebhook()
+	c.expectEQ(event.Type, dashapi.WebhookStatus)
+	c.expectEQ(event.Status.Status, dashapi.WebhookInvalid)
+	c.expectEQ(len(event.Status.FixCommits), 0)
+	c.expectNoWebhook()
+}
+
+func TestWebhookDup(t *testing.T) {
+	c := NewCtx(t)
+	defer c.Close()
+
+	client := c.makeClient(clientWebhook, keyWebhook, true)
+	build := testBuild(1)
+	client.UploadBuild(build)
+	crash1 := testCrash(build, 1)
+	client.ReportCrash(crash1)
+	rep1 := c.pollWebhook().Report
+	crash2 := testCrash(build, 2)
+	client.ReportCrash(crash2)
+	rep2 := c.pollWebhook().Report
+
+	reply := &dashapi.WebhookReply{
+		ID:     rep2.ID,
+		Author: "someone@syzkaller.com",
+		Body:   "#syz dup: " + crash1.Title,
+	}
+	c.expectEQ(c.expectWebhookReply(reply), "")
+	event := c.pollWebhook()
+	c.expectEQ(event.Type, dashapi.WebhookStatus)
+	c.expectEQ(event.Status.ID, rep2.ID)
+	c.expectEQ(event.Status.Status, dashapi.WebhookDup)
+	c.expectEQ(event.Status.DupOf, rep1.ID)
+	c.expectEQ(event.Status.DupOfTitle, crash1.Title)
+	c.expectNoWebhook()
+}
+
+func (c *Ctx) expectWebhookReply(reply *dashapi.WebhookReply) string {
+	c.t.Helper()
+	code, body := c.webhookReply(reply, testWebhookSecret)
+	if code != http.StatusOK {
+		c.t.Fatalf("webhook reply failed: %v: %s", code, body)
+	}
+	resp := new(dashapi.WebhookReplyResponse)
+	if err := json.Unmarshal(body, resp); err != nil {
+		c.t.Fatalf("failed to unmarshal webhook reply response: %v\n%s", err, body)
+	}
+	return resp.Text
+}
+
+func (c *Ctx) webhookReply(reply *dashapi.WebhookReply, secret string) (int, []byte) {
+	body, err := json.Marshal(reply)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	r, err := c.inst.NewRequest("POST", "/webhook/reply", bytes.NewReader(body))
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	r.Header.Set(dashapi.WebhookSignatureHeader, dashapi.WebhookSignature(secret, body))
+	r = registerRequest(r, c)
+	r = r.WithContext(c.transformContext(r.Context()))
+	w := httptest.NewRecorder()
+	http.DefaultServeMux.ServeHTTP(w, r)
+	return w.Code, w.Body.Bytes()
+}
@@ -8,6 +8,9 @@ package dashapi
 import (
 	"bytes"
 	"compress/gzip"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -172,13 +175,15 @@ type JobPollReq struct {
 }
 
 type ManagerJobs struct {
-	TestPatches bool
-	BisectCause bool
-	BisectFix   bool
+	TestPatches  bool
+	BisectCause  bool
+	BisectFix    bool
+	BisectConfig bool
+	FixSearch    bool
 }
 
 func (m ManagerJobs) Any() bool {
-	return m.TestPatches || m.BisectCause || m.BisectFix
+	return m.TestPatches || m.BisectCause || m.BisectFix || m.BisectConfig || m.FixSearch
 }
 
 type JobPollResp struct {
@@ -200,6 +205,11 @@ type JobPollResp struct {
 	ReproOpts         []byte
 	ReproSyz          []byte
 	ReproC            []byte
+	// Fix search looks for commits that modify the guilty function (the top stack frame)
+	// in the guilty file and ranks them by similarity to the crash.
+	CrashTitle  string
+	GuiltyFile  string
+	StackFrames []string
 }
 
 type JobDoneReq struct {
@@ -219,6 +229,8 @@ type JobDoneReq struct {
 	// If there are more than 1: suspected commits due to skips (broken build/boot).
 	Commits []Commit
 	Flags   JobDoneFlags
+	// Config bisection result: options that need to be enabled in the baseline config.
+	ConfigOptions []string
 }
 
 type JobType int
@@ -227,16 +239,20 @@ const (
 	JobTestPatch JobType = iota
 	JobBisectCause
 	JobBisectFix
+	JobBisectConfig
+	JobFixSearch
 )
 
 type JobDoneFlags int64
 
 const (
-	BisectResultMerge      JobDoneFlags = 1 << iota // bisected to a merge commit
-	BisectResultNoop                                // commit does not affect resulting kernel binary
-	BisectResultRelease                             // commit is a kernel release
-	BisectResultIgnore                              // this particular commit should be ignored, see syz-ci/jobs.go
-	BisectResultInfraError                          // the bisect failed due to an infrastructure problem
+	BisectResultMerge       JobDoneFlags = 1 << iota // bisected to a merge commit
+	BisectResultNoop                                 // commit does not affect resulting kernel binary
+	BisectResultRelease                              // commit is a kernel release
+	BisectResultIgnore                               // this particular commit should be ignored, see syz-ci/jobs.go
+	BisectResultInfraError                           // the bisect failed due to an infrastructure problem
+	BisectResultConfirmed                            // the crash is not reproduced with the commit reverted
+	BisectResultUnconfirmed                          // the crash is still reproduced with the commit reverted
 )
 
 func (flags JobDoneFlags) String() string {
@@ -256,6 +272,9 @@ func (flags JobDoneFlags) String() string {
 	if flags&BisectResultIgnore != 0 {
 		res += "ignored "
 	}
+	if flags&BisectResultUnconfirmed != 0 {
+		res += "unconfirmed "
+	}
 	if res == "" {
 		return res
 	}
@@ -378,6 +397,33 @@ func (dash *Dashboard) ReportFailedRepro(crash *CrashID) error {
 	return dash.Query("report_failed_repro", crash, nil)
 }
 
+// SeriesFindingsReq describes crashes found while fuzzing a proposed patch series
+// that are not known for the base kernel tree.
+type SeriesFindingsReq struct {
+	Manager string
+	// The series thread on the mailing list.
+	MessageID string
+	Subject   string
+	Link      string
+	// Cc contains the author and the recipients of the series.
+	Cc           []string
+	KernelRepo   string
+	KernelBranch string
+	BaseCommit   string
+	Findings     []SeriesFinding
+}
+
+type SeriesFinding struct {
+	Title    string
+	Report   []byte
+	ReproSyz []byte
+}
+
+// ReportSeriesFindings asks the dashboard to reply to the series thread with the findings.
+func (dash *Dashboard) ReportSeriesFindings(req *SeriesFindingsReq) error {
+	return dash.Query("series_findings", req, nil)
+}
+
 type LogToReproReq struct {
 	BuildID string
 }
@@ -522,6 +568,9 @@ type BisectResult struct {
 	CrossTree       bool
 	// In case a missing backport was backported.
 	Backported *Commit
+	// Results of reverting the culprit on top of the crashing commit (if it was done).
+	Confirmed   bool
+	Unconfirmed bool
 }
 
 type BugListReport struct {
@@ -647,6 +696,80 @@ type PollClosedResponse struct {
 	IDs []string
 }
 
+// WebhookEvent is POSTed by the dashboard to the endpoint of a webhook reporting.
+// Report is set for WebhookReport/WebhookJob events, Notification for WebhookNotification,
+// and Status for WebhookStatus events.
+type WebhookEvent struct {
+	Type         WebhookEventType
+	Report       *BugReport
+	Notification *BugNotification
+	Status       *WebhookBugStatus
+}
+
+type WebhookEventType string
+
+const (
+	WebhookReport       WebhookEventType = "report"
+	WebhookJob          WebhookEventType = "job"
+	WebhookNotification WebhookEventType = "notification"
+	WebhookStatus       WebhookEventType = "status"
+)
+
+// WebhookBugStatus notifies about bug status changes that happen outside of the webhook reporting
+// (e.g. the bug is fixed by a commit, or is marked as a duplicate on the dashboard).
+type WebhookBugStatus struct {
+	ID         string
+	ExtID      string
+	Title      string
+	Link       string
+	Status     WebhookBugState
+	FixCommits []string // for WebhookFixPending and WebhookFixed
+	DupOf      string   // for WebhookDup, ID of the canonical bug in this reporting (if reported there)
+	DupOfTitle string   // for WebhookDup
+}
+
+type WebhookBugState string
+
+const (
+	WebhookOpen       WebhookBugState = "open"
+	WebhookFixPending WebhookBugState = "fix-pending"
+	WebhookFixed      WebhookBugState = "fixed"
+	WebhookDup        WebhookBugState = "dup"
+	WebhookInvalid    WebhookBugState = "invalid"
+)
+
+// WebhookResponse may be returned by the endpoint in response to WebhookReport events
+// to associate the bug with an issue in the tracker.
+type WebhookResponse struct {
+	ExtID string
+	Link  string
+}
+
+// WebhookReply is POSTed to the dashboard /webhook/reply endpoint when somebody comments
+// on a reported bug in the tracker. Body may contain the same #syz commands as emails.
+type WebhookReply struct {
+	ID     string // BugReport.ID
+	ExtID  string // ID of the comment
+	Link   string // link to the comment
+	Author string // email of the comment author
+	Body   string
+}
+
+type WebhookReplyResponse struct {
+	// Text to post back to the tracker (e.g. errors in commands), may be empty.
+	Text string
+}
+
+// WebhookSignatureHeader holds HMAC-SHA256 of the request body keyed with the webhook secret.
+// It's set for requests in both directions.
+const WebhookSignatureHeader = "X-Syzbot-Signature"
+
+func WebhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
 type DiscussionSource string
 
 const (
@@ -788,6 +911,10 @@ type ManagerStatsReq struct {
 	// Non-zero only when set.
 	TriagedCoverage uint64
 	TriagedPCs      uint64
+	// Set once after the machine check, MachineCheckDiff then contains changes
+	// in enabled syscalls/features since the previous manager run (empty if there are none).
+	MachineChecked   bool
+	MachineCheckDiff string
 }
 
 func (dash *Dashboard) UploadManagerStats(req *ManagerStatsReq) error {
@@ -967,6 +1094,7 @@ type JobInfo struct {
 	ReproSyzLink     string
 	Commit           *Commit   // for conclusive bisection
 	Commits          []*Commit // for inconclusive bisection
+	ConfigOptions    []string  // for config bisection
 	Reported         bool
 	InvalidatedBy    string
 	TreeOrigin       bool
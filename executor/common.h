@@ -743,6 +743,8 @@ static void loop(void)
 
 #if !SYZ_EXECUTOR
 
+/*{{{TYPES}}}*/
+
 /*{{{RESULTS}}}*/
 
 #if SYZ_THREADED || SYZ_REPEAT || SYZ_SANDBOX_NONE || SYZ_SANDBOX_SETUID || SYZ_SANDBOX_NAMESPACE || SYZ_SANDBOX_ANDROID
@@ -761,8 +763,13 @@ void loop(void)
 }
 #endif
 
+#if SYZ_KSELFTEST
+// In kselftest mode the reproducer runs in a child process of the test harness below.
+static int run_reproducer(void)
+#else
 // This is the main function for csource.
 int main(void)
+#endif
 {
 	/*{{{MMAP_DATA}}}*/
 
@@ -830,4 +837,134 @@ int main(void)
 #endif
 	return 0;
 }
+
+#if SYZ_KSELFTEST
+#include <errno.h>
+#include <fcntl.h>
+#include <regex.h>
+#include <signal.h>
+#include <sys/wait.h>
+#include <time.h>
+#include <unistd.h>
+
+#define KSFT_PASS 0
+#define KSFT_FAIL 1
+#define KSFT_SKIP 4
+
+// A kernel log message belongs to the crash if it contains all kselftest_match strings
+// and does not match any of kselftest_suppressions.
+static const char* kselftest_match[] = {/*{{{KSELFTEST_MATCH}}}*/};
+static const char* kselftest_suppressions[] = {/*{{{KSELFTEST_SUPPRESSIONS}}}*/};
+
+static uint64 kselftest_time_ms(void)
+{
+	struct timespec ts;
+	clock_gettime(CLOCK_MONOTONIC, &ts);
+	return (uint64)ts.tv_sec * 1000 + (uint64)ts.tv_nsec / 1000000;
+}
+
+static int kselftest_is_crash(const char* msg)
+{
+	for (int i = 0; kselftest_match[i]; i++) {
+		if (!strstr(msg, kselftest_match[i]))
+			return 0;
+	}
+	for (int i = 0; kselftest_suppressions[i]; i++) {
+		regex_t re;
+		if (regcomp(&re, kselftest_suppressions[i], REG_EXTENDED | REG_NOSUB)) {
+			printf("# bad suppression: %s\n", kselftest_suppressions[i]);
+			continue;
+		}
+		int suppressed = regexec(&re, msg, 0, NULL, 0) == 0;
+		regfree(&re);
+		if (suppressed)
+			return 0;
+	}
+	return 1;
+}
+
+// Reads new kernel log records and returns 1 if any of them belongs to the crash.
+static int kselftest_check_kmsg(int fd)
+{
+	size_t size = 8192;
+	char* buf = (char*)malloc(size);
+	int crashed = 0;
+	for (;;) {
+		ssize_t n = read(fd, buf, size - 1);
+		if (n < 0 && errno == EPIPE)
+			continue; // some records were overwritten in the ring buffer
+		if (n < 0 && errno == EINVAL && size < (1 << 20)) {
+			// The record does not fit into the buffer, it stays unread until a larger buffer is used.
+			size *= 2;
+			buf = (char*)realloc(buf, size);
+			continue;
+		}
+		if (n < 0 && errno == EINVAL) {
+			printf("# failed to read too large kernel log record\n");
+			break;
+		}
+		if (n <= 0)
+			break;
+		buf[n] = 0;
+		// Records have the form "prio,seq,time,flags;message\n".
+		char* msg = strchr(buf, ';');
+		msg = msg ? msg + 1 : buf;
+		if (kselftest_is_crash(msg)) {
+			printf("# kernel: %s", msg);
+			crashed = 1;
+		}
+	}
+	free(buf);
+	return crashed;
+}
+
+// This is the main function for csource in kselftest mode.
+// It reports the result in the TAP format used by kselftests.
+int main(void)
+{
+	const char* title = /*{{{KSELFTEST_TITLE}}}*/;
+	printf("TAP version 13\n1..1\n");
+	fflush(stdout);
+	int kmsg = open("/dev/kmsg", O_RDONLY | O_NONBLOCK);
+	if (kmsg == -1) {
+		printf("ok 1 %s # SKIP cannot open /dev/kmsg: %s\n", title, strerror(errno));
+		return KSFT_SKIP;
+	}
+	// Check only messages printed after the test has started.
+	lseek(kmsg, 0, SEEK_END);
+	int pid = fork();
+	if (pid < 0) {
+		printf("not ok 1 %s # fork: %s\n", title, strerror(errno));
+		return KSFT_FAIL;
+	}
+	if (pid == 0) {
+		setpgid(0, 0);
+		exit(run_reproducer());
+	}
+	int status = 0;
+	int timedout = 1;
+	uint64 start = kselftest_time_ms();
+	while (kselftest_time_ms() - start < /*{{{KSELFTEST_TIMEOUT_MS}}}*/) {
+		if (waitpid(pid, &status, WNOHANG) == pid) {
+			timedout = 0;
+			break;
+		}
+		usleep(10 * 1000);
+	}
+	if (timedout) {
+		printf("# the reproducer did not finish in time, killing it\n");
+		kill(-pid, SIGKILL);
+		kill(pid, SIGKILL);
+		waitpid(pid, &status, 0);
+	}
+	// Give the kernel some time to print the crash report.
+	sleep(1);
+	if (kselftest_check_kmsg(kmsg)) {
+		printf("not ok 1 %s\n", title);
+		return KSFT_FAIL;
+	}
+	printf("ok 1 %s\n", title);
+	return KSFT_PASS;
+}
+#endif
 #endif
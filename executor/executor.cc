@@ -16,6 +16,7 @@
 #include <time.h>
 
 #include <atomic>
+#include <chrono>
 #include <optional>
 
 #if !GOOS_windows
@@ -382,6 +383,9 @@ struct thread_t {
 	bool fault_injected;
 	cover_t cov;
 	bool soft_fail_state;
+	// Wall-clock and kernel execution time of the call (ns).
+	uint64 elapsed;
+	uint64 kernel_time;
 };
 
 static thread_t threads[kMaxThreads];
@@ -525,6 +529,19 @@ bool CoverAccessScope::used_;
 static feature_t features[] = {};
 #endif
 
+#if !SYZ_HAVE_KERNEL_TIME
+static uint64 thread_kernel_time_ns()
+{
+	return 0;
+}
+#endif
+
+static uint64 current_time_ns()
+{
+	auto now = std::chrono::steady_clock::now().time_since_epoch();
+	return std::chrono::duration_cast<std::chrono::nanoseconds>(now).count();
+}
+
 #include "shmem.h"
 
 #include "conn.h"
@@ -565,6 +582,11 @@ int main(int argc, char** argv)
 	}
 	if (strcmp(argv[1], "test") == 0)
 		return run_tests(argc == 3 ? argv[2] : nullptr);
+	if (strcmp(argv[1], "version") == 0) {
+		// Used by the manager as a liveness check of the binary before reusing a VM.
+		printf("%s %s\n", GIT_REVISION, SYZ_REVISION);
+		return 0;
+	}
 
 	if (strcmp(argv[1], "exec") != 0) {
 		fprintf(stderr, "unknown command");
@@ -862,6 +884,8 @@ void execute_one()
 	}
 
 	int call_index = 0;
+	// Number of calls executed before the prefix snapshot was taken (only in snapshot mode).
+	int skip_calls = 0;
 	uint64 prog_extra_timeout = 0;
 	uint64 prog_extra_cover_timeout = 0;
 	call_props_t call_props;
@@ -989,6 +1013,14 @@ void execute_one()
 			args[i] = read_arg(&input_pos);
 		for (uint64 i = num_args; i < kMaxArgs; i++)
 			args[i] = 0;
+		if (call_index < skip_calls) {
+			// The call was already executed before the prefix snapshot was taken,
+			// and its results are still in memory. Note: copyin of its arguments above
+			// just rewrites the same values.
+			call_index++;
+			memset(&call_props, 0, sizeof(call_props));
+			continue;
+		}
 		thread_t* th = schedule_call(call_index++, call_num, copyout_index,
 					     num_args, args, input_pos, call_props);
 
@@ -1022,6 +1054,15 @@ void execute_one()
 			handle_completion(th);
 		}
 		memset(&call_props, 0, sizeof(call_props));
+		// We can take prefix snapshot only if all previous calls have finished.
+		if (flag_snapshot && call_index == snapshot_prefix && running == 0 && SnapshotPrefix()) {
+			// Restart parsing of the new program and skip the prefix.
+			skip_calls = call_index;
+			call_index = 0;
+			start = current_time_ms();
+			input_pos = input_data;
+			read_input(&input_pos); // total number of calls
+		}
 	}
 
 	if (running > 0) {
@@ -1267,7 +1308,8 @@ void copyout_call_results(thread_t* th)
 	}
 }
 
-void write_output(int index, cover_t* cov, rpc::CallFlag flags, uint32 error, bool all_signal)
+void write_output(int index, cover_t* cov, rpc::CallFlag flags, uint32 error, bool all_signal,
+		  uint64 elapsed, uint64 kernel_time)
 {
 	CoverAccessScope scope(cov);
 	auto& fbb = *output_builder;
@@ -1302,6 +1344,8 @@ void write_output(int index, cover_t* cov, rpc::CallFlag flags, uint32 error, bo
 		builder.add_cover(cover_off);
 	if (comps_off)
 		builder.add_comps(comps_off);
+	builder.add_elapsed(elapsed);
+	builder.add_kernel_time(kernel_time);
 	auto off = builder.Finish();
 	uint32 slot = output_data->completed.load(std::memory_order_relaxed);
 	if (slot >= kMaxCalls)
@@ -1318,17 +1362,21 @@ void write_output(int index, cover_t* cov, rpc::CallFlag flags, uint32 error, bo
 void write_call_output(thread_t* th, bool finished)
 {
 	uint32 reserrno = ENOSYS;
+	uint64 elapsed = 0;
+	uint64 kernel_time = 0;
 	rpc::CallFlag flags = rpc::CallFlag::Executed;
 	if (finished && th != last_scheduled)
 		flags |= rpc::CallFlag::Blocked;
 	if (finished) {
 		reserrno = th->res != -1 ? 0 : th->reserrno;
+		elapsed = th->elapsed;
+		kernel_time = th->kernel_time;
 		flags |= rpc::CallFlag::Finished;
 		if (th->fault_injected)
 			flags |= rpc::CallFlag::FaultInjected;
 	}
 	bool all_signal = th->call_index < 64 ? (all_call_signal & (1ull << th->call_index)) : false;
-	write_output(th->call_index, &th->cov, flags, reserrno, all_signal);
+	write_output(th->call_index, &th->cov, flags, reserrno, all_signal, elapsed, kernel_time);
 }
 
 void write_extra_output()
@@ -1338,7 +1386,7 @@ void write_extra_output()
 	cover_collect(&extra_cov);
 	if (!extra_cov.size)
 		return;
-	write_output(-1, &extra_cov, rpc::CallFlag::NONE, 997, all_extra_signal);
+	write_output(-1, &extra_cov, rpc::CallFlag::NONE, 997, all_extra_signal, 0, 0);
 	cover_reset(&extra_cov);
 }
 
@@ -1452,10 +1500,14 @@ void execute_call(thread_t* th)
 		cover_reset(&th->cov);
 	// For pseudo-syscalls and user-space functions NONFAILING can abort before assigning to th->res.
 	// Arrange for res = -1 and errno = EFAULT result for such case.
+	uint64 start_kernel_time = thread_kernel_time_ns();
+	uint64 start_time = current_time_ns();
 	th->res = -1;
 	errno = EFAULT;
 	NONFAILING(th->res = execute_syscall(call, th->args));
 	th->reserrno = errno;
+	th->elapsed = current_time_ns() - start_time;
+	th->kernel_time = thread_kernel_time_ns() - start_kernel_time;
 	// Our pseudo-syscalls may misbehave.
 	if ((th->res == -1 && th->reserrno == 0) || call->attrs.ignore_return)
 		th->reserrno = EINVAL;
@@ -8,6 +8,7 @@
 #include <sys/ioctl.h>
 #include <sys/mman.h>
 #include <sys/prctl.h>
+#include <sys/resource.h>
 #include <sys/syscall.h>
 #include <unistd.h>
 
@@ -92,6 +93,15 @@ static intptr_t execute_syscall(const call_t* c, intptr_t a[kMaxArgs])
 	return syscall(c->sys_nr, a[0], a[1], a[2], a[3], a[4], a[5]);
 }
 
+#define SYZ_HAVE_KERNEL_TIME 1
+static uint64 thread_kernel_time_ns()
+{
+	struct rusage usage;
+	if (getrusage(RUSAGE_THREAD, &usage))
+		return 0;
+	return (uint64)usage.ru_stime.tv_sec * 1000000000 + (uint64)usage.ru_stime.tv_usec * 1000;
+}
+
 static void cover_open(cover_t* cov, bool extra)
 {
 	int fd = open("/sys/kernel/debug/kcov", O_RDWR);
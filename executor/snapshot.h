@@ -36,6 +36,9 @@ static struct {
 	void* input;
 } ivs;
 
+// If non-zero, the number of program calls after which the host wants to take a prefix snapshot.
+static int snapshot_prefix;
+
 // Finds qemu ivshmem device, see:
 // https://www.qemu.org/docs/master/specs/ivshmem-spec.html
 static void FindIvshmemDevices()
@@ -192,6 +195,8 @@ static void SnapshotPrepareParent()
 }
 #endif
 
+static void SnapshotParseRequest();
+
 static void SnapshotStart()
 {
 	debug("SnapshotStart\n");
@@ -232,6 +237,11 @@ static void SnapshotStart()
 			sleep(1000);
 	}
 	// Resumed for program execution.
+	SnapshotParseRequest();
+}
+
+static void SnapshotParseRequest()
+{
 	output_data->Reset();
 	auto msg = flatbuffers::GetRoot<rpc::SnapshotRequest>(ivs.input);
 	execute_req req = {
@@ -244,6 +254,33 @@ static void SnapshotStart()
 	parse_execute(req);
 	output_data->num_calls.store(msg->num_calls(), std::memory_order_relaxed);
 	input_data = const_cast<uint8*>(msg->prog_data()->Data());
+	snapshot_prefix = msg->snapshot_prefix();
+}
+
+// SnapshotPrefix is called after the first snapshot_prefix calls of the program have finished.
+// It lets the host take a snapshot of the current state, so that other programs with the same
+// prefix can be executed starting from this point.
+// Returns true if we were restored from the prefix snapshot to execute a new program
+// (the new program is already in input_data).
+static bool SnapshotPrefix()
+{
+	debug("SnapshotPrefix\n");
+	{
+		CoverAccessScope scope(nullptr);
+		SnapshotSetState(rpc::SnapshotState::PrefixReady);
+		// Same as in SnapshotStart we busy loop here to not be snapshotted inside of a syscall.
+		while (ivs.hdr->state == rpc::SnapshotState::PrefixReady)
+			;
+		if (ivs.hdr->state == rpc::SnapshotState::Snapshotted) {
+			// The snapshot is taken, continue executing the current program.
+			snapshot_prefix = 0;
+			return false;
+		}
+		// Resumed for execution of a new program with the same prefix.
+		SnapshotParseRequest();
+	}
+	output_builder.emplace(output_data, output_size, false);
+	return true;
 }
 
 NORETURN static void SnapshotDone(bool failed)
@@ -9,6 +9,7 @@ import (
 	"math"
 	"os"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/syzkaller/pkg/build"
@@ -38,10 +39,23 @@ type Config struct {
 	BuildSemaphore  *instance.Semaphore
 	TestSemaphore   *instance.Semaphore
 	BuildCPUs       int
+	// BuildCache, if set, is used for incremental kernel builds and
+	// to reuse the kernels built for the same commits before.
+	BuildCache *build.Cache
 	// CrossTree specifies whether a cross tree bisection is to take place, i.e.
 	// Kernel.Commit is not reachable from Kernel.Branch.
 	// In this case, bisection starts from their merge base.
 	CrossTree bool
+	// VerifyCulprit enables verification of the cause bisection result:
+	// the culprit is reverted on top of Kernel.Commit and the reproducer is run once more.
+	VerifyCulprit bool
+	// ConfigBisect requests bisection of the kernel config instead of commits:
+	// find the minimal set of options that need to be enabled in Kernel.BaselineConfig
+	// to trigger the crash on Kernel.Commit.
+	ConfigBisect bool
+	// FixSearch requests a search for the fixing commit among the commits that modify
+	// the guilty function between Kernel.Commit and HEAD instead of fix bisection.
+	FixSearch *FixSearch
 }
 
 type KernelConfig struct {
@@ -101,6 +115,9 @@ type env struct {
 	flaky bool
 	// A cache of already performed revision tests.
 	results map[string]*testResult
+	// Whether the kernel sources have uncommitted changes (e.g. a revert),
+	// in such case the built kernel must not be cached.
+	uncommitted bool
 }
 
 const MaxNumTests = 20 // number of tests we do per commit
@@ -124,16 +141,33 @@ const MaxNumTests = 20 // number of tests we do per commit
 //   - Commit points to the oldest/latest commit where crash happens.
 //
 // 4. Config contains kernel config used for bisection.
+//
+// 5. Verification is the result of reverting the single cause commit (if Config.VerifyCulprit is set).
+//
+// 6. For config bisection, ConfigOptions contains the options that trigger the crash,
+// Config is the baseline config with these options enabled, Commit is the tested commit.
+//
+// 7. For fix search, the fixing commit (if found) in Commits.
 type Result struct {
-	Commits    []*vcs.Commit
-	Report     *report.Report
-	Commit     *vcs.Commit
-	Config     []byte
-	NoopChange bool
-	IsRelease  bool
-	Confidence float64
+	Commits       []*vcs.Commit
+	Report        *report.Report
+	Commit        *vcs.Commit
+	Config        []byte
+	NoopChange    bool
+	IsRelease     bool
+	Confidence    float64
+	Verification  CulpritVerification
+	ConfigOptions []string
 }
 
+type CulpritVerification int
+
+const (
+	CulpritNotVerified CulpritVerification = iota
+	CulpritConfirmed                       // the crash is not reproduced with the culprit reverted
+	CulpritUnconfirmed                     // the crash is still reproduced with the culprit reverted
+)
+
 type InfraError struct {
 	Title string
 }
@@ -192,13 +226,24 @@ func runImpl(cfg *Config, repo vcs.Repo, inst instance.Env) (*Result, error) {
 		hostname = "unnamed host"
 	}
 	env.logf("%s starts bisection %s", hostname, env.startTime.String())
-	if cfg.Fix {
+	if cfg.ConfigBisect {
+		env.logf("bisecting kernel config on %v", cfg.Kernel.Commit)
+	} else if cfg.FixSearch != nil {
+		env.logf("searching for fixing commit since %v", cfg.Kernel.Commit)
+	} else if cfg.Fix {
 		env.logf("bisecting fixing commit since %v", cfg.Kernel.Commit)
 	} else {
 		env.logf("bisecting cause commit starting from %v", cfg.Kernel.Commit)
 	}
 	start := time.Now()
-	res, err := env.bisect()
+	var res *Result
+	if cfg.ConfigBisect {
+		res, err = env.bisectConfig()
+	} else if cfg.FixSearch != nil {
+		res, err = env.searchFix()
+	} else {
+		res, err = env.bisect()
+	}
 	if env.flaky {
 		env.logf("reproducer is flaky (%.2f repro chance estimate)", env.reproChance)
 	}
@@ -208,6 +253,18 @@ func runImpl(cfg *Config, repo vcs.Repo, inst instance.Env) (*Result, error) {
 		env.logf("error: %v", err)
 		return nil, err
 	}
+	if cfg.ConfigBisect {
+		env.logf("the crash is triggered by: %v", strings.Join(res.ConfigOptions, " "))
+		return res, nil
+	}
+	if cfg.FixSearch != nil {
+		if len(res.Commits) == 0 {
+			env.logf("no fixing commit found")
+		} else {
+			env.logf("fixing commit: %v %v", res.Commits[0].Hash, res.Commits[0].Title)
+		}
+		return res, nil
+	}
 	if len(res.Commits) == 0 {
 		if cfg.Fix {
 			env.logf("crash still not fixed or there were kernel test errors")
@@ -249,36 +306,11 @@ func (env *env) bisect() (*Result, error) {
 	}
 
 	cfg := env.cfg
-	if err := build.Clean(cfg.Manager.TargetOS, cfg.Manager.TargetVMArch,
-		cfg.Manager.Type, cfg.Manager.KernelSrc); err != nil {
-		return nil, fmt.Errorf("kernel clean failed: %w", err)
-	}
-	env.logf("building syzkaller on %v", cfg.Syzkaller.Commit)
-	if _, err := env.inst.BuildSyzkaller(cfg.Syzkaller.Repo, cfg.Syzkaller.Commit); err != nil {
-		return nil, err
-	}
-
-	cfg.Kernel.Commit, err = env.identifyRewrittenCommit()
-	if err != nil {
-		return nil, err
-	}
-	com, err := env.repo.SwitchCommit(cfg.Kernel.Commit)
+	testRes, err := env.testOriginal()
 	if err != nil {
 		return nil, err
 	}
 
-	env.logf("ensuring issue is reproducible on original commit %v\n", cfg.Kernel.Commit)
-	env.commit = com
-	env.kernelConfig = cfg.Kernel.Config
-	testRes, err := env.test()
-	if err != nil {
-		return nil, err
-	} else if testRes.verdict != vcs.BisectBad {
-		return nil, fmt.Errorf("the crash wasn't reproduced on the original commit")
-	}
-	env.reportTypes = testRes.types
-	env.reproChance = testRes.badRatio
-
 	testRes1, err := env.minimizeConfig()
 	if err != nil {
 		return nil, fmt.Errorf("config minimization failed: %w", err)
@@ -331,6 +363,106 @@ func (env *env) bisect() (*Result, error) {
 			env.logf("failed to detect noop change: %v", err)
 		}
 		res.NoopChange = noopChange
+		if cfg.VerifyCulprit && !cfg.Fix {
+			res.Verification, err = env.verifyCulprit(com)
+			if err != nil {
+				env.logf("failed to verify the culprit: %v", err)
+			}
+		}
+	}
+	return res, nil
+}
+
+// testOriginal builds syzkaller and checks that the crash is reproduced
+// on the original commit with the original config.
+func (env *env) testOriginal() (*testResult, error) {
+	cfg := env.cfg
+	if err := build.Clean(cfg.Manager.TargetOS, cfg.Manager.TargetVMArch,
+		cfg.Manager.Type, cfg.Manager.KernelSrc); err != nil {
+		return nil, fmt.Errorf("kernel clean failed: %w", err)
+	}
+	env.logf("building syzkaller on %v", cfg.Syzkaller.Commit)
+	if _, err := env.inst.BuildSyzkaller(cfg.Syzkaller.Repo, cfg.Syzkaller.Commit); err != nil {
+		return nil, err
+	}
+
+	var err error
+	cfg.Kernel.Commit, err = env.identifyRewrittenCommit()
+	if err != nil {
+		return nil, err
+	}
+	com, err := env.repo.SwitchCommit(cfg.Kernel.Commit)
+	if err != nil {
+		return nil, err
+	}
+
+	env.logf("ensuring issue is reproducible on original commit %v\n", cfg.Kernel.Commit)
+	env.commit = com
+	env.kernelConfig = cfg.Kernel.Config
+	testRes, err := env.test()
+	if err != nil {
+		return nil, err
+	} else if testRes.verdict != vcs.BisectBad {
+		return nil, fmt.Errorf("the crash wasn't reproduced on the original commit")
+	}
+	env.reportTypes = testRes.types
+	env.reproChance = testRes.badRatio
+	return testRes, nil
+}
+
+// bisectConfig finds the options that need to be enabled in the baseline config to trigger the crash.
+func (env *env) bisectConfig() (*Result, error) {
+	cfg := env.cfg
+	configBisecter, ok := env.repo.(vcs.ConfigBisecter)
+	if !ok {
+		return nil, fmt.Errorf("config bisection is not implemented for %v", cfg.Manager.TargetOS)
+	}
+	if len(cfg.Kernel.BaselineConfig) == 0 {
+		return nil, fmt.Errorf("config bisection requires a baseline config")
+	}
+	testRes, err := env.testOriginal()
+	if err != nil {
+		return nil, err
+	}
+	env.logf("ensuring issue is not reproducible with the baseline config")
+	env.kernelConfig = cfg.Kernel.BaselineConfig
+	baselineRes, err := env.test()
+	if err != nil {
+		return nil, err
+	}
+	switch baselineRes.verdict {
+	case vcs.BisectBad:
+		return nil, fmt.Errorf("the crash is reproduced with the baseline config")
+	case vcs.BisectSkip:
+		return nil, fmt.Errorf("failed to test the baseline config: %v", baselineRes.rep.Title)
+	}
+	testResults := map[hash.Sig]*testResult{
+		hash.Hash(cfg.Kernel.Config): testRes,
+	}
+	pred := func(test []byte) (vcs.BisectResult, error) {
+		env.kernelConfig = test
+		testRes, err := env.test()
+		if err != nil {
+			return 0, err
+		}
+		if testRes.verdict == vcs.BisectBad {
+			testResults[hash.Hash(test)] = testRes
+		}
+		return testRes.verdict, nil
+	}
+	config, options, err := configBisecter.BisectConfig(cfg.Manager.SysTarget, cfg.Kernel.Config,
+		cfg.Kernel.BaselineConfig, cfg.Trace, pred)
+	if err != nil {
+		return nil, err
+	}
+	res := &Result{
+		Commit:        env.commit,
+		Config:        config,
+		ConfigOptions: options,
+		Report:        testRes.rep,
+	}
+	if testRes := testResults[hash.Hash(config)]; testRes != nil {
+		res.Report = testRes.rep
 	}
 	return res, nil
 }
@@ -456,6 +588,44 @@ func (env *env) detectNoopChange(com *vcs.Commit) (bool, error) {
 	return testRes.kernelSign == parentRes.kernelSign, nil
 }
 
+// verifyCulprit reverts the cause commit on top of the original crashing commit
+// and checks whether the crash is still reproduced.
+func (env *env) verifyCulprit(com *vcs.Commit) (CulpritVerification, error) {
+	reverter, ok := env.repo.(vcs.Reverter)
+	if !ok || len(com.Parents) != 1 {
+		return CulpritNotVerified, nil
+	}
+	if _, err := env.repo.SwitchCommit(env.cfg.Kernel.Commit); err != nil {
+		return CulpritNotVerified, err
+	}
+	partial, err := reverter.Revert(com.Hash)
+	if err != nil {
+		return CulpritNotVerified, err
+	}
+	env.uncommitted = true
+	defer func() { env.uncommitted = false }()
+	if partial {
+		env.logf("%v does not revert cleanly, testing a partial revert", com.Hash)
+	} else {
+		env.logf("testing %v with %v reverted", env.cfg.Kernel.Commit, com.Hash)
+	}
+	testRes, err := env.test()
+	if err != nil {
+		return CulpritNotVerified, err
+	}
+	switch {
+	case testRes.verdict == vcs.BisectGood:
+		env.logf("the culprit is confirmed: the crash is not reproduced with the revert")
+		return CulpritConfirmed, nil
+	case testRes.verdict == vcs.BisectBad && !partial:
+		// For a partial revert the crash may be caused by the part that we could not revert.
+		env.logf("the culprit is not confirmed: the crash is still reproduced with the revert")
+		return CulpritUnconfirmed, nil
+	}
+	env.logf("unable to verify the culprit")
+	return CulpritNotVerified, nil
+}
+
 func (env *env) commitRange() (*vcs.Commit, *vcs.Commit, []*testResult, *Result, error) {
 	rangeFunc := env.commitRangeForCause
 	if env.cfg.Fix {
@@ -627,6 +797,8 @@ func (env *env) build() (*vcs.Commit, string, error) {
 		SysctlFile:   kern.Sysctl,
 		KernelConfig: bisectEnv.KernelConfig,
 		BuildCPUs:    env.cfg.BuildCPUs,
+		Cache:        env.cfg.BuildCache,
+		KernelCommit: env.cacheCommitID(current),
 	})
 	if imageDetails.CompilerID != "" {
 		env.logf("compiler: %v", imageDetails.CompilerID)
@@ -638,6 +810,19 @@ func (env *env) build() (*vcs.Commit, string, error) {
 	return current, imageDetails.Signature, err
 }
 
+// cacheCommitID identifies the kernel sources for the build cache.
+func (env *env) cacheCommitID(current *vcs.Commit) string {
+	if env.uncommitted {
+		return ""
+	}
+	// The backports are cherry-picked on top of the commit, so they are part of the sources.
+	id := current.Hash
+	for _, backport := range env.cfg.Kernel.Backports {
+		id += "+" + backport.FixHash
+	}
+	return id
+}
+
 // Note: When this function returns an error, the bisection it was called from is aborted.
 // Hence recoverable errors must be handled and the callers must treat testResult with care.
 // e.g. testResult.verdict will be vcs.BisectSkip for a broken build, but err will be nil.
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/google/syzkaller/pkg/build"
@@ -101,9 +102,16 @@ func (env *testEnv) Test(numVMs int, reproSyz, reproOpts, reproC []byte) ([]inst
 		}
 		introduced = commit != nil
 	}
+	if r, ok := env.r.(*revertTestRepo); ok && r.reverted && !env.test.revertKeepsCrash {
+		introduced = false
+	}
 
-	if (env.config == "baseline-repro" || env.config == "new-minimized-config" || env.config == "original config") &&
-		introduced && !fixed {
+	crashes := env.config == "baseline-repro" || env.config == "new-minimized-config" ||
+		env.config == "original config"
+	if env.test.needConfig != "" {
+		crashes = strings.Contains(env.config, env.test.needConfig)
+	}
+	if crashes && introduced && !fixed {
 		if env.test.flaky {
 			crashed := max(2, numVMs/6)
 			ret = crashErrors(crashed, numVMs-crashed, "crash occurs", env.test.reportType)
@@ -140,6 +148,24 @@ func (env *testEnv) headCommit() int {
 	return int(commit)
 }
 
+// revertTestRepo emulates reverting of commits, as commits in the test repo are empty.
+type revertTestRepo struct {
+	vcs.Repo
+	vcs.Bisecter
+	vcs.ConfigMinimizer
+	reverted bool
+}
+
+func (r *revertTestRepo) Revert(commit string) (bool, error) {
+	r.reverted = true
+	return false, nil
+}
+
+func (r *revertTestRepo) SwitchCommit(commit string) (*vcs.Commit, error) {
+	r.reverted = false
+	return r.Repo.SwitchCommit(commit)
+}
+
 func createTestRepo(t *testing.T) string {
 	baseDir := t.TempDir()
 	repo := vcs.CreateTestRepo(t, baseDir, "")
@@ -184,6 +210,18 @@ func createTestRepo(t *testing.T) string {
 	return baseDir
 }
 
+// fixSearchTestRepo emulates function changes, as commits in the test repo are empty.
+type fixSearchTestRepo struct {
+	vcs.Repo
+	vcs.Bisecter
+	vcs.ConfigMinimizer
+	changes []*vcs.FunctionChange
+}
+
+func (r *fixSearchTestRepo) FunctionChanges(base, head, file, function string) ([]*vcs.FunctionChange, error) {
+	return r.changes, nil
+}
+
 func testBisection(t *testing.T, baseDir string, test BisectionTest) {
 	r, err := vcs.NewRepo(targets.TestOS, targets.TestArch64, baseDir, vcs.OptPrecious)
 	if err != nil {
@@ -202,6 +240,10 @@ func testBisection(t *testing.T, baseDir string, test BisectionTest) {
 		t.Fatalf("start commit %v is not found", test.startCommit)
 	}
 	r.SwitchCommit("master")
+	kernelConfig := "original config"
+	if test.kernelConfig != "" {
+		kernelConfig = test.kernelConfig
+	}
 	cfg := &Config{
 		Fix:   test.fix,
 		Trace: &debugtracer.TestTracer{T: t},
@@ -218,10 +260,42 @@ func testBisection(t *testing.T, baseDir string, test BisectionTest) {
 			Branch:         "master",
 			Commit:         sc.Hash,
 			CommitTitle:    sc.Title,
-			Config:         []byte("original config"),
+			Config:         []byte(kernelConfig),
 			BaselineConfig: []byte(test.baselineConfig),
 		},
-		CrossTree: test.crossTree,
+		CrossTree:     test.crossTree,
+		VerifyCulprit: test.verifyCulprit,
+		ConfigBisect:  test.configBisect,
+	}
+	if test.verifyCulprit {
+		r = &revertTestRepo{
+			Repo:            r,
+			Bisecter:        r.(vcs.Bisecter),
+			ConfigMinimizer: r.(vcs.ConfigMinimizer),
+		}
+	}
+	if test.fixCandidates != nil {
+		cfg.FixSearch = &FixSearch{
+			File:   "file",
+			Frames: []string{"func"},
+			Title:  "crash occurs",
+		}
+		searchRepo := &fixSearchTestRepo{
+			Repo:            r,
+			Bisecter:        r.(vcs.Bisecter),
+			ConfigMinimizer: r.(vcs.ConfigMinimizer),
+		}
+		for _, title := range test.fixCandidates {
+			com, err := r.GetCommitByTitle(title)
+			if err != nil || com == nil {
+				t.Fatalf("fix candidate %v is not found: %v", title, err)
+			}
+			searchRepo.changes = append(searchRepo.changes, &vcs.FunctionChange{
+				Commit:  com,
+				Message: title,
+			})
+		}
+		r = searchRepo
 	}
 	inst := &testEnv{
 		t:    t,
@@ -287,6 +361,10 @@ func checkBisectionResult(t *testing.T, test BisectionTest, res *Result) {
 		t.Fatalf("expected resulting config: %q got %q",
 			test.resultingConfig, res.Config)
 	}
+	if res.Verification != test.verification {
+		t.Fatalf("got culprit verification: %v, want: %v", res.Verification, test.verification)
+	}
+	assert.Equal(t, test.configOptions, res.ConfigOptions)
 }
 
 type BisectionTest struct {
@@ -329,6 +407,17 @@ type BisectionTest struct {
 	resultingConfig string
 	crossTree       bool
 	noFakeHashTest  bool
+	verifyCulprit   bool
+	// The crash is still reproduced when the culprit is reverted.
+	revertKeepsCrash bool
+	verification     CulpritVerification
+	// Config bisection: the kernel crashes only if its config contains needConfig.
+	configBisect  bool
+	kernelConfig  string
+	needConfig    string
+	configOptions []string
+	// Fix search: titles of the commits that modify the guilty function.
+	fixCandidates []string
 
 	extraTest func(t *testing.T, res *Result)
 }
@@ -345,6 +434,64 @@ var bisectionTests = []BisectionTest{
 			assert.Greater(t, res.Confidence, 0.99)
 		},
 	},
+	// Tests that config bisection finds the option that enables the bug.
+	{
+		name:            "config-bisect",
+		startCommit:     905,
+		configBisect:    true,
+		kernelConfig:    "CONFIG_A=y\nCONFIG_B=y\nCONFIG_C=y\nCONFIG_D=y",
+		baselineConfig:  "CONFIG_A=y",
+		needConfig:      "CONFIG_C=y",
+		expectRep:       true,
+		oldestLatest:    905,
+		resultingConfig: "CONFIG_A=y\nCONFIG_C=y\n",
+		configOptions:   []string{"CONFIG_C=y"},
+	},
+	{
+		name:           "config-bisect-baseline-repro",
+		startCommit:    905,
+		configBisect:   true,
+		kernelConfig:   "CONFIG_A=y\nCONFIG_B=y",
+		baselineConfig: "CONFIG_A=y",
+		needConfig:     "CONFIG_A=y",
+		expectErr:      true,
+	},
+	// Tests that the fix is found among the candidates that modify the guilty function.
+	{
+		name:          "fix-search",
+		fix:           true,
+		startCommit:   400,
+		commitLen:     1,
+		fixCommit:     "500",
+		fixCandidates: []string{"403", "500", "501"},
+	},
+	{
+		name:          "fix-search-not-found",
+		fix:           true,
+		startCommit:   400,
+		fixCommit:     "500",
+		fixCandidates: []string{"403", "601"},
+	},
+	// Tests that the culprit is verified by reverting it.
+	{
+		name:          "cause-verify-confirmed",
+		startCommit:   905,
+		commitLen:     1,
+		expectRep:     true,
+		introduced:    "602",
+		verifyCulprit: true,
+		verification:  CulpritConfirmed,
+	},
+	{
+		name:             "cause-verify-unconfirmed",
+		startCommit:      905,
+		commitLen:        1,
+		expectRep:        true,
+		introduced:       "602",
+		verifyCulprit:    true,
+		revertKeepsCrash: true,
+		verification:     CulpritUnconfirmed,
+	},
 	{
 		name:        "cause-finds-cause-flaky",
 		startCommit: 905,
@@ -1021,3 +1168,29 @@ func TestPickReleaseTags(t *testing.T) {
 		})
 	}
 }
+
+func TestRankFixCandidates(t *testing.T) {
+	change := func(title, msg, patch string) *vcs.FunctionChange {
+		return &vcs.FunctionChange{
+			Commit:  &vcs.Commit{Title: title},
+			Message: msg,
+			Patch:   patch,
+		}
+	}
+	changes := []*vcs.FunctionChange{
+		change("cleanup", "foo: cleanup", "-\told_name();\n+\tnew_name();\n"),
+		change("caller", "foo: simplify", "+\tbar(x);\n"),
+		change("fix", "foo: fix use-after-free in foo_release()\n\nReported-by: syzbot", ""),
+		change("refcount", "foo: take a reference", ""),
+	}
+	search := &FixSearch{
+		File:   "foo.c",
+		Frames: []string{"foo_release", "bar"},
+		Title:  "KASAN: slab-use-after-free Read in foo_release",
+	}
+	var titles []string
+	for _, change := range rankFixCandidates(changes, search) {
+		titles = append(titles, change.Commit.Title)
+	}
+	assert.Equal(t, []string{"fix", "caller", "cleanup", "refcount"}, titles)
+}
@@ -0,0 +1,173 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package bisect
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/vcs"
+)
+
+// FixSearch describes the crash for the fixing commit search (see Config.FixSearch).
+type FixSearch struct {
+	// File is the guilty file of the crash.
+	File string
+	// Frames are the top stack frames of the crash, the first one is the guilty function.
+	Frames []string
+	// Title is the crash title.
+	Title string
+}
+
+// maxFixCandidates is the number of the best ranked candidates that are tested.
+const maxFixCandidates = 5
+
+// searchFix looks for the fixing commit among the commits that modify the guilty function
+// between Kernel.Commit and HEAD. The candidates are ranked by similarity to the crash,
+// then the reproducer is run on the parent of each candidate and on the candidate itself.
+func (env *env) searchFix() (*Result, error) {
+	cfg := env.cfg
+	searcher, ok := env.repo.(vcs.ChangeSearcher)
+	if !ok {
+		return nil, fmt.Errorf("fix search is not implemented for %v", cfg.Manager.TargetOS)
+	}
+	search := cfg.FixSearch
+	if search.File == "" || len(search.Frames) == 0 {
+		return nil, fmt.Errorf("fix search requires the guilty file and function")
+	}
+	if _, err := env.testOriginal(); err != nil {
+		return nil, err
+	}
+	changes, err := searcher.FunctionChanges(cfg.Kernel.Commit, env.head.Hash, search.File, search.Frames[0])
+	if err != nil {
+		return nil, err
+	}
+	env.logf("found %v commits that modify %v in %v", len(changes), search.Frames[0], search.File)
+	res := &Result{
+		Config:     env.kernelConfig,
+		Confidence: env.confidence,
+	}
+	for i, change := range rankFixCandidates(changes, search) {
+		if i == maxFixCandidates {
+			break
+		}
+		com := change.Commit
+		if len(com.Parents) != 1 {
+			continue
+		}
+		env.logf("testing fix candidate %v %v", com.Hash, com.Title)
+		parentRes, err := env.testCommit(com.Parents[0])
+		if err != nil {
+			return nil, err
+		}
+		if parentRes.verdict != vcs.BisectBad {
+			env.logf("the crash is not reproduced on the parent commit")
+			continue
+		}
+		testRes, err := env.testCommit(com.Hash)
+		if err != nil {
+			return nil, err
+		}
+		if testRes.verdict == vcs.BisectGood {
+			res.Commits = []*vcs.Commit{com}
+			return res, nil
+		}
+	}
+	return res, nil
+}
+
+func (env *env) testCommit(hash string) (*testResult, error) {
+	com, err := env.repo.SwitchCommit(hash)
+	if err != nil {
+		return nil, err
+	}
+	env.commit = com
+	return env.test()
+}
+
+// rankFixCandidates orders the commits by similarity to the crash:
+// mentions of the stack frames and of the crash type keywords in the commit.
+// Among equally ranked commits the older ones go first.
+func rankFixCandidates(changes []*vcs.FunctionChange, search *FixSearch) []*vcs.FunctionChange {
+	keywords := crashKeywords(search.Title)
+	score := make(map[*vcs.FunctionChange]int)
+	for _, change := range changes {
+		msg := strings.ToLower(change.Message)
+		patchIdents, msgIdents := identifiers(change.Patch), identifiers(change.Message)
+		for i, frame := range search.Frames {
+			if i != 0 && patchIdents[frame] {
+				score[change] += 2
+			}
+			if msgIdents[frame] {
+				score[change] += 3
+			}
+		}
+		for _, keyword := range keywords {
+			if strings.Contains(msg, keyword) {
+				score[change] += 2
+			}
+		}
+		if strings.Contains(msg, "syzbot") || strings.Contains(msg, "syzkaller") {
+			score[change]++
+		}
+	}
+	ret := append([]*vcs.FunctionChange{}, changes...)
+	sort.SliceStable(ret, func(i, j int) bool {
+		if score[ret[i]] != score[ret[j]] {
+			return score[ret[i]] > score[ret[j]]
+		}
+		return ret[i].Commit.Date.Before(ret[j].Commit.Date)
+	})
+	return ret
+}
+
+var identRe = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+
+// identifiers returns the set of C identifiers mentioned in the text.
+func identifiers(text string) map[string]bool {
+	ret := make(map[string]bool)
+	for _, ident := range identRe.FindAllString(text, -1) {
+		ret[ident] = true
+	}
+	return ret
+}
+
+var crashTypeKeywords = []struct {
+	title    string
+	keywords []string
+}{
+	{"use-after-free", []string{"use-after-free", "use after free", "uaf", "refcount", "free"}},
+	{"double-free", []string{"double-free", "double free", "free"}},
+	{"out-of-bounds", []string{"out-of-bounds", "out of bounds", "overflow", "bounds", "length", "size"}},
+	{"null-ptr-deref", []string{"null-ptr-deref", "null pointer", "null", "check"}},
+	{"null pointer", []string{"null-ptr-deref", "null pointer", "null", "check"}},
+	{"uninit", []string{"uninit", "initialize", "initialise"}},
+	{"memory leak", []string{"memory leak", "leak", "free"}},
+	{"deadlock", []string{"deadlock", "lock"}},
+	{"lock", []string{"lock"}},
+	{"data-race", []string{"data-race", "data race", "race", "read_once", "write_once"}},
+	{"hung task", []string{"hang", "hung", "stuck"}},
+	{"stall", []string{"stall", "loop", "resched"}},
+	{"divide error", []string{"divide", "division", "zero"}},
+}
+
+// crashKeywords returns lower-case keywords that a fix for the crash is likely to mention.
+func crashKeywords(title string) []string {
+	title = strings.ToLower(title)
+	var ret []string
+	for _, typ := range crashTypeKeywords {
+		if !strings.Contains(title, typ.title) {
+			continue
+		}
+		for _, keyword := range typ.keywords {
+			if !slices.Contains(ret, keyword) {
+				ret = append(ret, keyword)
+			}
+		}
+	}
+	return ret
+}
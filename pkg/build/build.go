@@ -40,6 +40,12 @@ type Params struct {
 	Tracer       debugtracer.DebugTracer
 	BuildCPUs    int // If 0, all CPUs will be used.
 	Build        json.RawMessage
+	// Cache, if set, is used to build kernels incrementally and to reuse already built images.
+	Cache *Cache
+	// KernelCommit identifies the kernel sources in KernelDir for the image cache.
+	// It must be empty if the sources don't match a commit (e.g. a patch is applied),
+	// then the image is not cached.
+	KernelCommit string
 }
 
 // Information that is returned from the Image function.
@@ -84,6 +90,19 @@ func Image(params Params) (details ImageDetails, err error) {
 	if err = osutil.MkdirAll(filepath.Join(params.OutputDir, "obj")); err != nil {
 		return
 	}
+	var cacheKey string
+	if params.Cache != nil {
+		if cacheKey, err = params.Cache.imageKey(params); err != nil {
+			return
+		}
+	}
+	if cacheKey != "" {
+		var cached bool
+		details, cached, err = params.Cache.loadImage(cacheKey, params.OutputDir)
+		if err != nil || cached {
+			return
+		}
+	}
 	if len(params.Config) != 0 {
 		// Write kernel config early, so that it's captured on build failures.
 		if err = osutil.WriteFile(filepath.Join(params.OutputDir, "kernel.config"), params.Config); err != nil {
@@ -109,6 +128,12 @@ func Image(params Params) (details ImageDetails, err error) {
 			return details, fmt.Errorf("failed to chmod 0600 %v: %w", key, err)
 		}
 	}
+	if cacheKey != "" {
+		// The image is already built, so failure to cache it is not a build failure.
+		if err := params.Cache.storeImage(cacheKey, params.KernelCommit, params.OutputDir, details); err != nil {
+			params.Tracer.Log("%v", err)
+		}
+	}
 	return
 }
 
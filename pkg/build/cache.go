@@ -0,0 +1,241 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package build
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/syzkaller/pkg/osutil"
+)
+
+// Cache keeps build results between Image invocations:
+//   - kernel images (the whole OutputDir) keyed by the hash of the sources, config, compiler
+//     and the other build parameters, so that the same kernel is never built twice;
+//   - build trees (object dirs) keyed by the compiler and the config, so that kernels
+//     for nearby commits are built incrementally.
+//
+// The cache is safe to be used concurrently, but it must not be shared between processes.
+type Cache struct {
+	dir   string
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+const (
+	maxCachedImages = 20
+	maxBuildTrees   = 4
+)
+
+func NewCache(dir string) (*Cache, error) {
+	for _, sub := range []string{"images", "trees"} {
+		if err := osutil.MkdirAll(filepath.Join(dir, sub)); err != nil {
+			return nil, err
+		}
+	}
+	// Remove leftovers of images that were being stored when the process died.
+	tmpDirs, _ := filepath.Glob(filepath.Join(dir, "images", "*.tmp"))
+	for _, tmp := range tmpDirs {
+		os.RemoveAll(tmp)
+	}
+	return &Cache{
+		dir:   dir,
+		locks: make(map[string]*sync.Mutex),
+	}, nil
+}
+
+// lock serializes the use of the build tree or of the cached image with the given name.
+func (cache *Cache) lock(name string) func() {
+	cache.mu.Lock()
+	mu := cache.locks[name]
+	if mu == nil {
+		mu = new(sync.Mutex)
+		cache.locks[name] = mu
+	}
+	cache.mu.Unlock()
+	mu.Lock()
+	return mu.Unlock
+}
+
+// imageKey returns the cache key of the image, or "" if the image must not be cached.
+func (cache *Cache) imageKey(params Params) (string, error) {
+	if params.KernelCommit == "" {
+		return "", nil
+	}
+	compilerID, err := compilerIdentity(params.Compiler)
+	if err != nil {
+		return "", err
+	}
+	var extra [][]byte
+	for _, file := range []string{params.CmdlineFile, params.SysctlFile} {
+		var data []byte
+		if file != "" {
+			if data, err = os.ReadFile(file); err != nil {
+				return "", err
+			}
+		}
+		extra = append(extra, data)
+	}
+	return cacheHash(
+		[]byte(params.KernelCommit),
+		[]byte(params.TargetOS),
+		[]byte(params.TargetArch),
+		[]byte(params.VMType),
+		[]byte(params.Compiler),
+		[]byte(compilerID),
+		[]byte(params.Linker),
+		[]byte(params.UserspaceDir),
+		params.Config,
+		params.Build,
+		extra[0],
+		extra[1],
+	), nil
+}
+
+// buildTree returns the object dir for the build with the given parameters.
+// The returned function must be called once the tree is no longer used.
+func (cache *Cache) buildTree(params Params) (string, func(), error) {
+	// Kbuild records absolute source paths, so the trees are not shared between source dirs.
+	name := cacheHash(
+		[]byte(params.KernelDir),
+		[]byte(params.TargetOS),
+		[]byte(params.TargetArch),
+		[]byte(params.Compiler),
+		[]byte(params.Linker),
+		params.Config,
+	)
+	unlock := cache.lock("tree-" + name)
+	dir := filepath.Join(cache.dir, "trees", name)
+	if err := osutil.MkdirAll(dir); err != nil {
+		unlock()
+		return "", nil, err
+	}
+	touch(dir)
+	cache.evict(filepath.Join(cache.dir, "trees"), maxBuildTrees, "tree-")
+	return dir, unlock, nil
+}
+
+type cachedImage struct {
+	Commit  string
+	Details ImageDetails
+}
+
+// loadImage copies the cached image into outputDir.
+func (cache *Cache) loadImage(key, outputDir string) (ImageDetails, bool, error) {
+	defer cache.lock("image-" + key)()
+	dir := filepath.Join(cache.dir, "images", key)
+	data, err := os.ReadFile(filepath.Join(dir, "details.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = nil
+		}
+		return ImageDetails{}, false, err
+	}
+	var info cachedImage
+	if err := json.Unmarshal(data, &info); err != nil {
+		return ImageDetails{}, false, fmt.Errorf("failed to parse cached image details: %w", err)
+	}
+	if err := os.RemoveAll(outputDir); err != nil {
+		return ImageDetails{}, false, err
+	}
+	if err := osutil.CopyDirRecursively(filepath.Join(dir, "output"), outputDir); err != nil {
+		return ImageDetails{}, false, err
+	}
+	touch(dir)
+	return info.Details, true, nil
+}
+
+// storeImage saves the contents of outputDir as the image with the given key.
+func (cache *Cache) storeImage(key, commit, outputDir string, details ImageDetails) error {
+	data, err := json.MarshalIndent(cachedImage{Commit: commit, Details: details}, "", "\t")
+	if err != nil {
+		return err
+	}
+	unlock := cache.lock("image-" + key)
+	dir := filepath.Join(cache.dir, "images", key)
+	tmpDir := dir + ".tmp"
+	err = func() error {
+		if err := os.RemoveAll(tmpDir); err != nil {
+			return err
+		}
+		if err := osutil.CopyDirRecursively(outputDir, filepath.Join(tmpDir, "output")); err != nil {
+			return err
+		}
+		if err := osutil.WriteFile(filepath.Join(tmpDir, "details.json"), data); err != nil {
+			return err
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return err
+		}
+		return os.Rename(tmpDir, dir)
+	}()
+	unlock()
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		return fmt.Errorf("failed to cache the image: %w", err)
+	}
+	cache.evict(filepath.Join(cache.dir, "images"), maxCachedImages, "image-")
+	return nil
+}
+
+// evict removes the least recently used entries of the dir, so that at most max are left.
+func (cache *Cache) evict(dir string, max int, lockPrefix string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	type entry struct {
+		name string
+		used time.Time
+	}
+	var all []entry
+	for _, ent := range entries {
+		info, err := ent.Info()
+		if err != nil || !ent.IsDir() || filepath.Ext(ent.Name()) == ".tmp" {
+			continue
+		}
+		all = append(all, entry{ent.Name(), info.ModTime()})
+	}
+	if len(all) <= max {
+		return
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].used.After(all[j].used)
+	})
+	for _, ent := range all[max:] {
+		cache.mu.Lock()
+		mu := cache.locks[lockPrefix+ent.name]
+		cache.mu.Unlock()
+		// Don't wait for the entries that are being used right now.
+		if mu != nil && !mu.TryLock() {
+			continue
+		}
+		os.RemoveAll(filepath.Join(dir, ent.name))
+		if mu != nil {
+			mu.Unlock()
+		}
+	}
+}
+
+func touch(dir string) {
+	now := time.Now()
+	os.Chtimes(dir, now, now)
+}
+
+func cacheHash(parts ...[]byte) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		// Prefix each part with its length, so that the concatenation is unambiguous.
+		fmt.Fprintf(hash, "%d:", len(part))
+		hash.Write(part)
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:32]
+}
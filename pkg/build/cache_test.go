@@ -0,0 +1,90 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package build
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageCache(t *testing.T) {
+	cacheDir := t.TempDir()
+	cache, err := NewCache(cacheDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cachedImages := func() int {
+		entries, err := os.ReadDir(filepath.Join(cacheDir, "images"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(entries)
+	}
+	// Returns the number of images built since the previous call.
+	lastBuilds := testBuilds.Load()
+	builds := func() int {
+		cur := testBuilds.Load()
+		n := int(cur - lastBuilds)
+		lastBuilds = cur
+		return n
+	}
+	build := func(commit, config string) string {
+		outputDir := t.TempDir()
+		_, err := Image(Params{
+			TargetOS:     targets.TestOS,
+			TargetArch:   targets.TestArch64,
+			VMType:       "qemu",
+			OutputDir:    outputDir,
+			Config:       []byte(config),
+			Cache:        cache,
+			KernelCommit: commit,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return outputDir
+	}
+
+	build("commit1", "CONFIG_A=y")
+	assert.Equal(t, 1, builds())
+	assert.Equal(t, 1, cachedImages())
+
+	// The same image is taken from the cache.
+	outputDir := build("commit1", "CONFIG_A=y")
+	assert.Equal(t, 0, builds())
+	assert.Equal(t, 1, cachedImages())
+	config, err := os.ReadFile(filepath.Join(outputDir, "kernel.config"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "CONFIG_A=y", string(config))
+	assert.DirExists(t, filepath.Join(outputDir, "obj"))
+
+	// Different config or different sources.
+	build("commit1", "CONFIG_B=y")
+	build("commit2", "CONFIG_A=y")
+	assert.Equal(t, 2, builds())
+	assert.Equal(t, 3, cachedImages())
+
+	// Images of the sources that don't match a commit are not cached.
+	build("", "CONFIG_A=y")
+	build("", "CONFIG_A=y")
+	assert.Equal(t, 2, builds())
+	assert.Equal(t, 3, cachedImages())
+
+	// Old images are evicted.
+	for i := 0; i < maxCachedImages; i++ {
+		build(fmt.Sprintf("commit%v", i+10), "CONFIG_A=y")
+	}
+	assert.Equal(t, maxCachedImages, cachedImages())
+	assert.Equal(t, maxCachedImages, builds())
+	// The least recently used image is rebuilt.
+	build("commit1", "CONFIG_A=y")
+	assert.Equal(t, 1, builds())
+}
@@ -26,19 +26,33 @@ type linux struct{}
 
 func (linux linux) build(params Params) (ImageDetails, error) {
 	details := ImageDetails{}
-	err := linux.buildKernel(params)
+	// Object files are put into the source dir, unless the cache provides a build tree
+	// that is reused for incremental builds.
+	objDir := params.KernelDir
+	if params.Cache != nil {
+		dir, release, err := params.Cache.buildTree(params)
+		if err != nil {
+			return details, err
+		}
+		defer release()
+		if err := osutil.SandboxChown(dir); err != nil {
+			return details, err
+		}
+		objDir = dir
+	}
+	err := linux.buildKernel(params, objDir)
 	// Even if the build fails, autogenerated files would still be present (unless the build is really broken).
 	if err != nil {
-		details.CompilerID, _ = queryLinuxCompiler(params.KernelDir)
+		details.CompilerID, _ = queryLinuxCompiler(objDir)
 		return details, err
 	}
 
-	details.CompilerID, err = queryLinuxCompiler(params.KernelDir)
+	details.CompilerID, err = queryLinuxCompiler(objDir)
 	if err != nil {
 		return details, err
 	}
 
-	kernelPath := filepath.Join(params.KernelDir, filepath.FromSlash(LinuxKernelImage(params.TargetArch)))
+	kernelPath := filepath.Join(objDir, filepath.FromSlash(LinuxKernelImage(params.TargetArch)))
 
 	// Copy the kernel image to let it be uploaded to the asset storage. If the asset storage is not enabled,
 	// let the file just stay in the output folder -- it is usually very small compared to vmlinux anyway.
@@ -67,14 +81,14 @@ func (linux linux) build(params Params) (ImageDetails, error) {
 	return details, err
 }
 
-func (linux linux) buildKernel(params Params) error {
-	configFile := filepath.Join(params.KernelDir, ".config")
+func (linux linux) buildKernel(params Params, objDir string) error {
+	configFile := filepath.Join(objDir, ".config")
 	if err := linux.writeFile(configFile, params.Config); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 	// One would expect olddefconfig here, but olddefconfig is not present in v3.6 and below.
 	// oldconfig is the same as olddefconfig if stdin is not set.
-	if err := runMake(params, "oldconfig"); err != nil {
+	if err := runMake(params, objDir, "oldconfig"); err != nil {
 		return err
 	}
 	// Write updated kernel config early, so that it's captured on build failures.
@@ -85,26 +99,33 @@ func (linux linux) buildKernel(params Params) error {
 	// Ensure CONFIG_GCC_PLUGIN_RANDSTRUCT doesn't prevent ccache usage.
 	// See /Documentation/kbuild/reproducible-builds.rst.
 	const seed = `const char *randstruct_seed = "e9db0ca5181da2eedb76eba144df7aba4b7f9359040ee58409765f2bdc4cb3b8";`
-	gccPluginsDir := filepath.Join(params.KernelDir, "scripts", "gcc-plugins")
-	if osutil.IsExist(gccPluginsDir) {
-		if err := linux.writeFile(filepath.Join(gccPluginsDir, "randomize_layout_seed.h"), []byte(seed)); err != nil {
+	gccPluginsDir := filepath.Join("scripts", "gcc-plugins")
+	if osutil.IsExist(filepath.Join(params.KernelDir, gccPluginsDir)) {
+		if err := linux.mkdirAll(objDir, gccPluginsDir); err != nil {
+			return err
+		}
+		seedFile := filepath.Join(objDir, gccPluginsDir, "randomize_layout_seed.h")
+		if err := linux.writeFile(seedFile, []byte(seed)); err != nil {
 			return err
 		}
 	}
 
 	// Different key is generated for each build if key is not provided.
 	// see Documentation/reproducible-builds.rst. This is causing problems to our signature calculation.
-	certsDir := filepath.Join(params.KernelDir, "certs")
-	if osutil.IsExist(certsDir) {
-		if err := linux.writeFile(filepath.Join(certsDir, "signing_key.pem"), []byte(moduleSigningKey)); err != nil {
+	if osutil.IsExist(filepath.Join(params.KernelDir, "certs")) {
+		if err := linux.mkdirAll(objDir, "certs"); err != nil {
+			return err
+		}
+		keyFile := filepath.Join(objDir, "certs", "signing_key.pem")
+		if err := linux.writeFile(keyFile, []byte(moduleSigningKey)); err != nil {
 			return err
 		}
 	}
 	target := path.Base(LinuxKernelImage(params.TargetArch))
-	if err := runMake(params, target); err != nil {
+	if err := runMake(params, objDir, target); err != nil {
 		return err
 	}
-	vmlinux := filepath.Join(params.KernelDir, "vmlinux")
+	vmlinux := filepath.Join(objDir, "vmlinux")
 	outputVmlinux := filepath.Join(params.OutputDir, "obj", "vmlinux")
 	if err := osutil.Rename(vmlinux, outputVmlinux); err != nil {
 		return fmt.Errorf("failed to rename vmlinux: %w", err)
@@ -142,7 +163,7 @@ func (linux) createImage(params Params, kernelPath string) error {
 }
 
 func (linux) clean(kernelDir, targetArch string) error {
-	return runMakeImpl(targetArch, "", "", "", kernelDir, runtime.NumCPU(), []string{"distclean"})
+	return runMakeImpl(targetArch, "", "", "", kernelDir, "", runtime.NumCPU(), []string{"distclean"})
 }
 
 func (linux) writeFile(file string, data []byte) error {
@@ -152,9 +173,31 @@ func (linux) writeFile(file string, data []byte) error {
 	return osutil.SandboxChown(file)
 }
 
-func runMakeImpl(arch, compiler, linker, ccache, kernelDir string, jobs int, extraArgs []string) error {
+// mkdirAll creates the dir (relative to root) with all parents, so that the build can write into them.
+func (linux) mkdirAll(root, dir string) error {
+	if osutil.IsExist(filepath.Join(root, dir)) {
+		return nil
+	}
+	var created []string
+	for ; dir != "."; dir = filepath.Dir(dir) {
+		if !osutil.IsExist(filepath.Join(root, dir)) {
+			created = append(created, filepath.Join(root, dir))
+		}
+	}
+	if err := osutil.MkdirAll(created[0]); err != nil {
+		return err
+	}
+	for _, dir := range created {
+		if err := osutil.SandboxChown(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runMakeImpl(arch, compiler, linker, ccache, kernelDir, buildDir string, jobs int, extraArgs []string) error {
 	target := targets.Get(targets.Linux, arch)
-	args := LinuxMakeArgs(target, compiler, linker, ccache, "", jobs)
+	args := LinuxMakeArgs(target, compiler, linker, ccache, buildDir, jobs)
 	args = append(args, extraArgs...)
 	cmd := osutil.Command("make", args...)
 	if err := osutil.Sandbox(cmd, true, true); err != nil {
@@ -178,9 +221,13 @@ func runMakeImpl(arch, compiler, linker, ccache, kernelDir string, jobs int, ext
 	return err
 }
 
-func runMake(params Params, extraArgs ...string) error {
+func runMake(params Params, objDir string, extraArgs ...string) error {
+	buildDir := ""
+	if objDir != params.KernelDir {
+		buildDir = objDir
+	}
 	return runMakeImpl(params.TargetArch, params.Compiler, params.Linker, params.Ccache,
-		params.KernelDir, params.BuildCPUs, extraArgs)
+		params.KernelDir, buildDir, params.BuildCPUs, extraArgs)
 }
 
 func LinuxMakeArgs(target *targets.Target, compiler, linker, ccache, buildDir string, jobs int) []string {
@@ -10,12 +10,14 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"text/template"
 
 	"github.com/google/syzkaller/pkg/debugtracer"
 	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/stretchr/testify/assert"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -41,6 +43,90 @@ func TestQueryLinuxCompiler(t *testing.T) {
 	}
 }
 
+// fakeMake is put into PATH instead of make. It logs the arguments to make.log in the source dir
+// and builds vmlinux in the O= dir, each build appends a line to the objects file there.
+const fakeMake = `#!/bin/sh
+echo "$@" >> make.log
+for arg in "$@"; do
+	case "$arg" in
+	O=*) out="${arg#O=}";;
+	oldconfig) exit 0;;
+	esac
+done
+echo obj >> "$out/objects"
+echo vmlinux > "$out/vmlinux"
+`
+
+func TestLinuxBuildTree(t *testing.T) {
+	t.Setenv("SYZ_DISABLE_SANDBOXING", "yes")
+	binDir := t.TempDir()
+	if err := osutil.WriteExecFile(filepath.Join(binDir, "make"), []byte(fakeMake)); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	kernelDir := t.TempDir()
+	if err := osutil.MkdirAll(filepath.Join(kernelDir, "certs")); err != nil {
+		t.Fatal(err)
+	}
+	cache, err := NewCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	build := func(config string) string {
+		params := Params{
+			TargetArch: "amd64",
+			KernelDir:  kernelDir,
+			OutputDir:  t.TempDir(),
+			Config:     []byte(config),
+			Cache:      cache,
+		}
+		if err := osutil.MkdirAll(filepath.Join(params.OutputDir, "obj")); err != nil {
+			t.Fatal(err)
+		}
+		objDir, release, err := cache.buildTree(params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer release()
+		if err := (linux{}).buildKernel(params, objDir); err != nil {
+			t.Fatal(err)
+		}
+		assert.FileExists(t, filepath.Join(params.OutputDir, "obj", "vmlinux"))
+		assert.FileExists(t, filepath.Join(objDir, "certs", "signing_key.pem"))
+		outConfig, err := os.ReadFile(filepath.Join(params.OutputDir, "kernel.config"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, config, string(outConfig))
+		return objDir
+	}
+	readFile := func(file string) string {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	objDir := build("CONFIG_A=y")
+	assert.NotEqual(t, kernelDir, objDir)
+	makeLog := readFile(filepath.Join(kernelDir, "make.log"))
+	assert.Contains(t, makeLog, "O="+objDir+" oldconfig\n")
+	assert.Contains(t, makeLog, "O="+objDir+" bzImage\n")
+	// The sources are not modified.
+	assert.NoFileExists(t, filepath.Join(kernelDir, ".config"))
+	assert.NoFileExists(t, filepath.Join(kernelDir, "certs", "signing_key.pem"))
+
+	// The same config reuses the tree, so the build is incremental.
+	assert.Equal(t, objDir, build("CONFIG_A=y"))
+	assert.Equal(t, "obj\nobj\n", readFile(filepath.Join(objDir, "objects")))
+
+	// A different config is built in a different tree.
+	otherDir := build("CONFIG_B=y")
+	assert.NotEqual(t, objDir, otherDir)
+	assert.Equal(t, "obj\n", readFile(filepath.Join(otherDir, "objects")))
+}
+
 func enumerateFlags(t *testing.T, flags, allFlags []string) {
 	if len(allFlags) != 0 {
 		enumerateFlags(t, flags, allFlags[1:])
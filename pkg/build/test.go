@@ -3,9 +3,15 @@
 
 package build
 
+import "sync/atomic"
+
 type test struct{}
 
+// testBuilds counts the images built by the test builder, it's used to test the build cache.
+var testBuilds atomic.Int64
+
 func (tb test) build(params Params) (ImageDetails, error) {
+	testBuilds.Add(1)
 	return ImageDetails{}, nil
 }
 
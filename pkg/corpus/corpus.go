@@ -5,6 +5,7 @@ package corpus
 
 import (
 	"context"
+	"sort"
 	"sync"
 
 	"github.com/google/syzkaller/pkg/cover"
@@ -20,8 +21,11 @@ type Corpus struct {
 	ctx     context.Context
 	mu      sync.RWMutex
 	progs   map[string]*Item
-	signal  signal.Signal // total signal of all items
-	cover   cover.Cover   // total coverage of all items
+	signal  signal.Signal       // total signal of all items
+	cover   cover.Cover         // total coverage of all items
+	pcProgs map[uint64][]uint32 // ids of items that cover each PC (at most maxPCProgs)
+	itemIDs map[string]uint32   // compact ids of items for pcProgs
+	idSigs  []string            // sigs of items by their ids
 	updates chan<- NewItemEvent
 	*ProgramsList
 	StatProgs  *stat.Val
@@ -37,6 +41,8 @@ func NewMonitoredCorpus(ctx context.Context, updates chan<- NewItemEvent) *Corpu
 	corpus := &Corpus{
 		ctx:          ctx,
 		progs:        make(map[string]*Item),
+		pcProgs:      make(map[uint64][]uint32),
+		itemIDs:      make(map[string]uint32),
 		updates:      updates,
 		ProgramsList: &ProgramsList{},
 	}
@@ -106,6 +112,13 @@ func (corpus *Corpus) Save(inp NewInput) {
 		newSignal.Merge(inp.Signal)
 		var newCover cover.Cover
 		newCover.Merge(old.Cover)
+		var newPCs []uint64
+		for _, pc := range inp.Cover {
+			if _, ok := newCover[pc]; !ok {
+				newPCs = append(newPCs, pc)
+			}
+		}
+		corpus.indexCover(sig, newPCs)
 		newCover.Merge(inp.Cover)
 		newItem := &Item{
 			Sig:     sig,
@@ -132,6 +145,7 @@ func (corpus *Corpus) Save(inp NewInput) {
 			Updates: []ItemUpdate{update},
 		}
 		corpus.saveProgram(inp.Prog, inp.Signal)
+		corpus.indexCover(sig, inp.Cover)
 	}
 	corpus.signal.Merge(inp.Signal)
 	newCover := corpus.cover.MergeDiff(inp.Cover)
@@ -147,6 +161,26 @@ func (corpus *Corpus) Save(inp NewInput) {
 		}
 	}
 }
+
+// maxPCProgs limits the number of items remembered for each PC.
+// Hot PCs are covered by most of the corpus, and remembering all of them takes lots of memory.
+// Items are indexed in the order of addition, and on minimization shorter programs are added first.
+const maxPCProgs = 100
+
+func (corpus *Corpus) indexCover(sig string, pcs []uint64) {
+	id, ok := corpus.itemIDs[sig]
+	if !ok {
+		id = uint32(len(corpus.idSigs))
+		corpus.itemIDs[sig] = id
+		corpus.idSigs = append(corpus.idSigs, sig)
+	}
+	for _, pc := range pcs {
+		if ids := corpus.pcProgs[pc]; len(ids) < maxPCProgs {
+			corpus.pcProgs[pc] = append(ids, id)
+		}
+	}
+}
+
 func (corpus *Corpus) Signal() signal.Signal {
 	corpus.mu.RLock()
 	defer corpus.mu.RUnlock()
@@ -169,6 +203,43 @@ func (corpus *Corpus) Item(sig string) *Item {
 	return corpus.progs[sig]
 }
 
+// CoveringItem is a corpus item that covers some of the queried PCs.
+type CoveringItem struct {
+	Item *Item
+	PCs  int // number of the queried PCs covered by the item
+}
+
+// CoveringItems returns items that cover any of the pcs.
+// Items that cover more of the pcs go first, among them shorter programs go first.
+// For PCs covered by lots of items, only some of the items are returned (see maxPCProgs).
+func (corpus *Corpus) CoveringItems(pcs []uint64) []CoveringItem {
+	corpus.mu.RLock()
+	counts := make(map[uint32]int)
+	for _, pc := range pcs {
+		for _, id := range corpus.pcProgs[pc] {
+			counts[id]++
+		}
+	}
+	ret := make([]CoveringItem, 0, len(counts))
+	for id, count := range counts {
+		ret = append(ret, CoveringItem{
+			Item: corpus.progs[corpus.idSigs[id]],
+			PCs:  count,
+		})
+	}
+	corpus.mu.RUnlock()
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].PCs != ret[j].PCs {
+			return ret[i].PCs > ret[j].PCs
+		}
+		if len(ret[i].Item.Prog.Calls) != len(ret[j].Item.Prog.Calls) {
+			return len(ret[i].Item.Prog.Calls) < len(ret[j].Item.Prog.Calls)
+		}
+		return ret[i].Item.Sig < ret[j].Item.Sig
+	})
+	return ret
+}
+
 type CallCov struct {
 	Count int
 	Cover cover.Cover
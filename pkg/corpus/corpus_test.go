@@ -5,6 +5,7 @@ package corpus
 
 import (
 	"context"
+	"fmt"
 	"math/rand"
 	"testing"
 
@@ -120,3 +121,55 @@ func getTarget(t *testing.T, os, arch string) *prog.Target {
 	}
 	return target
 }
+
+func TestCorpusCoveringItems(t *testing.T) {
+	target := getTarget(t, targets.TestOS, targets.TestArch64)
+	corpus := NewCorpus(context.Background())
+	rs := rand.NewSource(0)
+
+	short := generateInput(target, rs, 1, 1)
+	short.Cover = []uint64{10, 11}
+	corpus.Save(short)
+	long := generateInput(target, rs, 5, 2)
+	long.Cover = []uint64{11}
+	corpus.Save(long)
+	// New coverage of the same program is indexed as well.
+	long.Cover = []uint64{11, 12}
+	corpus.Save(long)
+
+	items := corpus.CoveringItems([]uint64{11})
+	if assert.Len(t, items, 2) {
+		// Programs that cover the same number of PCs are sorted by size.
+		assert.Equal(t, short.Prog, items[0].Item.Prog)
+	}
+	items = corpus.CoveringItems([]uint64{11, 12})
+	if assert.Len(t, items, 2) {
+		assert.Equal(t, long.Prog, items[0].Item.Prog)
+		assert.Equal(t, 2, items[0].PCs)
+	}
+	items = corpus.CoveringItems([]uint64{10})
+	if assert.Len(t, items, 1) {
+		assert.Equal(t, short.Prog, items[0].Item.Prog)
+	}
+	assert.Empty(t, corpus.CoveringItems([]uint64{13}))
+
+	// Minimization keeps the index consistent with the remaining programs.
+	corpus.Minimize(true)
+	for _, item := range corpus.CoveringItems([]uint64{10, 11, 12}) {
+		assert.Equal(t, item.Item, corpus.Item(item.Item.Sig))
+	}
+}
+
+func TestCorpusCoverIndexLimit(t *testing.T) {
+	corpus := NewCorpus(context.Background())
+	for i := 0; i < maxPCProgs+10; i++ {
+		corpus.indexCover(fmt.Sprint(i), []uint64{1, uint64(i + 2)})
+	}
+	// The same item gets the same id.
+	corpus.indexCover("0", []uint64{0})
+	assert.Len(t, corpus.idSigs, maxPCProgs+10)
+	assert.Equal(t, []uint32{0}, corpus.pcProgs[0])
+	// The hot PC remembers only the first items, other PCs are still indexed.
+	assert.Len(t, corpus.pcProgs[1], maxPCProgs)
+	assert.Equal(t, []uint32{maxPCProgs + 9}, corpus.pcProgs[maxPCProgs+11])
+}
@@ -0,0 +1,233 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package corpus
+
+import (
+	"container/heap"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/google/syzkaller/pkg/signal"
+	"github.com/google/syzkaller/prog"
+)
+
+// DistillObjective is the cost that corpus distillation minimizes while keeping the signal.
+type DistillObjective int
+
+const (
+	DistillPrograms DistillObjective = iota // the number of programs
+	DistillCalls                            // the total number of calls in all programs
+	DistillTime                             // the total execution time of all programs
+)
+
+var distillObjectives = map[string]DistillObjective{
+	"programs": DistillPrograms,
+	"calls":    DistillCalls,
+	"time":     DistillTime,
+}
+
+func ParseDistillObjective(name string) (DistillObjective, error) {
+	objective, ok := distillObjectives[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown distillation objective %q (supported: programs, calls, time)", name)
+	}
+	return objective, nil
+}
+
+func (objective DistillObjective) String() string {
+	for name, val := range distillObjectives {
+		if val == objective {
+			return name
+		}
+	}
+	return fmt.Sprintf("DistillObjective(%d)", int(objective))
+}
+
+type DistillInput struct {
+	Prog    *prog.Prog
+	Signal  signal.Signal
+	Elapsed time.Duration
+	// Subsystems the program belongs to (used for DistillConfig.SubsystemQuota).
+	Subsystems []string
+}
+
+type DistillConfig struct {
+	Objective DistillObjective
+	// If SubsystemQuota is not 0, at most SubsystemQuota programs are selected for each subsystem.
+	// Programs without subsystems are accounted to the "-" subsystem.
+	// With the quota some signal may be lost.
+	SubsystemQuota int
+}
+
+type DistillResult struct {
+	Config DistillConfig
+	// Selected inputs in the order of selection (the most valuable first).
+	Inputs   []*DistillInput
+	Total    DistillStats
+	Selected DistillStats
+	// Number of programs selected per subsystem.
+	Subsystems map[string]int
+}
+
+type DistillStats struct {
+	Programs int
+	Calls    int
+	Elapsed  time.Duration
+	Signal   int
+}
+
+// Distill selects a subset of inputs that has the same total signal, but the smallest cost
+// according to the objective. It uses the greedy set cover algorithm: inputs with the largest
+// amount of new signal per unit of cost are selected first.
+func Distill(inputs []*DistillInput, cfg DistillConfig) *DistillResult {
+	res := &DistillResult{
+		Config:     cfg,
+		Subsystems: make(map[string]int),
+	}
+	var total signal.Signal
+	queue := &distillQueue{}
+	for idx, inp := range inputs {
+		res.Total.add(inp)
+		total.Merge(inp.Signal)
+		if inp.Signal.Empty() {
+			continue
+		}
+		queue.items = append(queue.items, &distillItem{
+			inp:  inp,
+			idx:  idx,
+			cost: distillCost(inp, cfg.Objective),
+			gain: inp.Signal.Len(),
+		})
+	}
+	res.Total.Signal = total.Len()
+	heap.Init(queue)
+	var covered signal.Signal
+	for queue.Len() != 0 {
+		item := heap.Pop(queue).(*distillItem)
+		if gain := covered.Diff(item.inp.Signal).Len(); gain != item.gain {
+			// The gain is stale, re-evaluate the item.
+			item.gain = gain
+			if gain != 0 {
+				heap.Push(queue, item)
+			}
+			continue
+		}
+		subsystems := item.inp.Subsystems
+		if len(subsystems) == 0 {
+			subsystems = []string{"-"}
+		}
+		if cfg.SubsystemQuota != 0 && !res.underQuota(subsystems) {
+			continue
+		}
+		for _, name := range subsystems {
+			res.Subsystems[name]++
+		}
+		covered.Merge(item.inp.Signal)
+		res.Inputs = append(res.Inputs, item.inp)
+		res.Selected.add(item.inp)
+	}
+	res.Selected.Signal = covered.Len()
+	return res
+}
+
+func (res *DistillResult) underQuota(subsystems []string) bool {
+	for _, name := range subsystems {
+		if res.Subsystems[name] < res.Config.SubsystemQuota {
+			return true
+		}
+	}
+	return false
+}
+
+func (stats *DistillStats) add(inp *DistillInput) {
+	stats.Programs++
+	stats.Calls += len(inp.Prog.Calls)
+	stats.Elapsed += inp.Elapsed
+}
+
+func distillCost(inp *DistillInput, objective DistillObjective) float64 {
+	switch objective {
+	case DistillCalls:
+		return float64(max(len(inp.Prog.Calls), 1))
+	case DistillTime:
+		return float64(max(inp.Elapsed, time.Microsecond))
+	default:
+		return 1
+	}
+}
+
+// WriteReport writes a human-readable summary of the distillation.
+func (res *DistillResult) WriteReport(w io.Writer) {
+	percent := func(part, total int) float64 {
+		if total == 0 {
+			return 0
+		}
+		return float64(part) * 100 / float64(total)
+	}
+	fmt.Fprintf(w, "objective: %v\n", res.Config.Objective)
+	if res.Config.SubsystemQuota != 0 {
+		fmt.Fprintf(w, "subsystem quota: %v\n", res.Config.SubsystemQuota)
+	}
+	fmt.Fprintf(w, "programs: %v -> %v (%.1f%%)\n", res.Total.Programs, res.Selected.Programs,
+		percent(res.Selected.Programs, res.Total.Programs))
+	fmt.Fprintf(w, "calls:    %v -> %v (%.1f%%)\n", res.Total.Calls, res.Selected.Calls,
+		percent(res.Selected.Calls, res.Total.Calls))
+	fmt.Fprintf(w, "time:     %v -> %v (%.1f%%)\n", res.Total.Elapsed, res.Selected.Elapsed,
+		percent(int(res.Selected.Elapsed), int(res.Total.Elapsed)))
+	fmt.Fprintf(w, "signal:   %v -> %v (%.1f%%)\n", res.Total.Signal, res.Selected.Signal,
+		percent(res.Selected.Signal, res.Total.Signal))
+	var names []string
+	for name := range res.Subsystems {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if res.Subsystems[names[i]] != res.Subsystems[names[j]] {
+			return res.Subsystems[names[i]] > res.Subsystems[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if len(names) != 0 {
+		fmt.Fprintf(w, "\nprograms per subsystem:\n")
+	}
+	for _, name := range names {
+		fmt.Fprintf(w, "%v: %v\n", name, res.Subsystems[name])
+	}
+}
+
+type distillItem struct {
+	inp  *DistillInput
+	idx  int
+	cost float64
+	gain int // the amount of new signal, may be stale (only decreases over time)
+}
+
+// distillQueue is a max heap of items by gain per unit of cost.
+type distillQueue struct {
+	items []*distillItem
+}
+
+func (q *distillQueue) Len() int { return len(q.items) }
+
+func (q *distillQueue) Less(i, j int) bool {
+	a, b := q.items[i], q.items[j]
+	if scoreA, scoreB := float64(a.gain)/a.cost, float64(b.gain)/b.cost; scoreA != scoreB {
+		return scoreA > scoreB
+	}
+	if a.cost != b.cost {
+		return a.cost < b.cost
+	}
+	return a.idx < b.idx
+}
+
+func (q *distillQueue) Swap(i, j int) { q.items[i], q.items[j] = q.items[j], q.items[i] }
+
+func (q *distillQueue) Push(x any) { q.items = append(q.items, x.(*distillItem)) }
+
+func (q *distillQueue) Pop() any {
+	item := q.items[len(q.items)-1]
+	q.items = q.items[:len(q.items)-1]
+	return item
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package corpus
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/pkg/signal"
+	"github.com/google/syzkaller/prog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistill(t *testing.T) {
+	input := func(calls int, elapsed time.Duration, subsystem string, sig ...uint64) *DistillInput {
+		inp := &DistillInput{
+			Prog:    &prog.Prog{Calls: make([]*prog.Call, calls)},
+			Signal:  signal.FromRaw(sig, 0),
+			Elapsed: elapsed,
+		}
+		if subsystem != "" {
+			inp.Subsystems = []string{subsystem}
+		}
+		return inp
+	}
+	// The big program covers everything, but it's long and slow.
+	big := input(10, 10*time.Second, "net", 1, 2, 3, 4)
+	small1 := input(2, 4*time.Second, "net", 1, 2)
+	small2 := input(2, 4*time.Second, "fs", 3, 4)
+	fast := input(5, time.Second, "fs", 1, 2, 3)
+	dup := input(2, time.Second, "", 1)
+	empty := input(1, time.Second, "")
+	inputs := []*DistillInput{big, small1, small2, fast, dup, empty}
+
+	tests := []struct {
+		cfg  DistillConfig
+		want []*DistillInput
+	}{
+		{
+			cfg:  DistillConfig{Objective: DistillPrograms},
+			want: []*DistillInput{big},
+		},
+		{
+			cfg:  DistillConfig{Objective: DistillCalls},
+			want: []*DistillInput{small1, small2},
+		},
+		{
+			cfg:  DistillConfig{Objective: DistillTime},
+			want: []*DistillInput{fast, small2},
+		},
+		{
+			// fast is selected first, then small2 is dropped b/c "fs" quota is exhausted.
+			cfg:  DistillConfig{Objective: DistillTime, SubsystemQuota: 1},
+			want: []*DistillInput{fast, big},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.cfg.Objective.String(), func(t *testing.T) {
+			res := Distill(inputs, test.cfg)
+			assert.Equal(t, test.want, res.Inputs)
+			assert.Equal(t, 4, res.Total.Signal)
+			assert.Equal(t, 6, res.Total.Programs)
+			assert.Equal(t, len(test.want), res.Selected.Programs)
+		})
+	}
+
+	res := Distill(inputs, DistillConfig{Objective: DistillCalls})
+	assert.Equal(t, DistillStats{Programs: 2, Calls: 4, Elapsed: 8 * time.Second, Signal: 4}, res.Selected)
+	report := new(bytes.Buffer)
+	res.WriteReport(report)
+	assert.Contains(t, report.String(), "programs: 6 -> 2 (33.3%)")
+	assert.Contains(t, report.String(), "signal:   4 -> 4 (100.0%)")
+	assert.Contains(t, report.String(), "fs: 1\nnet: 1\n")
+
+	objective, err := ParseDistillObjective("time")
+	assert.NoError(t, err)
+	assert.Equal(t, DistillTime, objective)
+	_, err = ParseDistillObjective("size")
+	assert.Error(t, err)
+}
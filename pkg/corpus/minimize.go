@@ -38,11 +38,15 @@ func (corpus *Corpus) Minimize(cover bool) {
 	})
 
 	corpus.progs = make(map[string]*Item)
+	corpus.pcProgs = make(map[uint64][]uint32)
+	corpus.itemIDs = make(map[string]uint32)
+	corpus.idSigs = nil
 	programsList := &ProgramsList{}
 	for _, ctx := range signal.Minimize(inputs) {
 		inp := ctx.(*Item)
 		corpus.progs[inp.Sig] = inp
 		programsList.saveProgram(inp.Prog, inp.Signal)
+		corpus.indexCover(inp.Sig, inp.Cover)
 	}
 	corpus.ProgramsList.replace(programsList)
 }
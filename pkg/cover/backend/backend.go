@@ -18,6 +18,12 @@ type Impl struct {
 	Symbolize       func(pcs map[*vminfo.KernelModule][]uint64) ([]Frame, error)
 	CallbackPoints  []uint64
 	PreciseCoverage bool
+	// CallSites returns direct calls in the kernel and module text (nil if not supported for the target).
+	// It's slow and the result is large, so it's done only on demand, the result is cached.
+	CallSites func() ([]CallSite, error)
+	// Branches returns conditional branches in the kernel text (nil if not supported for the target).
+	// The first call is slow, the result is cached.
+	Branches func() ([]Branch, error)
 }
 
 type CompileUnit struct {
@@ -52,6 +58,12 @@ type Frame struct {
 	Range
 }
 
+// CallSite is a direct call instruction at PC that calls Target.
+type CallSite struct {
+	PC     uint64
+	Target uint64
+}
+
 type Range struct {
 	StartLine int
 	StartCol  int
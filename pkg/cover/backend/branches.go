@@ -0,0 +1,346 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package backend
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/vminfo"
+	"github.com/google/syzkaller/sys/targets"
+)
+
+// Branch is a conditional branch instruction at PC.
+// Edges contains coverage edges for the two outcomes of the branch (fall through and jump).
+// An outcome is taken if any of its edges was executed.
+type Branch struct {
+	PC    uint64
+	Edges [2][]Edge
+}
+
+// Edge is a control flow edge between two coverage points that are executed one after another.
+// These are the PC pairs that the executor hashes into the signal with cover_edges.
+type Edge struct {
+	From uint64
+	To   uint64
+}
+
+type insnKind int
+
+const (
+	insnCond insnKind = iota // conditional direct jump
+	insnJump                 // unconditional direct jump
+	insnExit                 // return, indirect jump or trap
+)
+
+// controlInsn is a control transfer instruction.
+type controlInsn struct {
+	pc     uint64
+	next   uint64 // PC of the next instruction (0 if unknown)
+	target uint64 // jump target (0 if unknown)
+	kind   insnKind
+}
+
+// funcStart is used to distinguish tail calls from jumps within a function.
+type funcStart struct {
+	pc   uint64
+	name string
+}
+
+// readBranches finds conditional branches in the text of all modules and the coverage edges they lead to.
+// Instructions are parsed from objdump output, so it's slow and is done only on demand.
+func readBranches(target *targets.Target, modules []*vminfo.KernelModule, coverPoints []uint64,
+	funcs []funcStart) ([]Branch, error) {
+	type result struct {
+		insns []controlInsn
+		err   error
+	}
+	resC := make(chan result, len(modules))
+	for _, module := range modules {
+		go func() {
+			insns, err := readControlInsns(target, module)
+			resC <- result{insns, err}
+		}()
+	}
+	var insns []controlInsn
+	for range modules {
+		res := <-resC
+		if res.err != nil {
+			return nil, res.err
+		}
+		insns = append(insns, res.insns...)
+	}
+	return findBranches(insns, coverPoints, funcs), nil
+}
+
+// findBranches walks from every coverage point to the next coverage points over the control instructions.
+func findBranches(insns []controlInsn, coverPoints []uint64, funcs []funcStart) []Branch {
+	sort.Slice(insns, func(i, j int) bool {
+		return insns[i].pc < insns[j].pc
+	})
+	sort.Slice(funcs, func(i, j int) bool {
+		return funcs[i].pc < funcs[j].pc
+	})
+	w := &branchWalker{
+		insns:       insns,
+		coverPoints: coverPoints,
+		funcs:       funcs,
+		edges:       make(map[uint64]*[2][]Edge),
+	}
+	for _, pc := range coverPoints {
+		w.walk(pc)
+	}
+	var branches []Branch
+	for pc, edges := range w.edges {
+		if len(edges[0]) == 0 || len(edges[1]) == 0 {
+			// One of the outcomes doesn't reach any coverage points (e.g. returns),
+			// we can't tell if it was taken.
+			continue
+		}
+		branches = append(branches, Branch{PC: pc, Edges: *edges})
+	}
+	sort.Slice(branches, func(i, j int) bool {
+		return branches[i].PC < branches[j].PC
+	})
+	return branches
+}
+
+// branchWalker finds coverage points reachable from each coverage point
+// without passing through other coverage points.
+type branchWalker struct {
+	insns       []controlInsn
+	coverPoints []uint64
+	funcs       []funcStart
+	edges       map[uint64]*[2][]Edge
+}
+
+func (w *branchWalker) walk(from uint64) {
+	w.reach(from, from+1, make(map[uint64][]uint64))
+}
+
+// reach returns coverage points reachable from pc and records the edges from the from coverage point
+// for all conditional branches on the way.
+func (w *branchWalker) reach(from, pc uint64, visited map[uint64][]uint64) []uint64 {
+	if pc == 0 || w.funcEntry(pc) {
+		// Unknown target, tail call or fall through to the next function.
+		return nil
+	}
+	funcIdx := sort.Search(len(w.funcs), func(i int) bool { return w.funcs[i].pc > pc })
+	if funcIdx == 0 {
+		return nil
+	}
+	limit := ^uint64(0)
+	if funcIdx < len(w.funcs) {
+		limit = w.funcs[funcIdx].pc
+	}
+	pointIdx := sort.Search(len(w.coverPoints), func(i int) bool { return w.coverPoints[i] >= pc })
+	insnIdx := sort.Search(len(w.insns), func(i int) bool { return w.insns[i].pc >= pc })
+	if pointIdx < len(w.coverPoints) && w.coverPoints[pointIdx] < limit &&
+		(insnIdx == len(w.insns) || w.coverPoints[pointIdx] <= w.insns[insnIdx].pc) {
+		return []uint64{w.coverPoints[pointIdx]}
+	}
+	if insnIdx == len(w.insns) || w.insns[insnIdx].pc >= limit {
+		return nil
+	}
+	insn := w.insns[insnIdx]
+	if res, ok := visited[insn.pc]; ok {
+		return res
+	}
+	// Don't go in circles on loops without coverage points.
+	visited[insn.pc] = nil
+	var res []uint64
+	switch insn.kind {
+	case insnJump:
+		res = w.reach(from, insn.target, visited)
+	case insnCond:
+		outcomes := [2][]uint64{
+			w.reach(from, insn.next, visited),
+			w.reach(from, insn.target, visited),
+		}
+		edges := w.edges[insn.pc]
+		if edges == nil {
+			edges = new([2][]Edge)
+			w.edges[insn.pc] = edges
+		}
+		for i, points := range outcomes {
+			for _, to := range points {
+				edges[i] = append(edges[i], Edge{From: from, To: to})
+			}
+		}
+		res = mergePoints(outcomes[0], outcomes[1])
+	}
+	visited[insn.pc] = res
+	return res
+}
+
+// funcEntry returns true if pc is the start of a function, jumps there are tail calls.
+// Cold parts of functions (foo.cold) have own symbols, but jumps there are local.
+func (w *branchWalker) funcEntry(pc uint64) bool {
+	idx := sort.Search(len(w.funcs), func(i int) bool { return w.funcs[i].pc >= pc })
+	return idx < len(w.funcs) && w.funcs[idx].pc == pc && !strings.Contains(w.funcs[idx].name, ".cold")
+}
+
+func mergePoints(a, b []uint64) []uint64 {
+	res := append([]uint64{}, a...)
+	for _, pc := range b {
+		found := false
+		for _, pc1 := range a {
+			found = found || pc1 == pc
+		}
+		if !found {
+			res = append(res, pc)
+		}
+	}
+	return res
+}
+
+func readControlInsns(target *targets.Target, module *vminfo.KernelModule) ([]controlInsn, error) {
+	cmd := osutil.Command(target.Objdump, "-d", "-j", ".text", "--no-show-raw-insn", module.Path)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	defer stdout.Close()
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+	defer stderr.Close()
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to run objdump on %v: %w", module.Path, err)
+	}
+	defer func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}()
+	var insns []controlInsn
+	offset := uint64(0)
+	if module.Name != "" {
+		offset = module.Addr
+	}
+	s := bufio.NewScanner(stdout)
+	for s.Scan() {
+		pc, insn, ok := parseInsn(target.Arch, s.Bytes())
+		if !ok {
+			continue
+		}
+		pc += offset
+		if len(insns) != 0 && insns[len(insns)-1].next == 0 {
+			insns[len(insns)-1].next = pc
+		}
+		if insn == nil {
+			continue
+		}
+		insn.pc = pc
+		if insn.target != 0 {
+			insn.target += offset
+		}
+		insns = append(insns, *insn)
+	}
+	stderrOut, _ := io.ReadAll(stderr)
+	if err := cmd.Wait(); err != nil {
+		return nil, fmt.Errorf("failed to run objdump on %v: %w\n%s", module.Path, err, stderrOut)
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("failed to run objdump on %v: %w\n%s", module.Path, err, stderrOut)
+	}
+	return insns, nil
+}
+
+// parseInsn parses an instruction line of objdump output, e.g.:
+//
+//	ffffffff81000010:	jne    ffffffff81000020 <foo+0x20>
+//	ffff800008010010:	cbz	x0, ffff800008010020 <foo+0x20>
+//
+// It returns ok=false if the line is not an instruction and insn=nil if it's not a control transfer.
+func parseInsn(arch string, ln []byte) (uint64, *controlInsn, bool) {
+	ln = bytes.TrimLeft(ln, " ")
+	colon := bytes.IndexByte(ln, ':')
+	if colon == -1 || colon+1 == len(ln) || ln[colon+1] != '\t' {
+		return 0, nil, false
+	}
+	pc, err := strconv.ParseUint(string(ln[:colon]), 16, 64)
+	if err != nil {
+		return 0, nil, false
+	}
+	fields := strings.Fields(string(ln[colon+1:]))
+	if len(fields) == 0 {
+		return 0, nil, false
+	}
+	var kind insnKind
+	switch arch {
+	case targets.AMD64:
+		for len(fields) > 1 && x86Prefixes[fields[0]] {
+			fields = fields[1:]
+		}
+		mnemonic := fields[0]
+		switch {
+		case strings.HasPrefix(mnemonic, "jmp"):
+			kind = insnJump
+		case mnemonic[0] == 'j' || strings.HasPrefix(mnemonic, "loop"):
+			kind = insnCond
+		case x86Exits[mnemonic]:
+			kind = insnExit
+		default:
+			return pc, nil, true
+		}
+	case targets.ARM64:
+		mnemonic := fields[0]
+		switch {
+		case mnemonic == "b":
+			kind = insnJump
+		case strings.HasPrefix(mnemonic, "b.") || strings.HasPrefix(mnemonic, "bc.") ||
+			mnemonic == "cbz" || mnemonic == "cbnz" || mnemonic == "tbz" || mnemonic == "tbnz":
+			kind = insnCond
+		case strings.HasPrefix(mnemonic, "br") || strings.HasPrefix(mnemonic, "ret") ||
+			mnemonic == "eret" || mnemonic == "hlt" || mnemonic == "udf":
+			kind = insnExit
+		default:
+			return pc, nil, true
+		}
+	default:
+		return 0, nil, false
+	}
+	insn := &controlInsn{kind: kind}
+	if kind != insnExit {
+		insn.target = parseJumpTarget(strings.Join(fields[1:], " "))
+		if insn.target == 0 && kind == insnJump {
+			// Indirect jump.
+			insn.kind = insnExit
+		}
+	}
+	return pc, insn, true
+}
+
+var x86Prefixes = map[string]bool{
+	"bnd": true, "notrack": true, "cs": true, "ds": true,
+	"rep": true, "repz": true, "repnz": true, "repe": true, "repne": true,
+}
+
+var x86Exits = map[string]bool{
+	"ret": true, "retq": true, "retl": true, "retn": true, "lret": true, "lretq": true,
+	"iret": true, "iretq": true, "sysret": true, "sysretq": true,
+	"ud0": true, "ud1": true, "ud2": true, "int3": true, "hlt": true,
+}
+
+// parseJumpTarget extracts the target from jump operands, e.g. "x0, #3, ffff800008010020 <foo+0x20>".
+func parseJumpTarget(operands string) uint64 {
+	if pos := strings.Index(operands, " <"); pos != -1 {
+		operands = operands[:pos]
+	}
+	if pos := strings.LastIndexAny(operands, ", "); pos != -1 {
+		operands = operands[pos+1:]
+	}
+	target, err := strconv.ParseUint(operands, 16, 64)
+	if err != nil {
+		return 0
+	}
+	return target
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package backend
+
+import (
+	"testing"
+
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseInsn(t *testing.T) {
+	type Test struct {
+		arch string
+		line string
+		pc   uint64
+		insn *controlInsn
+		ok   bool
+	}
+	tests := []Test{
+		{
+			arch: targets.AMD64,
+			line: "ffffffff81000010:\tjne    ffffffff81000020 <foo+0x20>",
+			pc:   0xffffffff81000010,
+			insn: &controlInsn{kind: insnCond, target: 0xffffffff81000020},
+			ok:   true,
+		},
+		{
+			arch: targets.AMD64,
+			line: "  1179:\tnotrack jmp *%rax",
+			pc:   0x1179,
+			insn: &controlInsn{kind: insnExit},
+			ok:   true,
+		},
+		{
+			arch: targets.AMD64,
+			line: "  117b:\tjmp    1195 <foo+0x25>",
+			pc:   0x117b,
+			insn: &controlInsn{kind: insnJump, target: 0x1195},
+			ok:   true,
+		},
+		{
+			arch: targets.AMD64,
+			line: "  117d:\tret",
+			pc:   0x117d,
+			insn: &controlInsn{kind: insnExit},
+			ok:   true,
+		},
+		{
+			arch: targets.AMD64,
+			line: "  1180:\tmov    %rdi,%rax",
+			pc:   0x1180,
+			ok:   true,
+		},
+		{
+			arch: targets.AMD64,
+			line: "0000000000001170 <foo>:",
+		},
+		{
+			arch: targets.ARM64,
+			line: "ffff800008010010:\ttbnz\tw0, #3, ffff800008010020 <foo+0x20>",
+			pc:   0xffff800008010010,
+			insn: &controlInsn{kind: insnCond, target: 0xffff800008010020},
+			ok:   true,
+		},
+		{
+			arch: targets.ARM64,
+			line: "ffff800008010014:\tb.ne\tffff800008010000 <foo>",
+			pc:   0xffff800008010014,
+			insn: &controlInsn{kind: insnCond, target: 0xffff800008010000},
+			ok:   true,
+		},
+		{
+			arch: targets.ARM64,
+			line: "ffff800008010018:\tbl\tffff800008020000 <bar>",
+			pc:   0xffff800008010018,
+			ok:   true,
+		},
+	}
+	for _, test := range tests {
+		pc, insn, ok := parseInsn(test.arch, []byte(test.line))
+		assert.Equal(t, test.ok, ok, test.line)
+		assert.Equal(t, test.pc, pc, test.line)
+		assert.Equal(t, test.insn, insn, test.line)
+	}
+}
+
+func TestFindBranches(t *testing.T) {
+	funcs := []funcStart{{0x100, "foo"}, {0x140, "foo.cold"}, {0x200, "bar"}}
+	insns := []controlInsn{
+		// The loop condition.
+		{pc: 0x105, next: 0x107, target: 0x120, kind: insnCond},
+		// The loop back edge.
+		{pc: 0x115, next: 0x117, target: 0x105, kind: insnJump},
+		{pc: 0x125, next: 0x126, kind: insnExit},
+		// Tail call, one of the outcomes doesn't reach any coverage points.
+		{pc: 0x135, next: 0x137, target: 0x200, kind: insnCond},
+		{pc: 0x137, next: 0x139, target: 0x140, kind: insnJump},
+		// Jumps to the cold part of the function are local.
+		{pc: 0x145, next: 0x147, target: 0x150, kind: insnCond},
+	}
+	coverPoints := []uint64{0x100, 0x110, 0x120, 0x130, 0x147, 0x150}
+	assert.Equal(t, []Branch{
+		{
+			PC: 0x105,
+			Edges: [2][]Edge{
+				{{From: 0x100, To: 0x110}, {From: 0x110, To: 0x110}},
+				{{From: 0x100, To: 0x120}, {From: 0x110, To: 0x120}},
+			},
+		},
+		{
+			PC: 0x145,
+			Edges: [2][]Edge{
+				{{From: 0x130, To: 0x147}},
+				{{From: 0x130, To: 0x150}},
+			},
+		},
+	}, findBranches(insns, coverPoints, funcs))
+}
@@ -17,6 +17,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/google/syzkaller/pkg/log"
 	"github.com/google/syzkaller/pkg/osutil"
@@ -36,6 +37,7 @@ type dwarfParams struct {
 	readSymbols           func(*vminfo.KernelModule, *symbolInfo) ([]*Symbol, error)
 	readTextData          func(*vminfo.KernelModule) ([]byte, error)
 	readModuleCoverPoints func(*targets.Target, *vminfo.KernelModule, *symbolInfo) ([2][]uint64, error)
+	readModuleCallSites   func(*targets.Target, *vminfo.KernelModule, map[string]uint64) ([]CallSite, error)
 	readTextRanges        func(*vminfo.KernelModule) ([]pcRange, []*CompileUnit, error)
 	getCompilerVersion    func(string) string
 }
@@ -45,6 +47,7 @@ type Arch struct {
 	callLen       int
 	relaOffset    uint64
 	callRelocType uint64
+	callDispBase  uint64 // offset from the call instruction to the PC the displacement is relative to
 	isCallInsn    func(arch *Arch, insn []byte) bool
 	callTarget    func(arch *Arch, insn []byte, pc uint64) uint64
 }
@@ -55,6 +58,7 @@ var arches = map[string]Arch{
 		callLen:       5,
 		relaOffset:    1,
 		callRelocType: uint64(elf.R_X86_64_PLT32),
+		callDispBase:  5,
 		isCallInsn: func(arch *Arch, insn []byte) bool {
 			return insn[0] == 0xe8
 		},
@@ -228,6 +232,10 @@ func makeDWARFUnsafe(params *dwarfParams) (*Impl, error) {
 		})
 	}
 
+	funcs := make([]funcStart, 0, len(allSymbols))
+	for _, sym := range allSymbols {
+		funcs = append(funcs, funcStart{pc: sym.Start, name: sym.Name})
+	}
 	allSymbols = buildSymbols(allSymbols, allRanges, allCoverPoints)
 	nunit := 0
 	for _, unit := range allUnits {
@@ -253,6 +261,14 @@ func makeDWARFUnsafe(params *dwarfParams) (*Impl, error) {
 		CallbackPoints:  allCoverPoints[0],
 		PreciseCoverage: preciseCoverage,
 	}
+	if _, ok := arches[target.Arch]; ok {
+		impl.CallSites = sync.OnceValues(func() ([]CallSite, error) {
+			return readCallSites(params)
+		})
+		impl.Branches = sync.OnceValues(func() ([]Branch, error) {
+			return readBranches(target, modules, allCoverPoints[0], funcs)
+		})
+	}
 	return impl, nil
 }
 
@@ -564,6 +580,69 @@ func readCoverPoints(target *targets.Target, info *symbolInfo, data []byte) ([2]
 	return pcs, nil
 }
 
+// readCallSites finds all direct calls in the kernel image and modules except for calls of coverage callbacks.
+// Since the core kernel text is not disassembled, some of the found calls are bogus (byte sequences inside of
+// other instructions), but they are mostly filtered out by the users that expect targets to be
+// function starts. Calls in modules are resolved with relocations if the target supports it.
+func readCallSites(params *dwarfParams) ([]CallSite, error) {
+	var ret []CallSite
+	// Module calls of functions in the core kernel and in other modules are resolved by name.
+	// Core kernel symbols take precedence over module symbols with the same name.
+	funcs := make(map[string]uint64)
+	modules := append([]*vminfo.KernelModule{}, params.hostModules...)
+	sort.SliceStable(modules, func(i, j int) bool {
+		return modules[i].Name == "" && modules[j].Name != ""
+	})
+	for _, module := range modules {
+		info := &symbolInfo{
+			tracePC:     make(map[uint64]bool),
+			traceCmp:    make(map[uint64]bool),
+			tracePCIdx:  make(map[int]bool),
+			traceCmpIdx: make(map[int]bool),
+		}
+		symbols, err := params.readSymbols(module, info)
+		if err != nil {
+			return nil, err
+		}
+		for _, sym := range symbols {
+			if _, ok := funcs[sym.Name]; !ok {
+				funcs[sym.Name] = sym.Start
+			}
+		}
+		if module.Name != "" {
+			continue
+		}
+		data, err := params.readTextData(module)
+		if err != nil {
+			return nil, err
+		}
+		arch := arches[params.target.Arch]
+		for i := 0; ; {
+			callTarget, pc := nextCallTarget(&arch, info.textAddr, data, &i)
+			if callTarget == 0 {
+				break
+			}
+			if !info.tracePC[callTarget] && !info.traceCmp[callTarget] {
+				ret = append(ret, CallSite{PC: pc, Target: callTarget})
+			}
+		}
+	}
+	if params.readModuleCallSites == nil {
+		return ret, nil
+	}
+	for _, module := range modules {
+		if module.Name == "" {
+			continue
+		}
+		calls, err := params.readModuleCallSites(params.target, module, funcs)
+		if err != nil {
+			return nil, fmt.Errorf("module %v: %w", module.Name, err)
+		}
+		ret = append(ret, calls...)
+	}
+	return ret, nil
+}
+
 // Source files for Android may be split between two subdirectories: the common AOSP kernel
 // and the device-specific drivers: https://source.android.com/docs/setup/build/building-pixel-kernels.
 // Android build system references these subdirectories in various ways, which often results in
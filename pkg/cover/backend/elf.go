@@ -30,6 +30,7 @@ func makeELF(target *targets.Target, objDir, srcDir, buildDir string, splitBuild
 		readSymbols:           elfReadSymbols,
 		readTextData:          elfReadTextData,
 		readModuleCoverPoints: elfReadModuleCoverPoints,
+		readModuleCallSites:   elfReadModuleCallSites,
 		readTextRanges:        elfReadTextRanges,
 		getCompilerVersion:    elfGetCompilerVersion,
 	})
@@ -206,6 +207,86 @@ func elfReadModuleCoverPoints(target *targets.Target, module *vminfo.KernelModul
 	return pcs, nil
 }
 
+// elfReadModuleCallSites finds direct calls in the module text except for calls of coverage callbacks.
+// Calls of functions outside of the module text are resolved by name in funcs.
+func elfReadModuleCallSites(target *targets.Target, module *vminfo.KernelModule, funcs map[string]uint64) (
+	[]CallSite, error) {
+	file, err := elf.Open(module.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	textIdx := -1
+	for i, s := range file.Sections {
+		if s.Name == ".text" {
+			textIdx = i
+			break
+		}
+	}
+	if textIdx == -1 {
+		return nil, fmt.Errorf("no .text section in the object file")
+	}
+	data, err := file.Sections[textIdx].Data()
+	if err != nil {
+		return nil, err
+	}
+	allSymbols, err := file.Symbols()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ELF symbols: %w", err)
+	}
+	arch := arches[target.Arch]
+	var ret []CallSite
+	relocated := make(map[uint64]bool)
+	for _, s := range file.Sections {
+		if s.Type != elf.SHT_RELA || int(s.Info) != textIdx { // nolint: misspell
+			continue
+		}
+		rel := new(elf.Rela64)
+		for r := s.Open(); ; {
+			if err := binary.Read(r, binary.LittleEndian, rel); err != nil {
+				if err == io.EOF {
+					break
+				}
+				return nil, err
+			}
+			if (rel.Info & 0xffffffff) != arch.callRelocType {
+				continue
+			}
+			pc := module.Addr + rel.Off - arch.relaOffset
+			relocated[pc] = true
+			index := int(elf.R_SYM64(rel.Info)) - 1
+			if index < 0 || index >= len(allSymbols) {
+				continue
+			}
+			sym := allSymbols[index]
+			var callee uint64
+			switch {
+			case sym.Section == elf.SectionIndex(textIdx):
+				callee = module.Addr + sym.Value
+			case sym.Section == elf.SHN_UNDEF && getTraceCallbackType(sym.Name) == TraceCbNone:
+				callee = funcs[sym.Name]
+			}
+			if callee == 0 {
+				continue
+			}
+			// The relocation sets the displacement to S + A - P, where P is the address of the displacement.
+			callTarget := callee + uint64(rel.Addend) + arch.callDispBase - arch.relaOffset
+			ret = append(ret, CallSite{PC: pc, Target: callTarget})
+		}
+	}
+	// Calls within the module text may be resolved by the assembler without relocations.
+	for i := 0; ; {
+		callTarget, pc := nextCallTarget(&arch, module.Addr, data, &i)
+		if callTarget == 0 {
+			break
+		}
+		if !relocated[pc] {
+			ret = append(ret, CallSite{PC: pc, Target: callTarget})
+		}
+	}
+	return ret, nil
+}
+
 func elfGetCompilerVersion(path string) string {
 	file, err := elf.Open(path)
 	if err != nil {
@@ -248,6 +329,23 @@ func elfReadTextSec(module *vminfo.KernelModule) (*elf.Section, error) {
 	return text, nil
 }
 
+func elfReadBuildID(path string) (string, error) {
+	file, err := elf.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	section := file.Section(".note.gnu.build-id")
+	if section == nil {
+		return "", nil
+	}
+	data, err := section.Data()
+	if err != nil {
+		return "", fmt.Errorf("failed to read .note.gnu.build-id: %w", err)
+	}
+	return vminfo.ParseBuildIDNote(data), nil
+}
+
 func getLinuxPCBase(cfg *mgrconfig.Config) (uint64, error) {
 	bin := filepath.Join(cfg.KernelObj, cfg.SysTarget.KernelObject)
 	file, err := elf.Open(bin)
@@ -64,6 +64,9 @@ func discoverModulesLinux(dirs []string) ([]*vminfo.KernelModule, error) {
 			return nil, err
 		}
 		module.Size = textRange.End - textRange.Start
+		if module.BuildID, err = elfReadBuildID(path); err != nil {
+			log.Logf(1, "failed to read %v build-id: %v", path, err)
+		}
 		modules = append(modules, module)
 	}
 	return modules, nil
@@ -152,30 +155,63 @@ func getKaslrOffset(modules []*vminfo.KernelModule, pcBase uint64) uint64 {
 	return 0
 }
 
-// when CONFIG_RANDOMIZE_BASE=y, pc from kcov already removed kaslr_offset.
-func FixModules(localModules, modules []*vminfo.KernelModule, pcBase uint64) []*vminfo.KernelModule {
+// ModuleMismatch describes a module loaded in the VM that does not match any local module object.
+// Coverage of such modules can't be symbolized and is discarded.
+type ModuleMismatch struct {
+	Name   string
+	Reason string
+	// Module is the module as loaded in the VM (with kaslr offset removed).
+	Module *vminfo.KernelModule
+}
+
+// FixModules matches modules loaded in the VM with local module objects and adjusts their addresses.
+// If build-ids are known for both the VM and the local module, they must match.
+// When CONFIG_RANDOMIZE_BASE=y, pc from kcov already removed kaslr_offset.
+func FixModules(localModules, modules []*vminfo.KernelModule, pcBase uint64) (
+	[]*vminfo.KernelModule, []ModuleMismatch) {
 	kaslrOffset := getKaslrOffset(modules, pcBase)
 	var modules1 []*vminfo.KernelModule
+	var mismatches []ModuleMismatch
 	for _, mod := range modules {
-		size := uint64(0)
-		path := ""
+		var local *vminfo.KernelModule
 		for _, modA := range localModules {
 			if modA.Name == mod.Name {
-				size = modA.Size
-				path = modA.Path
+				local = modA
 				break
 			}
 		}
-		if path == "" {
+		addr := mod.Addr - kaslrOffset
+		mismatch := ""
+		switch {
+		case local == nil || local.Path == "":
+			mismatch = "no module object file"
+		case mod.BuildID != "" && local.BuildID != "" && mod.BuildID != local.BuildID:
+			mismatch = fmt.Sprintf("build-id %v does not match %v of %v", mod.BuildID, local.BuildID, local.Path)
+		}
+		if mismatch != "" {
+			mismatches = append(mismatches, ModuleMismatch{
+				Name:   mod.Name,
+				Reason: mismatch,
+				Module: &vminfo.KernelModule{
+					Name:    mod.Name,
+					Size:    mod.Size,
+					Addr:    addr,
+					BuildID: mod.BuildID,
+				},
+			})
 			continue
 		}
-		addr := mod.Addr - kaslrOffset
+		buildID := mod.BuildID
+		if buildID == "" {
+			buildID = local.BuildID
+		}
 		modules1 = append(modules1, &vminfo.KernelModule{
-			Name: mod.Name,
-			Size: size,
-			Addr: addr,
-			Path: path,
+			Name:    mod.Name,
+			Size:    local.Size,
+			Addr:    addr,
+			Path:    local.Path,
+			BuildID: buildID,
 		})
 	}
-	return modules1
+	return modules1, mismatches
 }
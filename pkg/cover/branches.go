@@ -0,0 +1,223 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package cover
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/google/syzkaller/pkg/cover/backend"
+	"github.com/google/syzkaller/pkg/signal"
+	"github.com/google/syzkaller/pkg/vminfo"
+	"github.com/google/syzkaller/sys/targets"
+)
+
+// branch is a conditional branch with coverage of its two outcomes (fall through and jump).
+// Outcomes are taken if the edge signal contains any of the edges they lead to.
+type branch struct {
+	pc       uint64
+	executed bool // the coverage point before the branch is covered
+	taken    [2]bool
+	targets  [2][]uint64 // coverage points the outcomes lead to
+}
+
+// lineBranch is a branch attributed to a source line.
+type lineBranch struct {
+	*branch
+	lines [2][]int // lines of the targets in the same source file
+}
+
+type branchInfo struct {
+	lines map[string]map[int][]lineBranch // source file -> line -> branches
+	funcs map[*backend.Symbol][]*branch
+}
+
+var branchOutcomes = [2]string{"fall through", "jump"}
+
+// computeBranches computes coverage of conditional branches in the kernel text from the edge signal.
+// Branches are attributed to source lines only in the symbolized functions.
+// It returns nil if there is no edge signal or the backend does not support branches.
+func (rg *ReportGenerator) computeBranches(progs []Prog, sig signal.Signal) (*branchInfo, error) {
+	if sig == nil || rg.Branches == nil {
+		return nil, nil
+	}
+	branches, err := rg.Branches()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find branches: %w", err)
+	}
+	covered := make(map[uint64]bool)
+	for _, prog := range progs {
+		for _, pc := range prog.PCs {
+			covered[pc] = true
+		}
+	}
+	bc := &branchInfo{
+		lines: make(map[string]map[int][]lineBranch),
+		funcs: make(map[*backend.Symbol][]*branch),
+	}
+	symbolized := make(map[uint64]*branch)
+	for i := range branches {
+		sym := rg.findSymbol(branches[i].PC)
+		if sym == nil {
+			continue
+		}
+		b := rg.makeBranch(&branches[i], covered, sig)
+		bc.funcs[sym] = append(bc.funcs[sym], b)
+		if sym.Symbolized {
+			symbolized[b.pc] = b
+		}
+	}
+	frames, err := rg.branchFrames(symbolized)
+	if err != nil {
+		return nil, err
+	}
+	pointFrames := make(map[uint64][]*backend.Frame)
+	for i := range rg.Frames {
+		frame := &rg.Frames[i]
+		pointFrames[frame.PC] = append(pointFrames[frame.PC], frame)
+	}
+	for _, frame := range frames {
+		b := symbolized[frame.PC]
+		if b == nil {
+			continue
+		}
+		lb := lineBranch{branch: b}
+		for i, targets := range b.targets {
+			for _, pc := range targets {
+				for _, target := range pointFrames[pc] {
+					if target.Name == frame.Name {
+						lb.lines[i] = append(lb.lines[i], target.StartLine)
+					}
+				}
+			}
+			lb.lines[i] = uniqueLines(lb.lines[i])
+		}
+		if bc.lines[frame.Name] == nil {
+			bc.lines[frame.Name] = make(map[int][]lineBranch)
+		}
+		bc.lines[frame.Name][frame.StartLine] = append(bc.lines[frame.Name][frame.StartLine], lb)
+	}
+	for _, lines := range bc.lines {
+		for _, branches := range lines {
+			sort.Slice(branches, func(i, j int) bool {
+				return branches[i].pc < branches[j].pc
+			})
+		}
+	}
+	return bc, nil
+}
+
+func (rg *ReportGenerator) makeBranch(br *backend.Branch, covered map[uint64]bool, sig signal.Signal) *branch {
+	b := &branch{pc: br.PC}
+	for i, edges := range br.Edges {
+		seen := make(map[uint64]bool)
+		for _, edge := range edges {
+			b.executed = b.executed || covered[edge.From]
+			b.taken[i] = b.taken[i] || !sig.HasNew([]uint64{rg.edgeSignal(edge)})
+			if !seen[edge.To] {
+				seen[edge.To] = true
+				b.targets[i] = append(b.targets[i], edge.To)
+			}
+		}
+	}
+	return b
+}
+
+// branchFrames symbolizes the branch PCs, the frames are cached.
+func (rg *ReportGenerator) branchFrames(branches map[uint64]*branch) ([]backend.Frame, error) {
+	if rg.branchFrameCache == nil {
+		rg.branchFrameCache = make(map[uint64][]backend.Frame)
+	}
+	pcs := make(map[*vminfo.KernelModule][]uint64)
+	for pc := range branches {
+		if _, ok := rg.branchFrameCache[pc]; ok {
+			continue
+		}
+		sym := rg.findSymbol(pc)
+		pcs[sym.Module] = append(pcs[sym.Module], pc)
+	}
+	if len(pcs) != 0 {
+		frames, err := rg.Symbolize(pcs)
+		if err != nil {
+			return nil, err
+		}
+		for _, pcs1 := range pcs {
+			for _, pc := range pcs1 {
+				rg.branchFrameCache[pc] = nil
+			}
+		}
+		for _, frame := range frames {
+			rg.branchFrameCache[frame.PC] = append(rg.branchFrameCache[frame.PC], frame)
+		}
+	}
+	var ret []backend.Frame
+	for pc := range branches {
+		ret = append(ret, rg.branchFrameCache[pc]...)
+	}
+	return ret, nil
+}
+
+// edgeSignal returns the signal the executor produces for the edge with cover_edges
+// (see write_signal in executor/executor.cc).
+func (rg *ReportGenerator) edgeSignal(edge backend.Edge) uint64 {
+	const mask = 1<<12 - 1
+	prev := backend.NextInstructionPC(rg.target, rg.vm, edge.From)
+	pc := backend.NextInstructionPC(rg.target, rg.vm, edge.To)
+	return pc ^ uint64(edgeHash(rg.target, uint32(prev&mask))&mask)
+}
+
+// edgeHash is the hash function of the executor.
+func edgeHash(target *targets.Target, a uint32) uint32 {
+	if target.OS == targets.TestOS {
+		return a
+	}
+	a = (a ^ 61) ^ (a >> 16)
+	a = a + (a << 3)
+	a = a ^ (a >> 4)
+	a = a * 0x27d4eb2d
+	a = a ^ (a >> 15)
+	return a
+}
+
+// addBranches adds branch coverage to the files and functions, it returns false if there is no branch coverage.
+func (rg *ReportGenerator) addBranches(files fileMap, progs []Prog, sig signal.Signal) (bool, error) {
+	bc, err := rg.computeBranches(progs, sig)
+	if bc == nil || err != nil {
+		return false, err
+	}
+	for name, f := range files {
+		f.branches = bc.lines[name]
+		for _, fn := range f.functions {
+			for _, b := range bc.funcs[fn.symbol] {
+				fn.branches += len(b.taken)
+				fn.coveredBranches += b.takenCount()
+			}
+			f.totalBranches += fn.branches
+			f.coveredBranches += fn.coveredBranches
+		}
+	}
+	return true, nil
+}
+
+func (b *branch) takenCount() int {
+	n := 0
+	for _, taken := range b.taken {
+		if taken {
+			n++
+		}
+	}
+	return n
+}
+
+func uniqueLines(lines []int) []int {
+	sort.Ints(lines)
+	n := 0
+	for i, line := range lines {
+		if i == 0 || line != lines[n-1] {
+			lines[n] = line
+			n++
+		}
+	}
+	return lines[:n]
+}
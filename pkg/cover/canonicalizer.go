@@ -5,22 +5,44 @@ package cover
 
 import (
 	"fmt"
+	"slices"
 	"sort"
+	"sync"
 
+	"github.com/google/syzkaller/pkg/cover/backend"
 	"github.com/google/syzkaller/pkg/log"
 	"github.com/google/syzkaller/pkg/vminfo"
 )
 
 type Canonicalizer struct {
-	// Map of modules stored as module name:kernel module.
+	// Map of modules stored as module key (build-id or name, see moduleKey):kernel module.
 	modules map[string]*vminfo.KernelModule
+	// Map of modules stored as module name:kernel module.
+	moduleNames map[string]*vminfo.KernelModule
 
 	// Contains a sorted list of the canonical module addresses.
 	moduleKeys []uint64
+
+	mu    sync.Mutex
+	stats map[string]*ModuleStat
+}
+
+// ModuleStat contains per-module canonicalization diagnostics accumulated over all VM instances.
+type ModuleStat struct {
+	Name    string
+	BuildID string // canonical build-id
+	// Number of instances that loaded the module.
+	Instances int
+	// Number of instances that loaded the module at a different address than the canonical one.
+	Relocated int
+	// Number of instances where coverage of the module was discarded.
+	Mismatches int
+	// Reason of the last mismatch.
+	LastMismatch string
 }
 
 type CanonicalizerInstance struct {
-	canonical Canonicalizer
+	canonical *Canonicalizer
 
 	// Contains the canonicalize and decanonicalize conversion maps.
 	canonicalize   *Convert
@@ -54,28 +76,52 @@ func NewCanonicalizer(modules []*vminfo.KernelModule, flagSignal bool) *Canonica
 	if len(modules) == 0 || !flagSignal {
 		return &Canonicalizer{}
 	}
-	// Create a map of canonical module offsets by name.
+	// Create a map of canonical module offsets by build-id and by name.
 	canonicalModules := make(map[string]*vminfo.KernelModule)
+	moduleNames := make(map[string]*vminfo.KernelModule)
 	for _, module := range modules {
-		canonicalModules[module.Name] = module
+		canonicalModules[moduleKey(module)] = module
+		moduleNames[module.Name] = module
 	}
 
 	// Store sorted canonical address keys.
 	canonicalModuleKeys := make([]uint64, len(modules))
 	setModuleKeys(canonicalModuleKeys, modules)
 	return &Canonicalizer{
-		modules:    canonicalModules,
-		moduleKeys: canonicalModuleKeys,
+		modules:     canonicalModules,
+		moduleNames: moduleNames,
+		moduleKeys:  canonicalModuleKeys,
+	}
+}
+
+// Modules are identified by build-id, if it's known. Build-id is stable across VM restarts and
+// instance types, while the name may refer to a different build of the module.
+func moduleKey(module *vminfo.KernelModule) string {
+	if module.BuildID != "" {
+		return "build-id:" + module.BuildID
 	}
+	return "name:" + module.Name
 }
 
-func (can *Canonicalizer) NewInstance(modules []*vminfo.KernelModule) *CanonicalizerInstance {
+// NewInstance creates conversion maps for a VM instance that loaded the modules.
+// Coverage of the mismatched modules (that don't match the local module objects) is discarded.
+func (can *Canonicalizer) NewInstance(modules []*vminfo.KernelModule,
+	mismatches []backend.ModuleMismatch) *CanonicalizerInstance {
+	for _, mismatch := range mismatches {
+		can.recordMismatch(mismatch.Name, mismatch.Reason)
+	}
 	if can.moduleKeys == nil {
 		return &CanonicalizerInstance{}
 	}
 	// Save sorted list of module offsets.
-	moduleKeys := make([]uint64, len(modules))
-	setModuleKeys(moduleKeys, modules)
+	instModules := slices.Clone(modules)
+	for _, mismatch := range mismatches {
+		if mismatch.Module != nil {
+			instModules = append(instModules, mismatch.Module)
+		}
+	}
+	moduleKeys := make([]uint64, len(instModules))
+	setModuleKeys(moduleKeys, instModules)
 
 	// Create a hash between the "canonical" module addresses and each VM instance.
 	instToCanonicalMap := make(map[uint64]*canonicalizerModule)
@@ -83,14 +129,30 @@ func (can *Canonicalizer) NewInstance(modules []*vminfo.KernelModule) *Canonical
 	for _, module := range modules {
 		discard := false
 		canonicalAddr := uint64(0)
-		canonicalModule, found := can.modules[module.Name]
-		if !found || canonicalModule.Size != module.Size {
-			log.Errorf("kernel build has changed; instance module %v differs from canonical", module.Name)
+		canonicalModule, found := can.modules[moduleKey(module)]
+		if !found && module.BuildID == "" {
+			// Build-id is not known for the instance, fall back to the name.
+			canonicalModule, found = can.moduleNames[module.Name]
+		}
+		mismatch := ""
+		switch {
+		case !found && can.moduleNames[module.Name] != nil:
+			mismatch = fmt.Sprintf("build-id %v differs from canonical %v",
+				module.BuildID, can.moduleNames[module.Name].BuildID)
+		case !found:
+			mismatch = "module is not present in canonical modules"
+		case canonicalModule.Size != module.Size:
+			mismatch = fmt.Sprintf("size 0x%x differs from canonical 0x%x", module.Size, canonicalModule.Size)
+		}
+		if mismatch != "" {
+			log.Errorf("kernel build has changed; instance module %v differs from canonical: %v",
+				module.Name, mismatch)
 			discard = true
 		}
 		if found {
 			canonicalAddr = canonicalModule.Addr
 		}
+		can.recordInstance(module, canonicalModule, mismatch)
 
 		instAddr := module.Addr
 
@@ -108,9 +170,20 @@ func (can *Canonicalizer) NewInstance(modules []*vminfo.KernelModule) *Canonical
 			discard: discard,
 		}
 	}
+	for _, mismatch := range mismatches {
+		if mismatch.Module == nil {
+			continue
+		}
+		// Without the module in the map its PCs would be attributed to the preceding module.
+		instToCanonicalMap[mismatch.Module.Addr] = &canonicalizerModule{
+			name:    mismatch.Name,
+			endAddr: mismatch.Module.Addr + mismatch.Module.Size,
+			discard: true,
+		}
+	}
 
 	return &CanonicalizerInstance{
-		canonical: *can,
+		canonical: can,
 		canonicalize: &Convert{
 			conversionHash: instToCanonicalMap,
 			moduleKeys:     moduleKeys,
@@ -122,6 +195,58 @@ func (can *Canonicalizer) NewInstance(modules []*vminfo.KernelModule) *Canonical
 	}
 }
 
+func (can *Canonicalizer) recordInstance(module, canonicalModule *vminfo.KernelModule, mismatch string) {
+	can.mu.Lock()
+	defer can.mu.Unlock()
+	stat := can.statLocked(module.Name)
+	stat.Instances++
+	if canonicalModule != nil && canonicalModule.Addr != module.Addr {
+		stat.Relocated++
+	}
+	if mismatch != "" {
+		stat.Mismatches++
+		stat.LastMismatch = mismatch
+	}
+}
+
+func (can *Canonicalizer) recordMismatch(name, reason string) {
+	can.mu.Lock()
+	defer can.mu.Unlock()
+	stat := can.statLocked(name)
+	stat.Instances++
+	stat.Mismatches++
+	stat.LastMismatch = reason
+}
+
+func (can *Canonicalizer) statLocked(name string) *ModuleStat {
+	if can.stats == nil {
+		can.stats = make(map[string]*ModuleStat)
+	}
+	stat := can.stats[name]
+	if stat == nil {
+		stat = &ModuleStat{Name: name}
+		if module := can.moduleNames[name]; module != nil {
+			stat.BuildID = module.BuildID
+		}
+		can.stats[name] = stat
+	}
+	return stat
+}
+
+// ModuleStats returns diagnostics for all modules seen on instances sorted by name.
+func (can *Canonicalizer) ModuleStats() []ModuleStat {
+	can.mu.Lock()
+	defer can.mu.Unlock()
+	var ret []ModuleStat
+	for _, stat := range can.stats {
+		ret = append(ret, *stat)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+	return ret
+}
+
 func (ci *CanonicalizerInstance) Canonicalize(elems []uint64) []uint64 {
 	return ci.canonicalize.convertPCs(elems)
 }
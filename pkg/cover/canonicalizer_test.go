@@ -11,6 +11,7 @@ import (
 	"strconv"
 	"testing"
 
+	"github.com/google/syzkaller/pkg/cover/backend"
 	"github.com/google/syzkaller/pkg/vminfo"
 )
 
@@ -224,6 +225,56 @@ func TestChangingModules(t *testing.T) {
 	}
 }
 
+// Tests that modules are matched by build-id and mismatches are accounted in module stats.
+func TestModuleBuildID(t *testing.T) {
+	serv := &RPCServer{
+		fuzzers: make(map[string]*Fuzzer),
+	}
+
+	f1Modules := initModules([]uint64{0x00015000, 0x00020000}, []uint64{0x5000, 0x5000})
+	f1Modules[0].BuildID = "aa"
+	f1Modules[1].BuildID = "bb"
+	serv.connect("f1", f1Modules, true)
+
+	// Module 0 is loaded at a different address, module 1 is a different build of the module.
+	f2Modules := initModules([]uint64{0x00030000, 0x00040000}, []uint64{0x5000, 0x5000})
+	f2Modules[0].BuildID = "aa"
+	f2Modules[1].BuildID = "cc"
+	serv.connect("f2", f2Modules, true)
+
+	// Module 2 does not match the local module object, its coverage must be discarded.
+	f3Modules := initModules([]uint64{0x00015000, 0x00020000}, []uint64{0x5000, 0x5000})
+	f3Modules[0].BuildID = "aa"
+	f3Modules[1].BuildID = "bb"
+	serv.fuzzers["f3"] = &Fuzzer{
+		instModules: serv.canonicalModules.NewInstance(f3Modules, []backend.ModuleMismatch{{
+			Name:   "2",
+			Reason: "no module object file",
+			Module: &vminfo.KernelModule{Name: "2", Addr: 0x00050000, Size: 0x5000},
+		}}),
+	}
+
+	serv.fuzzers["f1"].cov = []uint64{0x00010000, 0x00015010, 0x00020010}
+	serv.fuzzers["f1"].goalCov = []uint64{0x00010000, 0x00015010, 0x00020010}
+	serv.fuzzers["f2"].cov = []uint64{0x00010000, 0x00030010, 0x00040010}
+	serv.fuzzers["f2"].goalCov = []uint64{0x00010000, 0x00015010}
+	serv.fuzzers["f3"].cov = []uint64{0x00010000, 0x00015010, 0x00050010, 0x00020010}
+	serv.fuzzers["f3"].goalCov = []uint64{0x00010000, 0x00015010, 0x00020010}
+	if err := serv.runTest(Canonicalize); err != "" {
+		t.Fatalf("failed in canonicalization: %v", err)
+	}
+
+	want := []ModuleStat{
+		{Name: "0", BuildID: "aa", Instances: 3, Relocated: 1},
+		{Name: "1", BuildID: "bb", Instances: 3, Mismatches: 1,
+			LastMismatch: "build-id cc differs from canonical bb"},
+		{Name: "2", Instances: 1, Mismatches: 1, LastMismatch: "no module object file"},
+	}
+	if got := serv.canonicalModules.ModuleStats(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("wrong module stats:\n%+v\nwant:\n%+v", got, want)
+	}
+}
+
 func (serv *RPCServer) runTest(val canonicalizeValue) string {
 	var cov []uint64
 	for name, fuzzer := range serv.fuzzers {
@@ -253,7 +304,7 @@ func (serv *RPCServer) connect(name string, modules []*vminfo.KernelModule, flag
 	}
 
 	serv.fuzzers[name] = &Fuzzer{
-		instModules: serv.canonicalModules.NewInstance(modules),
+		instModules: serv.canonicalModules.NewInstance(modules, nil),
 	}
 }
 
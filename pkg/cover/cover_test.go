@@ -4,12 +4,19 @@
 package cover
 
 import (
+	"bytes"
 	"fmt"
+	"path/filepath"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/syzkaller/pkg/cover/backend"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/signal"
+	"github.com/google/syzkaller/pkg/vminfo"
+	"github.com/google/syzkaller/sys/targets"
 )
 
 func TestMergeDiff(t *testing.T) {
@@ -118,3 +125,104 @@ func TestPerLineCoverage(t *testing.T) {
 		t.Fatal(diff)
 	}
 }
+
+func TestBranchCoverage(t *testing.T) {
+	module := &vminfo.KernelModule{}
+	src := filepath.Join(t.TempDir(), "foo.c")
+	if err := osutil.WriteFile(src, []byte(strings.Repeat("line\n", 13))); err != nil {
+		t.Fatal(err)
+	}
+	unit := &backend.CompileUnit{
+		ObjectUnit: backend.ObjectUnit{Name: "foo.c", PCs: []uint64{0x1001, 0x1002, 0x1004, 0x1008}},
+		Path:       src,
+		Module:     module,
+	}
+	lines := map[uint64]int{
+		0x1001: 10, 0x1002: 11, 0x1004: 12, 0x1008: 13,
+		// Branches.
+		0x1010: 10, 0x1020: 12, 0x1030: 11,
+	}
+	rg := &ReportGenerator{
+		target: targets.Get(targets.TestOS, targets.TestArch64),
+		Impl: &backend.Impl{
+			Units: []*backend.CompileUnit{unit},
+			Symbols: []*backend.Symbol{{
+				ObjectUnit: backend.ObjectUnit{Name: "foo", PCs: unit.PCs},
+				Module:     module,
+				Unit:       unit,
+				Start:      0x1000,
+				End:        0x1100,
+			}},
+			Symbolize: func(pcs map[*vminfo.KernelModule][]uint64) ([]backend.Frame, error) {
+				var frames []backend.Frame
+				for _, pc := range pcs[module] {
+					frames = append(frames, backend.Frame{
+						Module: module,
+						PC:     pc,
+						Name:   "foo.c",
+						Range:  backend.Range{StartLine: lines[pc], EndLine: lines[pc]},
+					})
+				}
+				return frames, nil
+			},
+			Branches: func() ([]backend.Branch, error) {
+				return []backend.Branch{
+					{PC: 0x1010, Edges: [2][]backend.Edge{{{From: 0x1001, To: 0x1002}}, {{From: 0x1001, To: 0x1004}}}},
+					{PC: 0x1020, Edges: [2][]backend.Edge{{{From: 0x1004, To: 0x1008}}, {{From: 0x1004, To: 0x1002}}}},
+					// Not executed.
+					{PC: 0x1030, Edges: [2][]backend.Edge{{{From: 0x1002, To: 0x1004}}, {{From: 0x1002, To: 0x1008}}}},
+				}, nil
+			},
+		},
+	}
+	progs := []Prog{{PCs: []uint64{0x1001, 0x1004, 0x1008}}}
+	// Edge signal for TestOS is pc ^ (prev & 0xfff).
+	sig := signal.FromRaw([]uint64{0x1004 ^ 0x001, 0x1008 ^ 0x004}, 0)
+	files, err := rg.prepareFileMap(progs, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edges, err := rg.addBranches(files, progs, sig)
+	if err != nil || !edges {
+		t.Fatalf("addBranches: %v %v", edges, err)
+	}
+	f := files["foo.c"]
+	if f.totalBranches != 6 || f.coveredBranches != 2 {
+		t.Fatalf("file branches %v/%v, want 2/6", f.coveredBranches, f.totalBranches)
+	}
+	if fn := f.functions[0]; fn.branches != 6 || fn.coveredBranches != 2 {
+		t.Fatalf("function branches %v/%v, want 2/6", fn.coveredBranches, fn.branches)
+	}
+	want := map[int]string{
+		10: "<span class='both' title='0x1010 fall through to line 11: not taken&#10;" +
+			"0x1010 jump to line 12: taken&#10;'>1/2</span>",
+		11: "<span class='' title='0x1030 fall through to line 12: not taken&#10;" +
+			"0x1030 jump to line 13: not taken&#10;'>0/2</span>",
+		12: "<span class='both' title='0x1020 fall through to line 13: taken&#10;" +
+			"0x1020 jump to line 11: not taken&#10;'>1/2</span>",
+		13: "",
+	}
+	for line, want := range want {
+		if got := lineBranches(f.branches[line]); got != want {
+			t.Errorf("line %v: got %q, want %q", line, got, want)
+		}
+	}
+
+	html := new(bytes.Buffer)
+	if err := rg.DoHTML(html, HandlerParams{Progs: progs, Signal: sig}); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{want[10], "branches 34%", "Total branch coverage"} {
+		if !strings.Contains(html.String(), want) {
+			t.Errorf("HTML report does not contain %q", want)
+		}
+	}
+	// Without the edge signal there is no branch coverage.
+	html.Reset()
+	if err := rg.DoHTML(html, HandlerParams{Progs: progs}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(html.String(), "Total branch coverage") {
+		t.Errorf("HTML report contains branch coverage without edges")
+	}
+}
@@ -0,0 +1,277 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package cover
+
+import (
+	"bufio"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cloud.google.com/go/civil"
+	"github.com/google/syzkaller/pkg/coveragedb"
+	"github.com/google/syzkaller/pkg/covermerger"
+	"github.com/google/syzkaller/pkg/subsystem"
+)
+
+// LineCoverage is a coverage snapshot mapped to source lines and functions.
+type LineCoverage struct {
+	files map[string]*exportFile
+}
+
+func (rg *ReportGenerator) LineCoverage(params HandlerParams) (*LineCoverage, error) {
+	files, err := rg.exportFiles(params)
+	if err != nil {
+		return nil, err
+	}
+	cov := &LineCoverage{files: make(map[string]*exportFile)}
+	for _, f := range files {
+		cov.files[f.name] = f
+	}
+	return cov, nil
+}
+
+// FileCoverage returns the number of instrumented and covered source lines per file.
+func (cov *LineCoverage) FileCoverage() map[string]*coveragedb.Coverage {
+	res := make(map[string]*coveragedb.Coverage)
+	for name, f := range cov.files {
+		covered, total := countCoveredLines(f.lines)
+		res[name] = &coveragedb.Coverage{
+			Instrumented: int64(total),
+			Covered:      int64(covered),
+		}
+	}
+	return res
+}
+
+// SaveDayCoverage saves the line coverage of the programs to the storage as the coverage of the namespace
+// for the date. Saving the same date again replaces the previous data.
+func (rg *ReportGenerator) SaveDayCoverage(ctx context.Context, storage coveragedb.Storage, ns string,
+	date civil.Date, params HandlerParams, sss []*subsystem.Subsystem) error {
+	cov, err := rg.LineCoverage(params)
+	if err != nil {
+		return err
+	}
+	var totalRows int64
+	for _, prog := range params.Progs {
+		totalRows += int64(len(prog.PCs))
+	}
+	return storage.SaveMergeResult(ctx, cov.FileCoverage(), &coveragedb.HistoryRecord{
+		Namespace: ns,
+		Duration:  1,
+		DateTo:    date,
+	}, totalRows, sss)
+}
+
+// CoverageDiff describes lines and functions that gained or lost coverage between two snapshots.
+type CoverageDiff struct {
+	GainedLines int                 `json:"gained_lines"`
+	LostLines   int                 `json:"lost_lines"`
+	Files       []*FileCoverageDiff `json:"files"`
+}
+
+type FileCoverageDiff struct {
+	File string `json:"file"`
+	// Covered lines that were not covered in the base snapshot (line numbers in the new source version).
+	GainedLines []int `json:"gained_lines,omitempty"`
+	// Lines that are not covered anymore (line numbers in the base source version).
+	// Lines removed from the source are not reported.
+	LostLines []int                   `json:"lost_lines,omitempty"`
+	Functions []*FunctionCoverageDiff `json:"functions,omitempty"`
+}
+
+// FunctionCoverageDiff contains numbers of covered and instrumented lines of a function in both snapshots.
+type FunctionCoverageDiff struct {
+	Name        string `json:"name"`
+	BaseCovered int    `json:"base_covered"`
+	BaseTotal   int    `json:"base_total"`
+	Covered     int    `json:"covered"`
+	Total       int    `json:"total"`
+}
+
+// DiffCoverage compares the base coverage snapshot with the new one.
+// The snapshots may be taken on different kernel builds. If the source files differ,
+// lines are matched between the versions of the files.
+func DiffCoverage(base, cur *LineCoverage) *CoverageDiff {
+	names := make(map[string]bool)
+	for name := range base.files {
+		names[name] = true
+	}
+	for name := range cur.files {
+		names[name] = true
+	}
+	var sortedNames []string
+	for name := range names {
+		sortedNames = append(sortedNames, name)
+	}
+	sort.Strings(sortedNames)
+	diff := new(CoverageDiff)
+	for _, name := range sortedNames {
+		fd := diffFileCoverage(name, base.files[name], cur.files[name])
+		if len(fd.GainedLines) == 0 && len(fd.LostLines) == 0 && len(fd.Functions) == 0 {
+			continue
+		}
+		diff.GainedLines += len(fd.GainedLines)
+		diff.LostLines += len(fd.LostLines)
+		diff.Files = append(diff.Files, fd)
+	}
+	return diff
+}
+
+func diffFileCoverage(name string, base, cur *exportFile) *FileCoverageDiff {
+	fd := &FileCoverageDiff{File: name}
+	baseToCur := func(line int) int { return line }
+	if base != nil && cur != nil {
+		baseToCur = matchSourceLines(base.path, cur.path)
+	}
+	curToBase := make(map[int]int)
+	if base != nil {
+		for line := range base.lines {
+			if curLine := baseToCur(line); curLine != 0 {
+				curToBase[curLine] = line
+			}
+		}
+	}
+	if cur != nil {
+		for line, hits := range cur.lines {
+			if hits == 0 {
+				continue
+			}
+			if baseLine, ok := curToBase[line]; !ok || base.lines[baseLine] == 0 {
+				fd.GainedLines = append(fd.GainedLines, line)
+			}
+		}
+	}
+	if base != nil {
+		for line, hits := range base.lines {
+			if hits == 0 {
+				continue
+			}
+			if cur == nil {
+				fd.LostLines = append(fd.LostLines, line)
+				continue
+			}
+			if curLine := baseToCur(line); curLine != 0 && cur.lines[curLine] == 0 {
+				fd.LostLines = append(fd.LostLines, line)
+			}
+		}
+	}
+	sort.Ints(fd.GainedLines)
+	sort.Ints(fd.LostLines)
+	fd.Functions = diffFunctionCoverage(base, cur)
+	return fd
+}
+
+func diffFunctionCoverage(base, cur *exportFile) []*FunctionCoverageDiff {
+	functions := make(map[string]*FunctionCoverageDiff)
+	get := func(name string) *FunctionCoverageDiff {
+		if functions[name] == nil {
+			functions[name] = &FunctionCoverageDiff{Name: name}
+		}
+		return functions[name]
+	}
+	if base != nil {
+		for _, fn := range base.functions {
+			fd := get(fn.name)
+			fd.BaseCovered, fd.BaseTotal = countCoveredLines(fn.lines)
+		}
+	}
+	if cur != nil {
+		for _, fn := range cur.functions {
+			fd := get(fn.name)
+			fd.Covered, fd.Total = countCoveredLines(fn.lines)
+		}
+	}
+	var ret []*FunctionCoverageDiff
+	for _, fd := range functions {
+		if fd.BaseCovered != fd.Covered {
+			ret = append(ret, fd)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+	return ret
+}
+
+func countCoveredLines(lines map[int]int) (int, int) {
+	covered := 0
+	for _, hits := range lines {
+		if hits != 0 {
+			covered++
+		}
+	}
+	return covered, len(lines)
+}
+
+// matchSourceLines returns a function that maps 1-based lines of the base version of the file
+// to the lines of the new version (0 means that the line was changed or removed).
+// If any of the files can't be read, lines are assumed to be the same.
+func matchSourceLines(basePath, curPath string) func(int) int {
+	identity := func(line int) int { return line }
+	baseText, err := os.ReadFile(basePath)
+	if err != nil {
+		return identity
+	}
+	curText, err := os.ReadFile(curPath)
+	if err != nil || string(baseText) == string(curText) {
+		return identity
+	}
+	matcher := covermerger.MakeLineToLineMatcher(string(baseText), string(curText))
+	numLines := strings.Count(string(baseText), "\n") + 1
+	return func(line int) int {
+		if line < 1 || line > numLines {
+			return 0
+		}
+		return matcher.SameLinePos(line-1) + 1
+	}
+}
+
+func (diff *CoverageDiff) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(diff)
+}
+
+func (diff *CoverageDiff) WriteHTML(w io.Writer) error {
+	return coverDiffTemplate.Execute(w, diff)
+}
+
+// ParseRawCover parses raw coverage in the format produced by DoRawCover (one PC per line).
+func ParseRawCover(r io.Reader) ([]uint64, error) {
+	var pcs []uint64
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" {
+			continue
+		}
+		pc, err := strconv.ParseUint(line, 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad raw coverage line %q: %w", line, err)
+		}
+		pcs = append(pcs, pc)
+	}
+	return pcs, s.Err()
+}
+
+//go:embed templates/coverdiff.html
+var templatesCoverDiff string
+
+var coverDiffTemplate = template.Must(template.New("coverdiff").Funcs(template.FuncMap{
+	"joinLines": func(lines []int) string {
+		var strs []string
+		for _, line := range lines {
+			strs = append(strs, strconv.Itoa(line))
+		}
+		return strings.Join(strs, ", ")
+	},
+}).Parse(templatesCoverDiff))
@@ -0,0 +1,122 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package cover
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/coveragedb"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffCoverage(t *testing.T) {
+	dir := t.TempDir()
+	writeSource := func(name, text string) string {
+		path := filepath.Join(dir, name)
+		if err := osutil.WriteFile(path, []byte(text)); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	file := func(name, path string, lines map[int]int, functions map[string][]int) *exportFile {
+		f := &exportFile{
+			name:      name,
+			path:      path,
+			lines:     lines,
+			functions: make(map[string]*exportFunction),
+		}
+		for fn, fnLines := range functions {
+			f.functions[fn] = &exportFunction{name: fn, lines: make(map[int]int)}
+			for _, line := range fnLines {
+				f.functions[fn].lines[line] = lines[line]
+			}
+		}
+		return f
+	}
+	oldSource := "a\nb\nc\nd\n"
+	// A line is inserted at the beginning, so all lines are shifted.
+	newSource := "new\na\nb\nc\nd\n"
+	base := &LineCoverage{files: map[string]*exportFile{
+		"same.c": file("same.c", writeSource("same-old.c", oldSource),
+			map[int]int{1: 1, 2: 0, 3: 1}, map[string][]int{"foo": {1, 2}, "bar": {3}}),
+		"moved.c": file("moved.c", writeSource("moved-old.c", oldSource),
+			map[int]int{1: 1, 2: 1, 3: 0, 4: 1}, map[string][]int{"baz": {1, 2, 3, 4}}),
+		"gone.c": file("gone.c", filepath.Join(dir, "missing.c"),
+			map[int]int{5: 1, 6: 0}, map[string][]int{"gone": {5, 6}}),
+	}}
+	cur := &LineCoverage{files: map[string]*exportFile{
+		"same.c": file("same.c", writeSource("same-new.c", oldSource),
+			map[int]int{1: 1, 2: 2, 3: 0}, map[string][]int{"foo": {1, 2}, "bar": {3}}),
+		"moved.c": file("moved.c", writeSource("moved-new.c", newSource),
+			map[int]int{1: 1, 2: 1, 3: 0, 4: 1, 5: 1}, map[string][]int{"baz": {1, 2, 3, 4, 5}}),
+	}}
+	diff := DiffCoverage(base, cur)
+	assert.Equal(t, &CoverageDiff{
+		GainedLines: 3,
+		LostLines:   3,
+		Files: []*FileCoverageDiff{
+			{
+				File:      "gone.c",
+				LostLines: []int{5},
+				Functions: []*FunctionCoverageDiff{
+					{Name: "gone", BaseCovered: 1, BaseTotal: 2},
+				},
+			},
+			{
+				File: "moved.c",
+				// Line 1 is new, line 4 is old line 3.
+				GainedLines: []int{1, 4},
+				// Old line 2 is new line 3.
+				LostLines: []int{2},
+				Functions: []*FunctionCoverageDiff{
+					{Name: "baz", BaseCovered: 3, BaseTotal: 4, Covered: 4, Total: 5},
+				},
+			},
+			{
+				File:        "same.c",
+				GainedLines: []int{2},
+				LostLines:   []int{3},
+				Functions: []*FunctionCoverageDiff{
+					{Name: "bar", BaseCovered: 1, BaseTotal: 1, Covered: 0, Total: 1},
+					{Name: "foo", BaseCovered: 1, BaseTotal: 2, Covered: 2, Total: 2},
+				},
+			},
+		},
+	}, diff)
+
+	html := new(bytes.Buffer)
+	if err := diff.WriteHTML(html); err != nil {
+		t.Fatal(err)
+	}
+	assert.Contains(t, html.String(), "Gained lines: 1, 4")
+	js := new(bytes.Buffer)
+	if err := diff.WriteJSON(js); err != nil {
+		t.Fatal(err)
+	}
+	var decoded CoverageDiff
+	if err := json.Unmarshal(js.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, diff, &decoded)
+
+	assert.Equal(t, map[string]*coveragedb.Coverage{
+		"same.c":  {Instrumented: 3, Covered: 2},
+		"moved.c": {Instrumented: 5, Covered: 4},
+	}, cur.FileCoverage())
+}
+
+func TestParseRawCover(t *testing.T) {
+	pcs, err := ParseRawCover(strings.NewReader("0xffffffff81000010\n\n0x20\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []uint64{0xffffffff81000010, 0x20}, pcs)
+	_, err = ParseRawCover(strings.NewReader("0x10\nfoo\n"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,372 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package cover
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/syzkaller/pkg/cover/backend"
+)
+
+// exportFile is the coverage of a single source file for the LCOV and Cobertura exports.
+// Hit counts are the numbers of programs that cover the corresponding PCs.
+type exportFile struct {
+	name      string
+	path      string
+	lines     map[int]int
+	branches  map[int][]lineBranch // nil if there is no edge signal
+	functions map[string]*exportFunction
+}
+
+type exportFunction struct {
+	name  string
+	line  int
+	hits  int
+	lines map[int]int
+}
+
+func (rg *ReportGenerator) exportFiles(params HandlerParams) ([]*exportFile, error) {
+	// Symbolize all coverage points, so that uncovered functions are exported as well.
+	if rg.CallbackPoints != nil {
+		if err := rg.symbolizePCs(rg.CallbackPoints); err != nil {
+			return nil, fmt.Errorf("failed to symbolize PCs(): %w", err)
+		}
+	}
+	progs := fixUpPCs(rg.target.Arch, params.Progs, params.Filter)
+	if err := rg.symbolizePCs(uniquePCs(progs)); err != nil {
+		return nil, err
+	}
+	branches, err := rg.computeBranches(progs, params.Signal)
+	if err != nil {
+		return nil, err
+	}
+	progPCs := make(map[uint64]int)
+	for _, prog := range progs {
+		for _, pc := range prog.PCs {
+			progPCs[pc]++
+		}
+	}
+	frames := append([]backend.Frame{}, rg.Frames...)
+	sort.SliceStable(frames, func(i, j int) bool {
+		return frames[i].PC < frames[j].PC
+	})
+	files := make(map[string]*exportFile)
+	for _, frame := range frames {
+		f := files[frame.Name]
+		if f == nil {
+			f = &exportFile{
+				name:      frame.Name,
+				path:      frame.Path,
+				lines:     make(map[int]int),
+				functions: make(map[string]*exportFunction),
+			}
+			if branches != nil {
+				f.branches = branches.lines[frame.Name]
+				if f.branches == nil {
+					f.branches = make(map[int][]lineBranch)
+				}
+			}
+			files[frame.Name] = f
+		}
+		hits := progPCs[frame.PC]
+		line := frame.StartLine
+		f.lines[line] = max(f.lines[line], hits)
+		if frame.FuncName == "" {
+			continue
+		}
+		fn := f.functions[frame.FuncName]
+		if fn == nil {
+			fn = &exportFunction{
+				name:  frame.FuncName,
+				line:  line,
+				lines: make(map[int]int),
+			}
+			f.functions[frame.FuncName] = fn
+		}
+		// We don't know where the function starts, the first coverage point is the best approximation.
+		fn.line = min(fn.line, line)
+		fn.hits = max(fn.hits, hits)
+		fn.lines[line] = max(fn.lines[line], hits)
+	}
+	var ret []*exportFile
+	for _, f := range files {
+		ret = append(ret, f)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].name < ret[j].name
+	})
+	return ret, nil
+}
+
+func (f *exportFile) sortedFunctions() []*exportFunction {
+	var ret []*exportFunction
+	for _, fn := range f.functions {
+		ret = append(ret, fn)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].line != ret[j].line {
+			return ret[i].line < ret[j].line
+		}
+		return ret[i].name < ret[j].name
+	})
+	return ret
+}
+
+func (f *exportFile) branchLines() []int {
+	var ret []int
+	for line := range f.branches {
+		ret = append(ret, line)
+	}
+	sort.Ints(ret)
+	return ret
+}
+
+func sortedLines(lines map[int]int) []int {
+	var ret []int
+	for line := range lines {
+		ret = append(ret, line)
+	}
+	sort.Ints(ret)
+	return ret
+}
+
+// DoLCOV exports the coverage in the LCOV tracefile format (see geninfo(1)),
+// which can be consumed by genhtml and merged with other tracefiles with lcov.
+func (rg *ReportGenerator) DoLCOV(w io.Writer, params HandlerParams) error {
+	files, err := rg.exportFiles(params)
+	if err != nil {
+		return err
+	}
+	buf := bufio.NewWriter(w)
+	for _, f := range files {
+		fmt.Fprintf(buf, "TN:\nSF:%v\n", f.path)
+		functions := f.sortedFunctions()
+		functionsHit := 0
+		for _, fn := range functions {
+			fmt.Fprintf(buf, "FN:%v,%v\n", fn.line, fn.name)
+		}
+		for _, fn := range functions {
+			fmt.Fprintf(buf, "FNDA:%v,%v\n", fn.hits, fn.name)
+			if fn.hits != 0 {
+				functionsHit++
+			}
+		}
+		fmt.Fprintf(buf, "FNF:%v\nFNH:%v\n", len(functions), functionsHit)
+		if f.branches != nil {
+			branchesFound, branchesHit := 0, 0
+			for _, line := range f.branchLines() {
+				// Each branch is a block with the fall through and the jump outcomes.
+				for block, b := range f.branches[line] {
+					for i, ok := range b.taken {
+						// "-" means that the branch was not executed at all.
+						taken := "-"
+						if b.executed {
+							taken = "0"
+							if ok {
+								taken = "1"
+							}
+						}
+						fmt.Fprintf(buf, "BRDA:%v,%v,%v,%v\n", line, block, i, taken)
+						branchesFound++
+						if ok {
+							branchesHit++
+						}
+					}
+				}
+			}
+			fmt.Fprintf(buf, "BRF:%v\nBRH:%v\n", branchesFound, branchesHit)
+		}
+		lines := sortedLines(f.lines)
+		linesHit := 0
+		for _, line := range lines {
+			fmt.Fprintf(buf, "DA:%v,%v\n", line, f.lines[line])
+			if f.lines[line] != 0 {
+				linesHit++
+			}
+		}
+		fmt.Fprintf(buf, "LF:%v\nLH:%v\nend_of_record\n", len(lines), linesHit)
+	}
+	return buf.Flush()
+}
+
+type coberturaCoverage struct {
+	XMLName         xml.Name           `xml:"coverage"`
+	LineRate        string             `xml:"line-rate,attr"`
+	BranchRate      string             `xml:"branch-rate,attr"`
+	LinesCovered    int                `xml:"lines-covered,attr"`
+	LinesValid      int                `xml:"lines-valid,attr"`
+	BranchesCovered int                `xml:"branches-covered,attr"`
+	BranchesValid   int                `xml:"branches-valid,attr"`
+	Complexity      int                `xml:"complexity,attr"`
+	Version         string             `xml:"version,attr"`
+	Timestamp       int64              `xml:"timestamp,attr"`
+	Sources         []string           `xml:"sources>source"`
+	Packages        []coberturaPackage `xml:"packages>package"`
+}
+
+type coberturaPackage struct {
+	Name       string           `xml:"name,attr"`
+	LineRate   string           `xml:"line-rate,attr"`
+	BranchRate string           `xml:"branch-rate,attr"`
+	Complexity int              `xml:"complexity,attr"`
+	Classes    []coberturaClass `xml:"classes>class"`
+}
+
+type coberturaClass struct {
+	Name       string            `xml:"name,attr"`
+	Filename   string            `xml:"filename,attr"`
+	LineRate   string            `xml:"line-rate,attr"`
+	BranchRate string            `xml:"branch-rate,attr"`
+	Complexity int               `xml:"complexity,attr"`
+	Methods    []coberturaMethod `xml:"methods>method"`
+	Lines      []coberturaLine   `xml:"lines>line"`
+}
+
+type coberturaMethod struct {
+	Name       string          `xml:"name,attr"`
+	Signature  string          `xml:"signature,attr"`
+	LineRate   string          `xml:"line-rate,attr"`
+	BranchRate string          `xml:"branch-rate,attr"`
+	Complexity int             `xml:"complexity,attr"`
+	Lines      []coberturaLine `xml:"lines>line"`
+}
+
+type coberturaLine struct {
+	Number            int    `xml:"number,attr"`
+	Hits              int    `xml:"hits,attr"`
+	Branch            bool   `xml:"branch,attr"`
+	ConditionCoverage string `xml:"condition-coverage,attr,omitempty"`
+}
+
+// coberturaStats accumulates covered/total lines and branches for the rate attributes.
+type coberturaStats struct {
+	lines, linesHit       int
+	branches, branchesHit int
+}
+
+func (stats *coberturaStats) add(other coberturaStats) {
+	stats.lines += other.lines
+	stats.linesHit += other.linesHit
+	stats.branches += other.branches
+	stats.branchesHit += other.branchesHit
+}
+
+func (stats coberturaStats) lineRate() string {
+	return coberturaRate(stats.linesHit, stats.lines)
+}
+
+func (stats coberturaStats) branchRate() string {
+	return coberturaRate(stats.branchesHit, stats.branches)
+}
+
+func coberturaRate(covered, total int) string {
+	rate := 0.0
+	if total != 0 {
+		rate = float64(covered) / float64(total)
+	}
+	return strconv.FormatFloat(rate, 'f', 4, 64)
+}
+
+// DoCobertura exports the coverage in the Cobertura XML format understood by most CI systems.
+// Source directories are exported as packages and source files as classes.
+func (rg *ReportGenerator) DoCobertura(w io.Writer, params HandlerParams) error {
+	files, err := rg.exportFiles(params)
+	if err != nil {
+		return err
+	}
+	cov := &coberturaCoverage{
+		Version:   "syzkaller",
+		Timestamp: time.Now().Unix(),
+		Sources:   []string{rg.srcDir},
+	}
+	var total coberturaStats
+	packages := make(map[string]*coberturaPackage)
+	packageStats := make(map[string]*coberturaStats)
+	var packageNames []string
+	for _, f := range files {
+		lines, stats := coberturaLines(f.lines, f)
+		class := coberturaClass{
+			Name:       f.name,
+			Filename:   f.name,
+			LineRate:   stats.lineRate(),
+			BranchRate: stats.branchRate(),
+			Lines:      lines,
+		}
+		for _, fn := range f.sortedFunctions() {
+			fnLines, fnStats := coberturaLines(fn.lines, f)
+			class.Methods = append(class.Methods, coberturaMethod{
+				Name:       fn.name,
+				LineRate:   fnStats.lineRate(),
+				BranchRate: fnStats.branchRate(),
+				Lines:      fnLines,
+			})
+		}
+		dir := filepath.Dir(f.name)
+		if packages[dir] == nil {
+			packages[dir] = &coberturaPackage{Name: dir}
+			packageStats[dir] = new(coberturaStats)
+			packageNames = append(packageNames, dir)
+		}
+		packages[dir].Classes = append(packages[dir].Classes, class)
+		packageStats[dir].add(stats)
+		total.add(stats)
+	}
+	sort.Strings(packageNames)
+	for _, name := range packageNames {
+		pkg := packages[name]
+		pkg.LineRate = packageStats[name].lineRate()
+		pkg.BranchRate = packageStats[name].branchRate()
+		cov.Packages = append(cov.Packages, *pkg)
+	}
+	cov.LineRate = total.lineRate()
+	cov.BranchRate = total.branchRate()
+	cov.LinesValid, cov.LinesCovered = total.lines, total.linesHit
+	cov.BranchesValid, cov.BranchesCovered = total.branches, total.branchesHit
+
+	if _, err := io.WriteString(w, xml.Header+
+		`<!DOCTYPE coverage SYSTEM "http://cobertura.sourceforge.net/xml/coverage-04.dtd">`+"\n"); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "\t")
+	if err := encoder.Encode(cov); err != nil {
+		return fmt.Errorf("failed to encode cobertura report: %w", err)
+	}
+	return nil
+}
+
+func coberturaLines(hits map[int]int, f *exportFile) ([]coberturaLine, coberturaStats) {
+	var ret []coberturaLine
+	var stats coberturaStats
+	for _, line := range sortedLines(hits) {
+		cl := coberturaLine{
+			Number: line,
+			Hits:   hits[line],
+		}
+		stats.lines++
+		if cl.Hits != 0 {
+			stats.linesHit++
+		}
+		if branches := f.branches[line]; len(branches) != 0 {
+			taken, total := 0, 0
+			for _, b := range branches {
+				taken += b.takenCount()
+				total += len(b.taken)
+			}
+			cl.Branch = true
+			cl.ConditionCoverage = fmt.Sprintf("%v%% (%v/%v)", taken*100/total, taken, total)
+			stats.branches += total
+			stats.branchesHit += taken
+		}
+		ret = append(ret, cl)
+	}
+	return ret, stats
+}
@@ -0,0 +1,180 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package cover
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/cover/backend"
+	"github.com/google/syzkaller/pkg/signal"
+	"github.com/google/syzkaller/pkg/vminfo"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func makeExportTestGenerator() *ReportGenerator {
+	frame := func(pc uint64, name, funcName string, line int) backend.Frame {
+		return backend.Frame{
+			PC:       pc,
+			Name:     name,
+			Path:     "/src/" + name,
+			FuncName: funcName,
+			Range: backend.Range{
+				StartLine: line,
+				EndLine:   line,
+				EndCol:    backend.LineEnd,
+			},
+		}
+	}
+	symbol := func(name string, start uint64) *backend.Symbol {
+		return &backend.Symbol{
+			ObjectUnit: backend.ObjectUnit{Name: name},
+			Start:      start,
+			End:        start + 0xf,
+			Symbolized: true,
+		}
+	}
+	branchFrames := map[uint64]backend.Frame{
+		0x18: frame(0x18, "net/foo.c", "foo", 11),
+		0x28: frame(0x28, "net/foo.c", "bar", 20),
+	}
+	return &ReportGenerator{
+		target: targets.Get(targets.TestOS, targets.TestArch64),
+		srcDir: "/src",
+		Impl: &backend.Impl{
+			Symbols: []*backend.Symbol{symbol("foo", 0x10), symbol("bar", 0x20), symbol("baz", 0x30)},
+			Symbolize: func(pcs map[*vminfo.KernelModule][]uint64) ([]backend.Frame, error) {
+				var frames []backend.Frame
+				for _, pc := range pcs[nil] {
+					frames = append(frames, branchFrames[pc])
+				}
+				return frames, nil
+			},
+			Branches: func() ([]backend.Branch, error) {
+				return []backend.Branch{
+					{PC: 0x18, Edges: [2][]backend.Edge{{{From: 0x10, To: 0x11}}, {{From: 0x10, To: 0x12}}}},
+					// Not executed.
+					{PC: 0x28, Edges: [2][]backend.Edge{{{From: 0x20, To: 0x23}}, {{From: 0x20, To: 0x24}}}},
+				}, nil
+			},
+			Frames: []backend.Frame{
+				frame(0x10, "net/foo.c", "foo", 10),
+				frame(0x11, "net/foo.c", "foo", 11),
+				frame(0x12, "net/foo.c", "foo", 11),
+				frame(0x13, "net/foo.c", "foo", 12),
+				frame(0x20, "net/foo.c", "bar", 20),
+				frame(0x30, "mm/baz.c", "baz", 5),
+			},
+		},
+	}
+}
+
+func exportTestParams(edges bool) HandlerParams {
+	params := HandlerParams{
+		Progs: []Prog{
+			{Sig: "a", PCs: []uint64{0x10, 0x11, 0x13}},
+			{Sig: "b", PCs: []uint64{0x10, 0x30}},
+		},
+	}
+	if edges {
+		// Edge signal for TestOS is pc ^ (prev & 0xfff), only the 0x10->0x11 edge was executed.
+		params.Signal = signal.FromRaw([]uint64{0x11 ^ 0x10}, 0)
+	}
+	return params
+}
+
+func TestDoLCOV(t *testing.T) {
+	rg := makeExportTestGenerator()
+	buf := new(bytes.Buffer)
+	if err := rg.DoLCOV(buf, exportTestParams(true)); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, `TN:
+SF:/src/mm/baz.c
+FN:5,baz
+FNDA:1,baz
+FNF:1
+FNH:1
+BRF:0
+BRH:0
+DA:5,1
+LF:1
+LH:1
+end_of_record
+TN:
+SF:/src/net/foo.c
+FN:10,foo
+FN:20,bar
+FNDA:2,foo
+FNDA:0,bar
+FNF:2
+FNH:1
+BRDA:11,0,0,1
+BRDA:11,0,1,0
+BRDA:20,0,0,-
+BRDA:20,0,1,-
+BRF:4
+BRH:1
+DA:10,2
+DA:11,1
+DA:12,1
+DA:20,0
+LF:4
+LH:3
+end_of_record
+`, buf.String())
+
+	// Without edges there are no branch records.
+	buf.Reset()
+	if err := rg.DoLCOV(buf, exportTestParams(false)); err != nil {
+		t.Fatal(err)
+	}
+	assert.NotContains(t, buf.String(), "BR")
+}
+
+func TestDoCobertura(t *testing.T) {
+	rg := makeExportTestGenerator()
+	buf := new(bytes.Buffer)
+	if err := rg.DoCobertura(buf, exportTestParams(true)); err != nil {
+		t.Fatal(err)
+	}
+	var cov coberturaCoverage
+	if err := xml.Unmarshal(buf.Bytes(), &cov); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"/src"}, cov.Sources)
+	assert.Equal(t, 5, cov.LinesValid)
+	assert.Equal(t, 4, cov.LinesCovered)
+	assert.Equal(t, 4, cov.BranchesValid)
+	assert.Equal(t, 1, cov.BranchesCovered)
+	assert.Equal(t, "0.8000", cov.LineRate)
+	if !assert.Len(t, cov.Packages, 2) {
+		return
+	}
+	assert.Equal(t, "mm", cov.Packages[0].Name)
+	net := cov.Packages[1]
+	assert.Equal(t, "net", net.Name)
+	assert.Equal(t, "0.7500", net.LineRate)
+	assert.Equal(t, "0.2500", net.BranchRate)
+	if !assert.Len(t, net.Classes, 1) {
+		return
+	}
+	class := net.Classes[0]
+	assert.Equal(t, "net/foo.c", class.Filename)
+	assert.Equal(t, []coberturaLine{
+		{Number: 10, Hits: 2},
+		{Number: 11, Hits: 1, Branch: true, ConditionCoverage: "50% (1/2)"},
+		{Number: 12, Hits: 1},
+		{Number: 20, Hits: 0, Branch: true, ConditionCoverage: "0% (0/2)"},
+	}, class.Lines)
+	if !assert.Len(t, class.Methods, 2) {
+		return
+	}
+	assert.Equal(t, "foo", class.Methods[0].Name)
+	assert.Equal(t, "1.0000", class.Methods[0].LineRate)
+	assert.Equal(t, "bar", class.Methods[1].Name)
+	assert.Equal(t, "0.0000", class.Methods[1].LineRate)
+}
@@ -0,0 +1,192 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package cover
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/cover/backend"
+)
+
+// The coverage frontier consists of the uncovered functions that are directly called from covered functions.
+// These are the functions that are the easiest to reach next (e.g. with new or improved descriptions).
+
+type frontierData struct {
+	Subsystems []*frontierSubsystem
+}
+
+type frontierSubsystem struct {
+	Name      string
+	Functions []*frontierFunction
+}
+
+type frontierFunction struct {
+	Name   string
+	File   string
+	Blocks int // number of coverage points in the function
+	// All covered callers, but only the first maxFrontierCallers callers are listed in Callers.
+	NumCallers int
+	Callers    []*frontierCaller
+}
+
+type frontierCaller struct {
+	Name  string
+	File  string
+	Progs int    // number of programs that cover the caller
+	Sig   string // signature of the shortest program that covers the caller
+}
+
+const maxFrontierCallers = 5
+
+// DoFrontier generates the coverage frontier report: uncovered functions called directly from covered code,
+// ranked by the number of covered callers and by size, and grouped by subsystem.
+// Functions that don't belong to any of the configured subsystems are listed under "all".
+func (rg *ReportGenerator) DoFrontier(w io.Writer, params HandlerParams) error {
+	data, err := rg.frontier(params)
+	if err != nil {
+		return err
+	}
+	return frontierTemplate.Execute(w, data)
+}
+
+func (rg *ReportGenerator) frontier(params HandlerParams) (*frontierData, error) {
+	if rg.CallSites == nil {
+		return nil, fmt.Errorf("call graph is not supported for %v", rg.target.Arch)
+	}
+	progs := fixUpPCs(rg.target.Arch, params.Progs, params.Filter)
+	progPCs := make(map[uint64][]int)
+	for i, prog := range progs {
+		for _, pc := range prog.PCs {
+			progPCs[pc] = append(progPCs[pc], i)
+		}
+	}
+	if len(progPCs) == 0 {
+		return nil, fmt.Errorf("no coverage collected so far")
+	}
+	calls, err := rg.CallSites()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read call sites: %w", err)
+	}
+	// Programs that cover each covered symbol.
+	symbolProgs := make(map[*backend.Symbol]map[int]bool)
+	covered := func(sym *backend.Symbol) map[int]bool {
+		if progs, ok := symbolProgs[sym]; ok {
+			return progs
+		}
+		var progs map[int]bool
+		for _, pc := range sym.PCs {
+			for _, idx := range progPCs[pc] {
+				if progs == nil {
+					progs = make(map[int]bool)
+				}
+				progs[idx] = true
+			}
+		}
+		symbolProgs[sym] = progs
+		return progs
+	}
+	callers := make(map[*backend.Symbol]map[*backend.Symbol]bool)
+	for _, call := range calls {
+		callee := rg.findSymbol(call.Target)
+		if callee == nil || callee.Start != call.Target || len(covered(callee)) != 0 {
+			continue
+		}
+		caller := rg.findSymbol(call.PC)
+		if caller == nil || caller == callee || len(covered(caller)) == 0 {
+			continue
+		}
+		if callers[callee] == nil {
+			callers[callee] = make(map[*backend.Symbol]bool)
+		}
+		callers[callee][caller] = true
+	}
+	subsystems := make(map[string]*frontierSubsystem)
+	for callee, calleeCallers := range callers {
+		fn := &frontierFunction{
+			Name:   callee.Name,
+			File:   callee.Unit.Name,
+			Blocks: len(callee.PCs),
+		}
+		for caller := range calleeCallers {
+			fn.Callers = append(fn.Callers, makeFrontierCaller(caller, covered(caller), progs))
+		}
+		fn.NumCallers = len(fn.Callers)
+		sort.Slice(fn.Callers, func(i, j int) bool {
+			if fn.Callers[i].Progs != fn.Callers[j].Progs {
+				return fn.Callers[i].Progs > fn.Callers[j].Progs
+			}
+			return fn.Callers[i].Name < fn.Callers[j].Name
+		})
+		name := rg.frontierSubsystem(fn.File)
+		if subsystems[name] == nil {
+			subsystems[name] = &frontierSubsystem{Name: name}
+		}
+		subsystems[name].Functions = append(subsystems[name].Functions, fn)
+	}
+	data := new(frontierData)
+	for _, subsystem := range subsystems {
+		sort.Slice(subsystem.Functions, func(i, j int) bool {
+			fi, fj := subsystem.Functions[i], subsystem.Functions[j]
+			if fi.NumCallers != fj.NumCallers {
+				return fi.NumCallers > fj.NumCallers
+			}
+			if fi.Blocks != fj.Blocks {
+				return fi.Blocks > fj.Blocks
+			}
+			return fi.Name < fj.Name
+		})
+		for _, fn := range subsystem.Functions {
+			if len(fn.Callers) > maxFrontierCallers {
+				fn.Callers = fn.Callers[:maxFrontierCallers]
+			}
+		}
+		data.Subsystems = append(data.Subsystems, subsystem)
+	}
+	sort.Slice(data.Subsystems, func(i, j int) bool {
+		return data.Subsystems[i].Name < data.Subsystems[j].Name
+	})
+	return data, nil
+}
+
+func makeFrontierCaller(sym *backend.Symbol, progIdx map[int]bool, progs []Prog) *frontierCaller {
+	caller := &frontierCaller{
+		Name:  sym.Name,
+		File:  sym.Unit.Name,
+		Progs: len(progIdx),
+	}
+	best := -1
+	for idx := range progIdx {
+		if best == -1 || len(progs[idx].Data) < len(progs[best].Data) ||
+			len(progs[idx].Data) == len(progs[best].Data) && idx < best {
+			best = idx
+		}
+	}
+	if best != -1 {
+		caller.Sig = progs[best].Sig
+	}
+	return caller
+}
+
+// frontierSubsystem returns the first subsystem that contains the file
+// (the last subsystem is always "all" that contains all files).
+func (rg *ReportGenerator) frontierSubsystem(file string) string {
+	for _, subsystem := range rg.subsystem {
+		for _, path := range subsystem.Paths {
+			if strings.HasPrefix(file, path) {
+				return subsystem.Name
+			}
+		}
+	}
+	return "all"
+}
+
+//go:embed templates/frontier.html
+var templatesFrontier string
+
+var frontierTemplate = template.Must(template.New("frontier").Parse(templatesFrontier))
@@ -0,0 +1,109 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package cover
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/cover/backend"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrontier(t *testing.T) {
+	netUnit := &backend.CompileUnit{ObjectUnit: backend.ObjectUnit{Name: "net/socket.c"}}
+	mmUnit := &backend.CompileUnit{ObjectUnit: backend.ObjectUnit{Name: "mm/slab.c"}}
+	symbol := func(name string, unit *backend.CompileUnit, start uint64, blocks int) *backend.Symbol {
+		sym := &backend.Symbol{
+			ObjectUnit: backend.ObjectUnit{Name: name},
+			Unit:       unit,
+			Start:      start,
+			End:        start + 0x100,
+		}
+		for i := 0; i < blocks; i++ {
+			sym.PCs = append(sym.PCs, start+uint64(i)+1)
+		}
+		return sym
+	}
+	rg := &ReportGenerator{
+		target: targets.Get(targets.TestOS, targets.TestArch64),
+		subsystem: []mgrconfig.Subsystem{
+			{Name: "net", Paths: []string{"net/"}},
+			{Name: "all", Paths: []string{""}},
+		},
+		Impl: &backend.Impl{
+			Symbols: []*backend.Symbol{
+				symbol("sys_socket", netUnit, 0x1000, 2),
+				symbol("sys_bind", netUnit, 0x1100, 2),
+				symbol("sock_alloc", netUnit, 0x1200, 3),
+				symbol("sock_release", netUnit, 0x1300, 5),
+				symbol("kmalloc", mmUnit, 0x1400, 1),
+				symbol("kfree", mmUnit, 0x1500, 1),
+				symbol("unreachable", mmUnit, 0x1600, 10),
+			},
+			CallSites: func() ([]backend.CallSite, error) {
+				return []backend.CallSite{
+					// sock_alloc is called from 2 covered functions.
+					{PC: 0x1010, Target: 0x1200},
+					{PC: 0x1110, Target: 0x1200},
+					// sock_release is bigger, but has only 1 covered caller.
+					{PC: 0x1020, Target: 0x1300},
+					// kmalloc is covered.
+					{PC: 0x1030, Target: 0x1400},
+					// kfree is called from an uncovered function.
+					{PC: 0x1310, Target: 0x1500},
+					// Not a function start.
+					{PC: 0x1040, Target: 0x1610},
+				}, nil
+			},
+		},
+	}
+	params := HandlerParams{
+		Progs: []Prog{
+			{Sig: "long", Data: "long program", PCs: []uint64{0x1001, 0x1101, 0x1401}},
+			{Sig: "short", Data: "short", PCs: []uint64{0x1001}},
+		},
+	}
+	data, err := rg.frontier(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !assert.Len(t, data.Subsystems, 1) {
+		return
+	}
+	net := data.Subsystems[0]
+	assert.Equal(t, "net", net.Name)
+	assert.Equal(t, []*frontierFunction{
+		{
+			Name:       "sock_alloc",
+			File:       "net/socket.c",
+			Blocks:     3,
+			NumCallers: 2,
+			Callers: []*frontierCaller{
+				{Name: "sys_socket", File: "net/socket.c", Progs: 2, Sig: "short"},
+				{Name: "sys_bind", File: "net/socket.c", Progs: 1, Sig: "long"},
+			},
+		},
+		{
+			Name:       "sock_release",
+			File:       "net/socket.c",
+			Blocks:     5,
+			NumCallers: 1,
+			Callers: []*frontierCaller{
+				{Name: "sys_socket", File: "net/socket.c", Progs: 2, Sig: "short"},
+			},
+		},
+	}, net.Functions)
+
+	html := new(bytes.Buffer)
+	if err := rg.DoFrontier(html, params); err != nil {
+		t.Fatal(err)
+	}
+	assert.Contains(t, html.String(), `<a href="/input?sig=short">2 programs</a>`)
+
+	rg.CallSites = nil
+	assert.Error(t, rg.DoFrontier(html, params))
+}
@@ -14,11 +14,9 @@ import (
 	"strings"
 
 	"cloud.google.com/go/civil"
-	"cloud.google.com/go/spanner"
 	"github.com/google/syzkaller/pkg/coveragedb"
 	_ "github.com/google/syzkaller/pkg/subsystem/lists"
 	"golang.org/x/exp/maps"
-	"google.golang.org/api/iterator"
 )
 
 type templateHeatmapRow struct {
@@ -87,15 +85,7 @@ func (thm *templateHeatmapRow) prepareDataFor(dates []civil.Date) {
 	}
 }
 
-type fileCoverageWithDetails struct {
-	Filepath     string
-	Instrumented int64
-	Covered      int64
-	Dateto       civil.Date
-	Subsystems   []string
-}
-
-func filesCoverageToTemplateData(fCov []*fileCoverageWithDetails) *templateHeatmap {
+func filesCoverageToTemplateData(fCov []*coveragedb.FileCoverageWithDetails) *templateHeatmap {
 	res := templateHeatmap{
 		Root: &templateHeatmapRow{
 			builder:      map[string]*templateHeatmapRow{},
@@ -125,66 +115,6 @@ func filesCoverageToTemplateData(fCov []*fileCoverageWithDetails) *templateHeatm
 	return &res
 }
 
-func filesCoverageWithDetailsStmt(ns, subsystem string, timePeriod coveragedb.TimePeriod) spanner.Statement {
-	stmt := spanner.Statement{
-		SQL: `
-select
-  dateto,
-  instrumented,
-  covered,
-  files.filepath,
-  subsystems
-from merge_history
-  join files
-    on merge_history.session = files.session
-  join file_subsystems
-    on merge_history.namespace = file_subsystems.namespace and files.filepath = file_subsystems.filepath
-where
-  merge_history.namespace=$1 and dateto=$2 and duration=$3`,
-		Params: map[string]interface{}{
-			"p1": ns,
-			"p2": timePeriod.DateTo,
-			"p3": timePeriod.Days,
-		},
-	}
-	if subsystem != "" {
-		stmt.SQL += " and $4=ANY(subsystems)"
-		stmt.Params["p4"] = subsystem
-	}
-	return stmt
-}
-
-func filesCoverageWithDetails(ctx context.Context, projectID, ns, subsystem string, timePeriods []coveragedb.TimePeriod,
-) ([]*fileCoverageWithDetails, error) {
-	client, err := coveragedb.NewClient(ctx, projectID)
-	if err != nil {
-		return nil, fmt.Errorf("spanner.NewClient() failed: %s", err.Error())
-	}
-	defer client.Close()
-
-	res := []*fileCoverageWithDetails{}
-	for _, timePeriod := range timePeriods {
-		stmt := filesCoverageWithDetailsStmt(ns, subsystem, timePeriod)
-		iter := client.Single().Query(ctx, stmt)
-		defer iter.Stop()
-		for {
-			row, err := iter.Next()
-			if err == iterator.Done {
-				break
-			}
-			if err != nil {
-				return nil, fmt.Errorf("failed to iter.Next() spanner DB: %w", err)
-			}
-			var r fileCoverageWithDetails
-			if err = row.ToStruct(&r); err != nil {
-				return nil, fmt.Errorf("failed to row.ToStruct() spanner DB: %w", err)
-			}
-			res = append(res, &r)
-		}
-	}
-	return res, nil
-}
-
 type StyleBodyJS struct {
 	Style template.CSS
 	Body  template.HTML
@@ -192,8 +122,8 @@ type StyleBodyJS struct {
 }
 
 // nolint: dupl
-func DoDirHeatMap(w io.Writer, projectID, ns string, periods []coveragedb.TimePeriod) error {
-	style, body, js, err := DoHeatMapStyleBodyJS(context.Background(), projectID, ns, "", periods)
+func DoDirHeatMap(w io.Writer, storage coveragedb.Storage, ns string, periods []coveragedb.TimePeriod) error {
+	style, body, js, err := DoHeatMapStyleBodyJS(context.Background(), storage, ns, "", periods)
 	if err != nil {
 		return fmt.Errorf("failed to DoHeatMapStyleBodyJS() %w", err)
 	}
@@ -205,8 +135,8 @@ func DoDirHeatMap(w io.Writer, projectID, ns string, periods []coveragedb.TimePe
 }
 
 // nolint: dupl
-func DoSubsystemsHeatMap(w io.Writer, projectID, ns string, periods []coveragedb.TimePeriod) error {
-	style, body, js, err := DoSubsystemsHeatMapStyleBodyJS(context.Background(), projectID, ns, "", periods)
+func DoSubsystemsHeatMap(w io.Writer, storage coveragedb.Storage, ns string, periods []coveragedb.TimePeriod) error {
+	style, body, js, err := DoSubsystemsHeatMapStyleBodyJS(context.Background(), storage, ns, "", periods)
 	if err != nil {
 		return fmt.Errorf("failed to DoSubsystemsHeatMapStyleBodyJS() %w", err)
 	}
@@ -234,26 +164,26 @@ func stylesBodyJSTemplate(templData *templateHeatmap,
 		template.HTML(js.Bytes()), nil
 }
 
-func DoHeatMapStyleBodyJS(ctx context.Context, projectID, ns, subsystem string, periods []coveragedb.TimePeriod,
-) (template.CSS, template.HTML, template.HTML, error) {
-	covAndDates, err := filesCoverageWithDetails(ctx, projectID, ns, subsystem, periods)
+func DoHeatMapStyleBodyJS(ctx context.Context, storage coveragedb.Storage, ns, subsystem string,
+	periods []coveragedb.TimePeriod) (template.CSS, template.HTML, template.HTML, error) {
+	covAndDates, err := storage.FilesCoverageWithDetails(ctx, ns, subsystem, periods)
 	if err != nil {
-		return "", "", "", fmt.Errorf("failed to filesCoverageWithDetails: %w", err)
+		return "", "", "", fmt.Errorf("failed to FilesCoverageWithDetails: %w", err)
 	}
 	templData := filesCoverageToTemplateData(covAndDates)
 	return stylesBodyJSTemplate(templData)
 }
 
-func DoSubsystemsHeatMapStyleBodyJS(ctx context.Context, projectID, ns, subsystem string,
+func DoSubsystemsHeatMapStyleBodyJS(ctx context.Context, storage coveragedb.Storage, ns, subsystem string,
 	periods []coveragedb.TimePeriod) (template.CSS, template.HTML, template.HTML, error) {
-	covWithDetails, err := filesCoverageWithDetails(ctx, projectID, ns, subsystem, periods)
+	covWithDetails, err := storage.FilesCoverageWithDetails(ctx, ns, subsystem, periods)
 	if err != nil {
-		panic(err)
+		return "", "", "", fmt.Errorf("failed to FilesCoverageWithDetails: %w", err)
 	}
-	var ssCovAndDates []*fileCoverageWithDetails
+	var ssCovAndDates []*coveragedb.FileCoverageWithDetails
 	for _, cwd := range covWithDetails {
 		for _, ssName := range cwd.Subsystems {
-			newRecord := fileCoverageWithDetails{
+			newRecord := coveragedb.FileCoverageWithDetails{
 				Filepath:     ssName + "/" + cwd.Filepath,
 				Instrumented: cwd.Instrumented,
 				Covered:      cwd.Covered,
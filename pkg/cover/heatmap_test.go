@@ -8,18 +8,19 @@ import (
 	"time"
 
 	"cloud.google.com/go/civil"
+	"github.com/google/syzkaller/pkg/coveragedb"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestFilesCoverageToTemplateData(t *testing.T) {
 	tests := []struct {
 		name  string
-		input []*fileCoverageWithDetails
+		input []*coveragedb.FileCoverageWithDetails
 		want  *templateHeatmap
 	}{
 		{
 			name:  "empty input",
-			input: []*fileCoverageWithDetails{},
+			input: []*coveragedb.FileCoverageWithDetails{},
 			want: &templateHeatmap{
 				Root: &templateHeatmapRow{
 					Items: []*templateHeatmapRow{},
@@ -28,7 +29,7 @@ func TestFilesCoverageToTemplateData(t *testing.T) {
 		},
 		{
 			name: "single file",
-			input: []*fileCoverageWithDetails{
+			input: []*coveragedb.FileCoverageWithDetails{
 				{
 					Filepath:     "file1",
 					Instrumented: 1,
@@ -65,7 +66,7 @@ func TestFilesCoverageToTemplateData(t *testing.T) {
 		},
 		{
 			name: "tree data",
-			input: []*fileCoverageWithDetails{
+			input: []*coveragedb.FileCoverageWithDetails{
 				{
 					Filepath:     "dir/file2",
 					Instrumented: 1,
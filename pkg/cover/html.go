@@ -22,6 +22,7 @@ import (
 
 	"github.com/google/syzkaller/pkg/cover/backend"
 	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/signal"
 )
 
 type HandlerParams struct {
@@ -29,6 +30,10 @@ type HandlerParams struct {
 	Filter map[uint64]struct{}
 	Debug  bool
 	Force  bool
+	// Signal is the edge signal of Progs, set if the coverage was collected with cover_edges.
+	// Then coverage of conditional branches is computed from the edges between coverage points:
+	// they are exported as branches in LCOV/Cobertura and the HTML report shows taken branches.
+	Signal signal.Signal
 }
 
 func (rg *ReportGenerator) DoHTML(w io.Writer, params HandlerParams) error {
@@ -37,6 +42,10 @@ func (rg *ReportGenerator) DoHTML(w io.Writer, params HandlerParams) error {
 	if err != nil {
 		return err
 	}
+	edges, err := rg.addBranches(files, progs, params.Signal)
+	if err != nil {
+		return err
+	}
 	d := &templateData{
 		Root:     new(templateDir),
 		RawCover: rg.rawCoverEnabled,
@@ -80,15 +89,19 @@ func (rg *ReportGenerator) DoHTML(w io.Writer, params HandlerParams) error {
 			},
 			HasFunctions: len(file.functions) != 0,
 		}
+		if edges {
+			f.Branches = file.totalBranches
+			f.CoveredBranches = file.coveredBranches
+		}
 		pos.Files = append(pos.Files, f)
 		if file.coveredPCs == 0 {
 			continue
 		}
-		addFunctionCoverage(file, d)
+		addFunctionCoverage(file, d, edges)
 		contents := ""
 		lines, err := parseFile(file.filename)
 		if err == nil {
-			contents = fileContents(file, lines, haveProgs)
+			contents = fileContents(file, lines, haveProgs, edges)
 			fileOpenErr = nil
 		} else {
 			// We ignore individual errors of opening/locating source files
@@ -636,7 +649,7 @@ func fixUpPCs(target string, progs []Prog, coverFilter map[uint64]struct{}) []Pr
 	return progs
 }
 
-func fileContents(file *file, lines [][]byte, haveProgs bool) string {
+func fileContents(file *file, lines [][]byte, haveProgs, edges bool) string {
 	var buf bytes.Buffer
 	lineCover := perLineCoverage(file.covered, file.uncovered)
 	htmlReplacer := strings.NewReplacer(">", "&gt;", "<", "&lt;", "&", "&amp;", "\t", "        ")
@@ -652,6 +665,13 @@ func fileContents(file *file, lines [][]byte, haveProgs bool) string {
 			buf.WriteByte('\n')
 		}
 	}
+	if edges {
+		buf.WriteString("</td><td class='branches'>")
+		for i := range lines {
+			buf.WriteString(lineBranches(file.branches[i+1]))
+			buf.WriteByte('\n')
+		}
+	}
 	buf.WriteString("</td><td>")
 	for i := range lines {
 		buf.WriteString(fmt.Sprintf("%d\n", i+1))
@@ -689,6 +709,46 @@ func fileContents(file *file, lines [][]byte, haveProgs bool) string {
 	return buf.String()
 }
 
+// lineBranches renders the number of taken branch outcomes of the line,
+// the tooltip lists all outcomes and the lines they lead to.
+func lineBranches(branches []lineBranch) string {
+	if len(branches) == 0 {
+		return ""
+	}
+	var title strings.Builder
+	taken, total, executed := 0, 0, false
+	for _, b := range branches {
+		executed = executed || b.executed
+		for i, outcome := range branchOutcomes {
+			total++
+			status := "not taken"
+			if b.taken[i] {
+				taken++
+				status = "taken"
+			}
+			fmt.Fprintf(&title, "0x%x %v", b.pc, outcome)
+			if len(b.lines[i]) != 0 {
+				var lines []string
+				for _, line := range b.lines[i] {
+					lines = append(lines, fmt.Sprint(line))
+				}
+				fmt.Fprintf(&title, " to line %v", strings.Join(lines, ", "))
+			}
+			fmt.Fprintf(&title, ": %v&#10;", status)
+		}
+	}
+	class := "both"
+	if !executed {
+		// The branches were not reached, they are neither taken nor not taken.
+		class = ""
+	} else if taken == 0 {
+		class = "uncovered"
+	} else if taken == total {
+		class = "covered"
+	}
+	return fmt.Sprintf("<span class='%v' title='%v'>%v/%v</span>", class, title.String(), taken, total)
+}
+
 type lineCoverChunk struct {
 	End       int
 	Covered   bool
@@ -764,7 +824,7 @@ func mergeLine(chunks []lineCoverChunk, start, end int, covered bool) []lineCove
 	return res
 }
 
-func addFunctionCoverage(file *file, data *templateData) {
+func addFunctionCoverage(file *file, data *templateData, edges bool) {
 	var buf bytes.Buffer
 	var coveredTotal int
 	var TotalInCoveredFunc int
@@ -780,7 +840,11 @@ func addFunctionCoverage(file *file, data *templateData) {
 		buf.WriteString(fmt.Sprintf("<span class='hover'>%v", function.name))
 		buf.WriteString(fmt.Sprintf("<span class='cover hover'>%v", percentage))
 		buf.WriteString(fmt.Sprintf("<span class='cover-right'>of %v", strconv.Itoa(function.pcs)))
-		buf.WriteString("</span></span></span><br>\n")
+		buf.WriteString("</span></span>")
+		if edges {
+			buf.WriteString(branchCoverage(function.coveredBranches, function.branches))
+		}
+		buf.WriteString("</span><br>\n")
 	}
 	buf.WriteString("-----------<br>\n")
 	buf.WriteString("<span class='hover'>SUMMARY")
@@ -792,10 +856,23 @@ func addFunctionCoverage(file *file, data *templateData) {
 	}
 	buf.WriteString(fmt.Sprintf("<span class='cover hover'>%v", percentInCoveredFunc))
 	buf.WriteString(fmt.Sprintf("<span class='cover-right'>of %v", strconv.Itoa(TotalInCoveredFunc)))
-	buf.WriteString("</span></span></span><br>\n")
+	buf.WriteString("</span></span>")
+	if edges {
+		buf.WriteString(branchCoverage(file.coveredBranches, file.totalBranches))
+	}
+	buf.WriteString("</span><br>\n")
 	data.Functions = append(data.Functions, template.HTML(buf.String()))
 }
 
+func branchCoverage(covered, total int) string {
+	percentage := "---"
+	if total != 0 {
+		percentage = fmt.Sprintf("%v%%", percent(covered, total))
+	}
+	return fmt.Sprintf("<span class='branch-cover hover'>branches %v<span class='cover-right'>of %v</span></span>",
+		percentage, total)
+}
+
 func processDir(dir *templateDir) {
 	for len(dir.Dirs) == 1 && len(dir.Files) == 0 {
 		for _, child := range dir.Dirs {
@@ -810,14 +887,20 @@ func processDir(dir *templateDir) {
 	for _, f := range dir.Files {
 		dir.Total += f.Total
 		dir.Covered += f.Covered
+		dir.Branches += f.Branches
+		dir.CoveredBranches += f.CoveredBranches
 		f.Percent = percent(f.Covered, f.Total)
+		f.BranchPercent = branchPercent(f.CoveredBranches, f.Branches)
 	}
 	for _, child := range dir.Dirs {
 		processDir(child)
 		dir.Total += child.Total
 		dir.Covered += child.Covered
+		dir.Branches += child.Branches
+		dir.CoveredBranches += child.CoveredBranches
 	}
 	dir.Percent = percent(dir.Covered, dir.Total)
+	dir.BranchPercent = branchPercent(dir.CoveredBranches, dir.Branches)
 	if dir.Covered == 0 {
 		dir.Dirs = nil
 		dir.Files = nil
@@ -832,6 +915,13 @@ func percent(covered, total int) int {
 	return int(f)
 }
 
+func branchPercent(covered, total int) int {
+	if total == 0 {
+		return 0
+	}
+	return percent(covered, total)
+}
+
 func parseFile(fn string) ([][]byte, error) {
 	data, err := os.ReadFile(fn)
 	if err != nil {
@@ -871,6 +961,10 @@ type templateBase struct {
 	Total   int
 	Covered int
 	Percent int
+	// Branch coverage, only if the coverage was collected with cover_edges.
+	Branches        int
+	CoveredBranches int
+	BranchPercent   int
 }
 
 type templateDir struct {
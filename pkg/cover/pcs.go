@@ -0,0 +1,77 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package cover
+
+import (
+	"fmt"
+	"sort"
+)
+
+// LinePCs returns coverage callback PCs attributed to the source line of the file.
+// Only functions compiled in the file are considered (e.g. code inlined into other files is not found).
+func (rg *ReportGenerator) LinePCs(file string, line int) ([]uint64, error) {
+	var symPCs []uint64
+	for _, sym := range rg.Symbols {
+		if sym.Unit.Name == file {
+			symPCs = append(symPCs, sym.PCs...)
+		}
+	}
+	if len(symPCs) == 0 {
+		return nil, fmt.Errorf("no coverage callbacks in %v", file)
+	}
+	if err := rg.symbolizePCs(symPCs); err != nil {
+		return nil, err
+	}
+	rg.indexLines()
+	pcs := make(map[uint64]bool)
+	for _, pc := range rg.linePCs[file][line] {
+		pcs[pc] = true
+	}
+	return sortedPCs(pcs), nil
+}
+
+// indexLines adds the frames symbolized since the last call to the line index.
+func (rg *ReportGenerator) indexLines() {
+	if rg.linePCs == nil {
+		rg.linePCs = make(map[string]map[int][]uint64)
+	}
+	for _, frame := range rg.Frames[rg.linesIndexed:] {
+		lines := rg.linePCs[frame.Name]
+		if lines == nil {
+			lines = make(map[int][]uint64)
+			rg.linePCs[frame.Name] = lines
+		}
+		lines[frame.StartLine] = append(lines[frame.StartLine], frame.PC)
+	}
+	rg.linesIndexed = len(rg.Frames)
+}
+
+// FunctionPCs returns coverage callback PCs of the function.
+// There may be several static functions with the same name, file restricts the search to one file.
+func (rg *ReportGenerator) FunctionPCs(file, function string) ([]uint64, error) {
+	pcs := make(map[uint64]bool)
+	for _, sym := range rg.Symbols {
+		if sym.Name != function || file != "" && sym.Unit.Name != file {
+			continue
+		}
+		for _, pc := range sym.PCs {
+			pcs[pc] = true
+		}
+	}
+	if len(pcs) == 0 {
+		return nil, fmt.Errorf("no coverage callbacks in function %v", function)
+	}
+	return sortedPCs(pcs), nil
+}
+
+func sortedPCs(pcs map[uint64]bool) []uint64 {
+	ret := make([]uint64, 0, len(pcs))
+	for pc := range pcs {
+		ret = append(ret, pc)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i] < ret[j]
+	})
+	return ret
+}
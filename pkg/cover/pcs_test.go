@@ -0,0 +1,86 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package cover
+
+import (
+	"testing"
+
+	"github.com/google/syzkaller/pkg/cover/backend"
+	"github.com/google/syzkaller/pkg/vminfo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSourcePCs(t *testing.T) {
+	unit := func(name string) *backend.CompileUnit {
+		return &backend.CompileUnit{ObjectUnit: backend.ObjectUnit{Name: name}}
+	}
+	socket, sock, slab := unit("net/socket.c"), unit("net/sock.c"), unit("mm/slab.c")
+	symbol := func(name string, unit *backend.CompileUnit, start uint64, pcs ...uint64) *backend.Symbol {
+		return &backend.Symbol{
+			ObjectUnit: backend.ObjectUnit{Name: name, PCs: pcs},
+			Unit:       unit,
+			Start:      start,
+			End:        start + 0x100,
+		}
+	}
+	lines := map[uint64]int{0x1001: 10, 0x1002: 10, 0x1003: 11, 0x1101: 20, 0x1201: 10}
+	rg := &ReportGenerator{
+		Impl: &backend.Impl{
+			Symbols: []*backend.Symbol{
+				symbol("sys_socket", socket, 0x1000, 0x1001, 0x1002, 0x1003),
+				symbol("helper", socket, 0x1100, 0x1101),
+				symbol("helper", sock, 0x1200, 0x1201),
+				symbol("kmalloc", slab, 0x1300, 0x1301),
+			},
+			Symbolize: func(pcs map[*vminfo.KernelModule][]uint64) ([]backend.Frame, error) {
+				var frames []backend.Frame
+				for _, modulePCs := range pcs {
+					for _, pc := range modulePCs {
+						name := "net/socket.c"
+						if pc >= 0x1200 {
+							name = "net/sock.c"
+						}
+						frames = append(frames, backend.Frame{
+							PC:    pc,
+							Name:  name,
+							Range: backend.Range{StartLine: lines[pc], EndLine: lines[pc]},
+						})
+					}
+				}
+				return frames, nil
+			},
+		},
+	}
+	pcs, err := rg.LinePCs("net/socket.c", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []uint64{0x1001, 0x1002}, pcs)
+	pcs, err = rg.LinePCs("net/socket.c", 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, pcs)
+	_, err = rg.LinePCs("fs/open.c", 10)
+	assert.Error(t, err)
+	// Lines of files symbolized later are indexed as well.
+	pcs, err = rg.LinePCs("net/sock.c", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []uint64{0x1201}, pcs)
+
+	pcs, err = rg.FunctionPCs("", "helper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []uint64{0x1101, 0x1201}, pcs)
+	pcs, err = rg.FunctionPCs("net/sock.c", "helper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []uint64{0x1201}, pcs)
+	_, err = rg.FunctionPCs("", "kfree")
+	assert.Error(t, err)
+}
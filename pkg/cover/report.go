@@ -20,6 +20,12 @@ type ReportGenerator struct {
 	buildDir        string
 	subsystem       []mgrconfig.Subsystem
 	rawCoverEnabled bool
+	vm              string
+	// Frames of branch PCs symbolized so far (nil if the branch has no frames).
+	branchFrameCache map[uint64][]backend.Frame
+	// Coverage callback PCs of source lines for LinePCs, the first linesIndexed Frames are indexed.
+	linePCs      map[string]map[int][]uint64
+	linesIndexed int
 	*backend.Impl
 }
 
@@ -50,6 +56,7 @@ func MakeReportGenerator(cfg *mgrconfig.Config, subsystem []mgrconfig.Subsystem,
 		buildDir:        cfg.KernelBuildSrc,
 		subsystem:       subsystem,
 		rawCoverEnabled: rawCover,
+		vm:              cfg.Type,
 		Impl:            impl,
 	}
 	return rg, nil
@@ -64,12 +71,19 @@ type file struct {
 	uncovered  []backend.Range
 	totalPCs   int
 	coveredPCs int
+	// Conditional branches on the lines, only if the coverage was collected with cover_edges.
+	branches        map[int][]lineBranch
+	totalBranches   int
+	coveredBranches int
 }
 
 type function struct {
-	name    string
-	pcs     int
-	covered int
+	name            string
+	symbol          *backend.Symbol
+	pcs             int
+	covered         int
+	branches        int
+	coveredBranches int
 }
 
 type line struct {
@@ -150,15 +164,16 @@ func (rg *ReportGenerator) prepareFileMap(progs []Prog, force, debug bool) (file
 	}
 	for _, s := range rg.Symbols {
 		fun := &function{
-			name: s.Name,
-			pcs:  len(s.PCs),
+			name:   s.Name,
+			symbol: s,
+			pcs:    len(s.PCs),
 		}
+		f := files[s.Unit.Name]
 		for _, pc := range s.PCs {
 			if progPCs[pc] != nil {
 				fun.covered++
 			}
 		}
-		f := files[s.Unit.Name]
 		f.functions = append(f.functions, fun)
 	}
 	for _, f := range files {
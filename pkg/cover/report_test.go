@@ -11,6 +11,7 @@ import (
 	"bytes"
 	"encoding/csv"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -178,6 +179,8 @@ func testReportGenerator(t *testing.T, target *targets.Target, test Test) {
 	}
 	checkCSVReport(t, reps.csv)
 	checkJSONLReport(t, reps.jsonl)
+	checkLCOVReport(t, reps.lcov)
+	checkCoberturaReport(t, reps.cobertura)
 }
 
 const kcovCode = `
@@ -292,9 +295,11 @@ func buildTestBinary(t *testing.T, target *targets.Target, test *Test, dir strin
 }
 
 type reports struct {
-	html  []byte
-	csv   []byte
-	jsonl []byte
+	html      []byte
+	csv       []byte
+	jsonl     []byte
+	lcov      []byte
+	cobertura []byte
 }
 
 func generateReport(t *testing.T, target *targets.Target, test *Test) (*reports, error) {
@@ -337,6 +342,15 @@ func generateReport(t *testing.T, target *targets.Target, test *Test) (*reports,
 	if !rg.PreciseCoverage && test.SkipIfKcovIsBroken {
 		t.Skip("coverage testing requested, but kcov is broken")
 	}
+	if rg.CallSites != nil {
+		calls, err := rg.CallSites()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(calls) == 0 {
+			t.Fatalf("found no call sites")
+		}
+	}
 	if test.AddCover {
 		var pcs []uint64
 		Inexact := false
@@ -406,10 +420,20 @@ func generateReport(t *testing.T, target *targets.Target, test *Test) (*reports,
 	if err := rg.DoCoverJSONL(jsonl, params); err != nil {
 		return nil, err
 	}
+	lcov := new(bytes.Buffer)
+	if err := rg.DoLCOV(lcov, params); err != nil {
+		return nil, err
+	}
+	cobertura := new(bytes.Buffer)
+	if err := rg.DoCobertura(cobertura, params); err != nil {
+		return nil, err
+	}
 	return &reports{
-		html:  html.Bytes(),
-		csv:   csv.Bytes(),
-		jsonl: jsonl.Bytes(),
+		html:      html.Bytes(),
+		csv:       csv.Bytes(),
+		jsonl:     jsonl.Bytes(),
+		lcov:      lcov.Bytes(),
+		cobertura: cobertura.Bytes(),
 	}, nil
 }
 
@@ -438,6 +462,37 @@ func checkCSVReport(t *testing.T, CSVReport []byte) {
 	}
 }
 
+func checkLCOVReport(t *testing.T, r []byte) {
+	report := string(r)
+	assert.Contains(t, report, "FN:1,main\n")
+	assert.Contains(t, report, "FNDA:1,main\n")
+	assert.Contains(t, report, "DA:1,1\n")
+	assert.Contains(t, report, "end_of_record\n")
+}
+
+func checkCoberturaReport(t *testing.T, r []byte) {
+	var cov coberturaCoverage
+	if err := xml.Unmarshal(r, &cov); err != nil {
+		t.Fatal(err)
+	}
+	assert.Positive(t, cov.LinesCovered)
+	var main *coberturaMethod
+	for _, pkg := range cov.Packages {
+		for _, class := range pkg.Classes {
+			for i := range class.Methods {
+				if class.Methods[i].Name == "main" {
+					main = &class.Methods[i]
+				}
+			}
+		}
+	}
+	if !assert.NotNil(t, main, "no main in the Cobertura report") {
+		return
+	}
+	assert.Equal(t, "1.0000", main.LineRate)
+	assert.Contains(t, main.Lines, coberturaLine{Number: 1, Hits: 1})
+}
+
 // nolint:lll
 func checkJSONLReport(t *testing.T, r []byte) {
 	compacted := new(bytes.Buffer)
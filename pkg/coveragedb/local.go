@@ -0,0 +1,173 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package coveragedb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/subsystem"
+)
+
+// localStorage keeps merged coverage in a directory, it does not need any cloud services.
+// Each merged period of a namespace is stored in a separate JSON file:
+//
+//	dir/namespace/2024-07-01_1.json
+//
+// where 2024-07-01 is the last date of the period and 1 is the number of days in the period.
+// Saving a period again replaces the previous data for the period.
+type localStorage struct {
+	dir string
+}
+
+type localMergeResult struct {
+	History *HistoryRecord
+	Files   []*localFileRecord
+}
+
+type localFileRecord struct {
+	FilePath     string
+	Instrumented int64
+	Covered      int64
+	Subsystems   []string `json:",omitempty"`
+}
+
+// NewLocalStorage returns Storage that keeps the data in the dir directory.
+func NewLocalStorage(dir string) Storage {
+	return &localStorage{dir: dir}
+}
+
+func (ls *localStorage) SaveMergeResult(ctx context.Context, covMap map[string]*Coverage,
+	template *HistoryRecord, totalRows int64, sss []*subsystem.Subsystem) error {
+	if err := checkNamespace(template.Namespace); err != nil {
+		return err
+	}
+	ssMatcher := subsystem.MakePathMatcher(sss)
+	ssCache := make(map[string][]string)
+	res := &localMergeResult{
+		History: &HistoryRecord{
+			Time:      time.Now(),
+			Namespace: template.Namespace,
+			Repo:      template.Repo,
+			Commit:    template.Commit,
+			Duration:  template.Duration,
+			DateTo:    template.DateTo,
+			TotalRows: totalRows,
+		},
+	}
+	for filePath, record := range covMap {
+		res.Files = append(res.Files, &localFileRecord{
+			FilePath:     filePath,
+			Instrumented: record.Instrumented,
+			Covered:      record.Covered,
+			Subsystems:   fileSubsystems(filePath, ssMatcher, ssCache),
+		})
+	}
+	sort.Slice(res.Files, func(i, j int) bool {
+		return res.Files[i].FilePath < res.Files[j].FilePath
+	})
+	data, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+	nsDir := filepath.Join(ls.dir, template.Namespace)
+	if err := osutil.MkdirAll(nsDir); err != nil {
+		return err
+	}
+	file := ls.periodFile(template.Namespace, TimePeriod{DateTo: template.DateTo, Days: int(template.Duration)})
+	// Write to a temp file first, so that readers never see partially written data.
+	tmpFile := file + ".tmp"
+	if err := osutil.WriteFile(tmpFile, data); err != nil {
+		return err
+	}
+	return osutil.Rename(tmpFile, file)
+}
+
+func (ls *localStorage) NsDataMerged(ctx context.Context, ns string) ([]TimePeriod, []int64, error) {
+	if err := checkNamespace(ns); err != nil {
+		return nil, nil, err
+	}
+	files, err := filepath.Glob(filepath.Join(ls.dir, ns, "*.json"))
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Strings(files)
+	var periods []TimePeriod
+	var totalRows []int64
+	for _, file := range files {
+		res, err := readLocalMergeResult(file)
+		if err != nil {
+			return nil, nil, err
+		}
+		periods = append(periods, TimePeriod{DateTo: res.History.DateTo, Days: int(res.History.Duration)})
+		totalRows = append(totalRows, res.History.TotalRows)
+	}
+	return periods, totalRows, nil
+}
+
+func (ls *localStorage) FilesCoverageWithDetails(ctx context.Context, ns, subsystem string,
+	timePeriods []TimePeriod) ([]*FileCoverageWithDetails, error) {
+	if err := checkNamespace(ns); err != nil {
+		return nil, err
+	}
+	res := []*FileCoverageWithDetails{}
+	for _, timePeriod := range timePeriods {
+		merged, err := readLocalMergeResult(ls.periodFile(ns, timePeriod))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range merged.Files {
+			if subsystem != "" && !slices.Contains(file.Subsystems, subsystem) {
+				continue
+			}
+			res = append(res, &FileCoverageWithDetails{
+				Filepath:     file.FilePath,
+				Instrumented: file.Instrumented,
+				Covered:      file.Covered,
+				Dateto:       timePeriod.DateTo,
+				Subsystems:   file.Subsystems,
+			})
+		}
+	}
+	return res, nil
+}
+
+// checkNamespace verifies that the namespace can be used as a directory name in the database directory
+// (namespaces come from user input, so they must not refer to files outside of the directory).
+func checkNamespace(ns string) error {
+	if ns == "" || ns == "." || ns == ".." || strings.ContainsAny(ns, `/\`) {
+		return fmt.Errorf("bad namespace %q", ns)
+	}
+	return nil
+}
+
+func (ls *localStorage) periodFile(ns string, period TimePeriod) string {
+	return filepath.Join(ls.dir, ns, fmt.Sprintf("%v_%v.json", period.DateTo, period.Days))
+}
+
+func readLocalMergeResult(file string) (*localMergeResult, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	res := new(localMergeResult)
+	if err := json.Unmarshal(data, res); err != nil {
+		return nil, fmt.Errorf("failed to parse %v: %w", file, err)
+	}
+	if res.History == nil {
+		return nil, fmt.Errorf("no merge history in %v", file)
+	}
+	return res, nil
+}
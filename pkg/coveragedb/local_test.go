@@ -0,0 +1,89 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package coveragedb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"github.com/google/syzkaller/pkg/subsystem"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalStorage(t *testing.T) {
+	ctx := context.Background()
+	storage := NewLocalStorage(t.TempDir())
+	day1 := civil.Date{Year: 2024, Month: time.July, Day: 1}
+	day2 := day1.AddDays(1)
+	sss := []*subsystem.Subsystem{
+		{Name: "net", PathRules: []subsystem.PathRule{{IncludeRegexp: "^net/"}}},
+	}
+	save := func(date civil.Date, totalRows int64, covMap map[string]*Coverage) {
+		err := storage.SaveMergeResult(ctx, covMap, &HistoryRecord{
+			Namespace: "upstream",
+			Duration:  1,
+			DateTo:    date,
+		}, totalRows, sss)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	save(day1, 10, map[string]*Coverage{
+		"net/socket.c": {Instrumented: 10, Covered: 1},
+	})
+	// The second save of the same period replaces the data.
+	save(day1, 20, map[string]*Coverage{
+		"net/socket.c": {Instrumented: 10, Covered: 2},
+		"mm/slab.c":    {Instrumented: 5, Covered: 5},
+	})
+	save(day2, 30, map[string]*Coverage{
+		"net/socket.c": {Instrumented: 10, Covered: 3},
+	})
+
+	periods, totalRows, err := storage.NsDataMerged(ctx, "upstream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []TimePeriod{{DateTo: day1, Days: 1}, {DateTo: day2, Days: 1}}, periods)
+	assert.Equal(t, []int64{20, 30}, totalRows)
+
+	periods, _, err = storage.NsDataMerged(ctx, "other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, periods)
+
+	query := []TimePeriod{
+		{DateTo: day1, Days: 1},
+		{DateTo: day2, Days: 1},
+		{DateTo: day2.AddDays(1), Days: 1},
+	}
+	files, err := storage.FilesCoverageWithDetails(ctx, "upstream", "", query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []*FileCoverageWithDetails{
+		{Filepath: "mm/slab.c", Instrumented: 5, Covered: 5, Dateto: day1},
+		{Filepath: "net/socket.c", Instrumented: 10, Covered: 2, Dateto: day1, Subsystems: []string{"net"}},
+		{Filepath: "net/socket.c", Instrumented: 10, Covered: 3, Dateto: day2, Subsystems: []string{"net"}},
+	}, files)
+
+	files, err = storage.FilesCoverageWithDetails(ctx, "upstream", "net", query[:1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []*FileCoverageWithDetails{
+		{Filepath: "net/socket.c", Instrumented: 10, Covered: 2, Dateto: day1, Subsystems: []string{"net"}},
+	}, files)
+
+	assert.Error(t, storage.SaveMergeResult(ctx, nil, &HistoryRecord{Namespace: "../x"}, 0, nil))
+	for _, ns := range []string{"", ".", "..", "../upstream", "upstream/../upstream"} {
+		_, _, err = storage.NsDataMerged(ctx, ns)
+		assert.Error(t, err, ns)
+		_, err = storage.FilesCoverageWithDetails(ctx, ns, "", query)
+		assert.Error(t, err, ns)
+	}
+}
@@ -45,6 +45,84 @@ func NewClient(ctx context.Context, projectID string) (*spanner.Client, error) {
 	return spanner.NewClient(ctx, database)
 }
 
+type spannerStorage struct {
+	projectID string
+}
+
+// NewSpannerStorage returns Storage backed by the syzbot coverage Spanner database of the project.
+func NewSpannerStorage(projectID string) Storage {
+	return &spannerStorage{projectID: projectID}
+}
+
+func (ss *spannerStorage) SaveMergeResult(ctx context.Context, covMap map[string]*Coverage,
+	template *HistoryRecord, totalRows int64, sss []*subsystem.Subsystem) error {
+	return SaveMergeResult(ctx, ss.projectID, covMap, template, totalRows, sss)
+}
+
+func (ss *spannerStorage) NsDataMerged(ctx context.Context, ns string) ([]TimePeriod, []int64, error) {
+	return NsDataMerged(ctx, ss.projectID, ns)
+}
+
+func (ss *spannerStorage) FilesCoverageWithDetails(ctx context.Context, ns, subsystem string,
+	timePeriods []TimePeriod) ([]*FileCoverageWithDetails, error) {
+	client, err := NewClient(ctx, ss.projectID)
+	if err != nil {
+		return nil, fmt.Errorf("spanner.NewClient() failed: %s", err.Error())
+	}
+	defer client.Close()
+
+	res := []*FileCoverageWithDetails{}
+	for _, timePeriod := range timePeriods {
+		stmt := filesCoverageWithDetailsStmt(ns, subsystem, timePeriod)
+		iter := client.Single().Query(ctx, stmt)
+		defer iter.Stop()
+		for {
+			row, err := iter.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to iter.Next() spanner DB: %w", err)
+			}
+			var r FileCoverageWithDetails
+			if err = row.ToStruct(&r); err != nil {
+				return nil, fmt.Errorf("failed to row.ToStruct() spanner DB: %w", err)
+			}
+			res = append(res, &r)
+		}
+	}
+	return res, nil
+}
+
+func filesCoverageWithDetailsStmt(ns, subsystem string, timePeriod TimePeriod) spanner.Statement {
+	stmt := spanner.Statement{
+		SQL: `
+select
+  dateto,
+  instrumented,
+  covered,
+  files.filepath,
+  subsystems
+from merge_history
+  join files
+    on merge_history.session = files.session
+  join file_subsystems
+    on merge_history.namespace = file_subsystems.namespace and files.filepath = file_subsystems.filepath
+where
+  merge_history.namespace=$1 and dateto=$2 and duration=$3`,
+		Params: map[string]interface{}{
+			"p1": ns,
+			"p2": timePeriod.DateTo,
+			"p3": timePeriod.Days,
+		},
+	}
+	if subsystem != "" {
+		stmt.SQL += " and $4=ANY(subsystems)"
+		stmt.Params["p4"] = subsystem
+	}
+	return stmt
+}
+
 type Coverage struct {
 	Instrumented int64
 	Covered      int64
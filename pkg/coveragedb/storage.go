@@ -0,0 +1,33 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package coveragedb
+
+import (
+	"context"
+
+	"cloud.google.com/go/civil"
+	"github.com/google/syzkaller/pkg/subsystem"
+)
+
+// Storage keeps merged per-file coverage for namespaces and time periods.
+// It's implemented by the Spanner database used by syzbot and by a local on-disk database.
+type Storage interface {
+	// SaveMergeResult saves per-file coverage merged for the period described by template.
+	SaveMergeResult(ctx context.Context, covMap map[string]*Coverage, template *HistoryRecord,
+		totalRows int64, sss []*subsystem.Subsystem) error
+	// NsDataMerged returns the periods merged for the namespace and the number of source rows of each period.
+	NsDataMerged(ctx context.Context, ns string) ([]TimePeriod, []int64, error)
+	// FilesCoverageWithDetails returns per-file coverage of the namespace for the periods.
+	// If subsystem is not empty, only files of the subsystem are returned.
+	FilesCoverageWithDetails(ctx context.Context, ns, subsystem string, timePeriods []TimePeriod,
+	) ([]*FileCoverageWithDetails, error)
+}
+
+type FileCoverageWithDetails struct {
+	Filepath     string
+	Instrumented int64
+	Covered      int64
+	Dateto       civil.Date
+	Subsystems   []string
+}
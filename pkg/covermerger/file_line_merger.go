@@ -32,7 +32,7 @@ func makeFileLineCoverMerger(
 		a.MergeResult.LineDetails = make(map[int][]*FileRecord)
 	}
 	for repoBranch, fv := range fvs {
-		a.matchers[repoBranch] = makeLineToLineMatcher(fv, baseFile)
+		a.matchers[repoBranch] = MakeLineToLineMatcher(fv, baseFile)
 	}
 	return a
 }
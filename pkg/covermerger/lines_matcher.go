@@ -9,7 +9,9 @@ import (
 	dmp "github.com/sergi/go-diff/diffmatchpatch"
 )
 
-func makeLineToLineMatcher(textFrom, textTo string) *LineToLineMatcher {
+// MakeLineToLineMatcher matches lines of textFrom to the same lines in textTo (the text may be changed
+// between the versions, e.g. lines inserted or removed). Lines are 0-based.
+func MakeLineToLineMatcher(textFrom, textTo string) *LineToLineMatcher {
 	diffMatcher := dmp.New()
 	diffMatcher.DiffTimeout = 0
 	diffs := diffMatcher.DiffMain(textFrom, textTo, false)
@@ -43,6 +45,7 @@ type LineToLineMatcher struct {
 	lineToLine []int
 }
 
+// SameLinePos returns the position of the line in textTo, or -1 if the line was changed or removed.
 func (lm *LineToLineMatcher) SameLinePos(line int) int {
 	return lm.lineToLine[line]
 }
@@ -26,8 +26,12 @@ const (
 	sandboxAndroid   = "android"
 )
 
-func createCommonHeader(p, mmapProg *prog.Prog, replacements map[string]string, opts Options) ([]byte, error) {
+func createCommonHeader(p, mmapProg *prog.Prog, replacements map[string]string, opts Options,
+	kselftest bool) ([]byte, error) {
 	defines := defineList(p, mmapProg, opts)
+	if kselftest {
+		defines = append(defines, "SYZ_KSELFTEST")
+	}
 	sysTarget := targets.Get(p.Target.OS, p.Target.Arch)
 	// Note: -fdirectives-only isn't supported by clang. This code is relevant
 	// for producing C++ reproducers. Hence reproducers don't work when setting
@@ -39,6 +39,10 @@ import (
 
 // Write generates C source for program p based on the provided options opt.
 func Write(p *prog.Prog, opts Options) ([]byte, error) {
+	return write(p, opts, nil)
+}
+
+func write(p *prog.Prog, opts Options, kselftest *Kselftest) ([]byte, error) {
 	if err := opts.Check(p.Target.OS); err != nil {
 		return nil, fmt.Errorf("csource: invalid opts: %w", err)
 	}
@@ -48,6 +52,7 @@ func Write(p *prog.Prog, opts Options) ([]byte, error) {
 		target:    p.Target,
 		sysTarget: targets.Get(p.Target.OS, p.Target.Arch),
 		calls:     make(map[string]uint64),
+		kselftest: kselftest,
 	}
 	return ctx.generateSource()
 }
@@ -58,6 +63,8 @@ type context struct {
 	target    *prog.Target
 	sysTarget *targets.Target
 	calls     map[string]uint64 // CallName -> NR
+	kselftest *Kselftest
+	readable  readableTypes
 }
 
 func generateSandboxFunctionSignature(sandboxName string, sandboxArg int) string {
@@ -116,6 +123,7 @@ func (ctx *context) generateSource() ([]byte, error) {
 		"MMAP_DATA":       strings.Join(mmapCalls, ""),
 		"SYSCALL_DEFINES": ctx.generateSyscallDefines(),
 		"SANDBOX_FUNC":    sandboxFunc,
+		"TYPES":           ctx.readable.String(),
 		"RESULTS":         varsBuf.String(),
 		"SYSCALLS":        ctx.generateSyscalls(calls, len(vars) != 0),
 	}
@@ -134,6 +142,12 @@ func (ctx *context) generateSource() ([]byte, error) {
 		}
 	}
 	replacements["CALL_TIMEOUT_MS"] = timeoutExpr
+	if test := ctx.kselftest; test != nil {
+		replacements["KSELFTEST_TITLE"] = cStringLiteral(test.Title)
+		replacements["KSELFTEST_MATCH"] = cStringList(test.Match)
+		replacements["KSELFTEST_SUPPRESSIONS"] = cStringList(test.Suppressions)
+		replacements["KSELFTEST_TIMEOUT_MS"] = fmt.Sprint(int(test.Timeout / time.Millisecond))
+	}
 	if ctx.p.RequiredFeatures().Async {
 		conditions := []string{}
 		for idx, call := range ctx.p.Calls {
@@ -145,7 +159,7 @@ func (ctx *context) generateSource() ([]byte, error) {
 		replacements["ASYNC_CONDITIONS"] = strings.Join(conditions, " || ")
 	}
 
-	result, err := createCommonHeader(ctx.p, mmapProg, replacements, ctx.opts)
+	result, err := createCommonHeader(ctx.p, mmapProg, replacements, ctx.opts, ctx.kselftest != nil)
 	if err != nil {
 		return nil, err
 	}
@@ -245,17 +259,25 @@ func (ctx *context) generateProgCalls(p *prog.Prog, trace bool) ([]string, []uin
 	if err != nil {
 		return nil, nil, err
 	}
-	calls, vars := ctx.generateCalls(decoded, trace)
+	calls, vars := ctx.generateCalls(decoded, p, trace)
 	return calls, vars, nil
 }
 
-func (ctx *context) generateCalls(p prog.ExecProg, trace bool) ([]string, []uint64) {
+func (ctx *context) generateCalls(p prog.ExecProg, origProg *prog.Prog, trace bool) ([]string, []uint64) {
 	var calls []string
 	csumSeq := 0
 	for ci, call := range p.Calls {
 		w := new(bytes.Buffer)
+		rc := new(readableCall)
+		if ctx.opts.Readable {
+			rc = ctx.readableCall(origProg.Calls[ci], call.Copyin)
+		}
 		// Copyin.
-		for _, copyin := range call.Copyin {
+		for i, copyin := range call.Copyin {
+			w.WriteString(rc.blocks[i])
+			if rc.replaced[i] {
+				continue
+			}
 			ctx.copyin(w, &csumSeq, copyin)
 		}
 
@@ -266,12 +288,12 @@ func (ctx *context) generateCalls(p prog.ExecProg, trace bool) ([]string, []uint
 		resCopyout := call.Index != prog.ExecNoCopyout
 		argCopyout := len(call.Copyout) != 0
 
-		ctx.emitCall(w, call, ci, resCopyout || argCopyout, trace)
+		ctx.emitCall(w, call, rc, ci, resCopyout || argCopyout, trace)
 
 		if call.Props.Rerun > 0 {
 			fmt.Fprintf(w, "\tfor (int i = 0; i < %v; i++) {\n", call.Props.Rerun)
 			// Rerun invocations should not affect the result value.
-			ctx.emitCall(w, call, ci, false, false)
+			ctx.emitCall(w, call, rc, ci, false, false)
 			fmt.Fprintf(w, "\t}\n")
 		}
 		// Copyout.
@@ -288,7 +310,7 @@ func isNative(sysTarget *targets.Target, callName string) bool {
 	return sysTarget.HasCallNumber(callName) && !trampoline
 }
 
-func (ctx *context) emitCall(w *bytes.Buffer, call prog.ExecCall, ci int, haveCopyout, trace bool) {
+func (ctx *context) emitCall(w *bytes.Buffer, call prog.ExecCall, rc *readableCall, ci int, haveCopyout, trace bool) {
 	native := isNative(ctx.sysTarget, call.Meta.CallName)
 	fmt.Fprintf(w, "\t")
 	if !native {
@@ -306,7 +328,7 @@ func (ctx *context) emitCall(w *bytes.Buffer, call prog.ExecCall, ci int, haveCo
 	if haveCopyout || trace {
 		fmt.Fprintf(w, "res = ")
 	}
-	w.WriteString(ctx.fmtCallBody(call))
+	w.WriteString(ctx.fmtCallBody(call, rc))
 	if !native {
 		fmt.Fprintf(w, ")") // close NONFAILING macro
 	}
@@ -327,7 +349,7 @@ func (ctx *context) emitCall(w *bytes.Buffer, call prog.ExecCall, ci int, haveCo
 	}
 }
 
-func (ctx *context) fmtCallBody(call prog.ExecCall) string {
+func (ctx *context) fmtCallBody(call prog.ExecCall, rc *readableCall) string {
 	native := isNative(ctx.sysTarget, call.Meta.CallName)
 	callName, ok := ctx.sysTarget.SyscallTrampolines[call.Meta.CallName]
 	if !ok {
@@ -354,6 +376,10 @@ func (ctx *context) fmtCallBody(call prog.ExecCall) string {
 				panic("string format in syscall argument")
 			}
 			com := ctx.argComment(call.Meta.Args[i], arg)
+			if val := rc.args[i]; val != "" {
+				argsStrs = append(argsStrs, com+val)
+				continue
+			}
 			suf := ctx.literalSuffix(arg, native)
 			argsStrs = append(argsStrs, com+handleBigEndian(arg, ctx.constArgToStr(arg, suf)))
 		case prog.ExecArgResult:
@@ -0,0 +1,97 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package csource
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+)
+
+// Kselftest describes a kselftest-style regression test generated from a reproducer.
+type Kselftest struct {
+	// Title of the bug the test checks for (printed in the TAP output).
+	Title string
+	// The test fails if a kernel log message printed during the test contains all of these strings
+	// and does not match any of Suppressions (e.g. report.ExtractCrashSignature of the bug report).
+	Match []string
+	// POSIX extended regular expressions of kernel log messages that are not crashes.
+	Suppressions []string
+	// Number of times the program is executed.
+	Iterations int
+	// The test kills the program if it does not finish within the timeout.
+	Timeout time.Duration
+}
+
+// WriteKselftest generates a kselftest-style regression test for program p.
+// Instead of repeating the program forever, the test executes it test.Iterations times
+// within test.Timeout, checks /dev/kmsg for the crash signature, and reports PASS/FAIL
+// in the TAP format. Sandboxing and multiple procs are not used: kselftests run
+// as root in a dedicated machine. Sandbox=none is still used if the options require
+// setup performed by the sandbox (e.g. network devices). Leak checking is not supported.
+func WriteKselftest(p *prog.Prog, opts Options, test Kselftest) ([]byte, error) {
+	if p.Target.OS != targets.Linux {
+		return nil, fmt.Errorf("kselftests are not supported on %v", p.Target.OS)
+	}
+	if test.Iterations < 1 {
+		return nil, errors.New("kselftest: iterations must be positive")
+	}
+	if test.Timeout <= 0 {
+		return nil, errors.New("kselftest: timeout must be positive")
+	}
+	if len(test.Match) == 0 {
+		return nil, errors.New("kselftest: no crash signature")
+	}
+	if test.Title == "" {
+		test.Title = "syzkaller reproducer"
+	}
+	opts.Repeat = test.Iterations > 1
+	opts.RepeatTimes = 0
+	if opts.Repeat {
+		opts.RepeatTimes = test.Iterations
+	}
+	opts.Procs = 1
+	opts.Sandbox = ""
+	opts.SandboxArg = 0
+	if opts.NetInjection || opts.NetDevices || opts.Cgroups || opts.BinfmtMisc ||
+		opts.VhciInjection || opts.Wifi {
+		opts.Sandbox = sandboxNone
+	}
+	opts.NetReset = false
+	opts.Leak = false
+	opts.Trace = false
+	return write(p, opts, &test)
+}
+
+// cStringList returns a NULL-terminated C array initializer with the strings.
+func cStringList(list []string) string {
+	var ret []string
+	for _, s := range list {
+		ret = append(ret, cStringLiteral(s))
+	}
+	return strings.Join(append(ret, "NULL"), ", ")
+}
+
+// cStringLiteral returns s as a C string literal.
+func cStringLiteral(s string) string {
+	buf := new(strings.Builder)
+	buf.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"' || c == '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case c < 0x20 || c >= 0x7f:
+			fmt.Fprintf(buf, "\\%03o", c)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	buf.WriteByte('"')
+	return buf.String()
+}
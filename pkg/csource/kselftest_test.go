@@ -0,0 +1,71 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package csource
+
+import (
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKselftest(t *testing.T) {
+	if runtime.GOOS != targets.Linux {
+		t.Skipf("kselftests can only be built on linux")
+	}
+	target, err := prog.GetTarget(targets.Linux, targets.AMD64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := targets.Get(target.OS, target.Arch).BrokenCompiler; err != "" {
+		t.Skipf("target compiler is broken: %v", err)
+	}
+	p, err := target.Deserialize([]byte(`
+r0 = openat(0xffffffffffffff9c, &(0x7f0000000000)='./file0\x00', 0x42, 0x0)
+close(r0)
+`), prog.Strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := Options{
+		Threaded:  true,
+		Repeat:    true,
+		Procs:     4,
+		Slowdown:  1,
+		Sandbox:   sandboxNamespace,
+		UseTmpDir: true,
+		Leak:      true,
+	}
+	test := Kselftest{
+		Title:        "KASAN: use-after-free Read in \"foo\"\n",
+		Match:        []string{"BUG:", "KASAN:", "in", "foo"},
+		Suppressions: []string{"Killed process .* \\(syz-executor\\)"},
+		Iterations:   3,
+		Timeout:      20 * time.Second,
+	}
+	src, err := WriteKselftest(p, opts, test)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Contains(t, string(src), `printf("TAP version 13\n1..1\n");`)
+	assert.Contains(t, string(src), `"KASAN: use-after-free Read in \"foo\"\012"`)
+	assert.Contains(t, string(src), `{"BUG:", "KASAN:", "in", "foo", NULL}`)
+	assert.Contains(t, string(src), `{"Killed process .* \\(syz-executor\\)", NULL}`)
+	assert.Contains(t, string(src), `< 20000`)
+	assert.Contains(t, string(src), `iter < 3;`)
+	assert.NotContains(t, string(src), "do_sandbox_namespace")
+	bin, err := Build(target, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(bin)
+
+	test.Match = nil
+	_, err = WriteKselftest(p, opts, test)
+	assert.Error(t, err)
+}
@@ -49,6 +49,10 @@ type Options struct {
 	UseTmpDir  bool `json:"tmpdir,omitempty"`
 	HandleSegv bool `json:"segv,omitempty"`
 
+	// Readable replaces raw copyin stores of struct pointees with declared C structs
+	// initialized with designated initializers, named flags and sizeof-based lengths.
+	Readable bool `json:"readable,omitempty"`
+
 	Trace bool `json:"trace,omitempty"`
 	LegacyOptions
 }
@@ -0,0 +1,530 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package csource
+
+// This file implements Options.Readable.
+// Pointees that are syzlang structs are stored with a single memcpy of a local variable
+// of a declared C struct type instead of a sequence of raw copyin stores, e.g.:
+//
+//	{
+//		struct syz_epoll_event ev = {
+//			.ev = EPOLLET|POLLIN,
+//			.data = 4,
+//		};
+//		NONFAILING(memcpy((void*)0x20000080, &ev, sizeof(ev)));
+//	}
+//
+// The C structs are packed and mirror the syzlang layout including padding fields.
+// Unions are declared as byte arrays and their options are stored with raw copyins
+// after the struct. Structs that can't be represented (varlen structs, bitfields, string formats, etc)
+// fall back to raw copyins. Padding and output fields of the represented structs are zeroed,
+// while raw copyins leave them untouched.
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/syzkaller/prog"
+)
+
+// Structs are stored in local variables and threads of the generated programs have small stacks.
+const maxReadableStructSize = 1 << 10
+
+// readableTypes collects C declarations required by the readable copyins.
+type readableTypes struct {
+	structs map[string]string // syzlang struct name -> C struct tag ("" if it can't be represented)
+	tags    map[string]bool
+	decls   []string // struct declarations in dependency order
+	consts  map[string]uint64
+}
+
+// readableCall describes how copyins and arguments of a single call are replaced.
+type readableCall struct {
+	blocks   map[int]string // copyin index -> code emitted instead of the copyin
+	replaced map[int]bool   // copyins stored as part of a struct
+	args     map[int]string // syscall argument index -> expression
+}
+
+// String returns the flag constants and struct declarations.
+func (rt *readableTypes) String() string {
+	buf := new(bytes.Buffer)
+	var consts []string
+	for name := range rt.consts {
+		consts = append(consts, name)
+	}
+	sort.Strings(consts)
+	for _, name := range consts {
+		fmt.Fprintf(buf, "#ifndef %v\n#define %v 0x%x\n#endif\n", name, name, rt.consts[name])
+	}
+	for _, decl := range rt.decls {
+		fmt.Fprintf(buf, "\n%v", decl)
+	}
+	return buf.String()
+}
+
+func (ctx *context) readableCall(call *prog.Call, copyins []prog.ExecCopyin) *readableCall {
+	rc := &readableCall{
+		blocks:   make(map[int]string),
+		replaced: make(map[int]bool),
+		args:     make(map[int]string),
+	}
+	prog.ForeachArg(call, func(arg prog.Arg, argCtx *prog.ArgCtx) {
+		ptr, ok := arg.(*prog.PointerArg)
+		if !ok || ptr.Res == nil || ptr.Res.Dir() == prog.DirOut {
+			return
+		}
+		group, ok := ptr.Res.(*prog.GroupArg)
+		if !ok {
+			return
+		}
+		typ, ok := group.Type().(*prog.StructType)
+		if !ok {
+			return
+		}
+		tag := ctx.readableStruct(typ)
+		if tag == "" {
+			return
+		}
+		addr := ctx.target.PhysicalAddr(ptr)
+		m := &copyinMatcher{
+			copyins:  copyins,
+			replaced: rc.replaced,
+			used:     make(map[int]bool),
+		}
+		fields, ok := ctx.readableFields(group, addr, m)
+		if !ok || len(m.used) == 0 {
+			return
+		}
+		// The struct is stored before the first copyin to it, in particular before raw union copyins.
+		first, ok := m.first(addr, group.Size())
+		if !ok {
+			return
+		}
+		for idx := range m.used {
+			rc.replaced[idx] = true
+		}
+		name := "v"
+		if argCtx.Field != nil {
+			name = cIdent(argCtx.Field.Name)
+		}
+		if localReserved[name] {
+			name += "_"
+		}
+		w := new(bytes.Buffer)
+		if len(fields) == 0 {
+			fmt.Fprintf(w, "\tNONFAILING(memset((void*)0x%x, 0, sizeof(struct %v)));\n", addr, tag)
+		} else {
+			fmt.Fprintf(w, "\t{\n\t\tstruct %v %v = {\n", tag, name)
+			for _, field := range fields {
+				fmt.Fprintf(w, "\t\t\t%v,\n", field)
+			}
+			fmt.Fprintf(w, "\t\t};\n\t\tNONFAILING(memcpy((void*)0x%x, &%v, sizeof(%v)));\n\t}\n", addr, name, name)
+		}
+		rc.blocks[first] = w.String()
+	})
+	for i, arg := range call.Args {
+		if arg.Size() != ctx.target.PtrSize {
+			// The expression is passed to variadic syscall() and must have the right size.
+			continue
+		}
+		if val := ctx.readableSizeof(arg, call.Meta.Args, call.Args); val != "" {
+			rc.args[i] = val
+		}
+	}
+	return rc
+}
+
+// readableFields returns designated initializers of non-zero fields of the struct stored at addr.
+func (ctx *context) readableFields(group *prog.GroupArg, addr uint64, m *copyinMatcher) ([]string, bool) {
+	typ := group.Type().(*prog.StructType)
+	var fields []string
+	offset := uint64(0)
+	for i, arg := range group.Inner {
+		if !prog.IsPad(arg.Type()) {
+			val, ok := ctx.readableValue(arg, group, addr+offset, m)
+			if !ok {
+				return nil, false
+			}
+			if val != "" {
+				fields = append(fields, fmt.Sprintf(".%v = %v", cFieldName(typ, i), val))
+			}
+		}
+		offset += arg.Size()
+	}
+	return fields, true
+}
+
+// readableValue returns initializer of arg stored at addr, or "" if the value is zero.
+func (ctx *context) readableValue(arg prog.Arg, parent *prog.GroupArg, addr uint64, m *copyinMatcher) (string, bool) {
+	switch a := arg.(type) {
+	case *prog.GroupArg:
+		var elems []string
+		switch a.Type().(type) {
+		case *prog.StructType:
+			fields, ok := ctx.readableFields(a, addr, m)
+			if !ok {
+				return "", false
+			}
+			elems = fields
+		case *prog.ArrayType:
+			offset := uint64(0)
+			for i, elem := range a.Inner {
+				val, ok := ctx.readableValue(elem, a, addr+offset, m)
+				if !ok {
+					return "", false
+				}
+				if val != "" {
+					elems = append(elems, fmt.Sprintf("[%v] = %v", i, val))
+				}
+				offset += elem.Size()
+			}
+		default:
+			return "", false
+		}
+		if len(elems) == 0 {
+			return "", true
+		}
+		return "{" + strings.Join(elems, ", ") + "}", true
+	case *prog.UnionArg:
+		m.raw = append(m.raw, [2]uint64{addr, addr + a.Size()})
+		return "", true
+	case *prog.DataArg:
+		if a.Dir() == prog.DirOut {
+			return "", true
+		}
+		copyin, ok := m.find(addr)
+		if !ok {
+			return "", false
+		}
+		data, ok := copyin.(prog.ExecArgData)
+		if !ok || uint64(len(data.Data)) != a.Size() {
+			return "", false
+		}
+		if bytes.Equal(data.Data, make([]byte, len(data.Data))) {
+			return "", true
+		}
+		if data.Readable {
+			return fmt.Sprintf("\"%s\"", toCString(data.Data, true)), true
+		}
+		var elems []string
+		for _, v := range data.Data {
+			elems = append(elems, fmt.Sprintf("0x%x", v))
+		}
+		return "{" + strings.Join(elems, ", ") + "}", true
+	case *prog.ConstArg, *prog.ResultArg, *prog.PointerArg:
+		if a.Dir() == prog.DirOut {
+			return "", true
+		}
+		copyin, ok := m.find(addr)
+		if !ok {
+			return "", false
+		}
+		switch v := copyin.(type) {
+		case prog.ExecArgConst:
+			if v.BitfieldLength != 0 || v.Size != arg.Size() ||
+				v.Format != prog.FormatNative && v.Format != prog.FormatBigEndian {
+				return "", false
+			}
+			return ctx.readableConst(arg, parent, v), true
+		case prog.ExecArgResult:
+			if v.Size != arg.Size() || v.Format != prog.FormatNative && v.Format != prog.FormatBigEndian {
+				return "", false
+			}
+			return ctx.resultArgToStr(v), true
+		}
+	}
+	return "", false
+}
+
+func (ctx *context) readableConst(arg prog.Arg, parent *prog.GroupArg, v prog.ExecArgConst) string {
+	mask := (uint64(1) << (v.Size * 8)) - 1
+	val := v.Value & mask
+	if val == 0 && (v.PidStride == 0 || ctx.opts.Procs <= 1) {
+		return ""
+	}
+	if v.PidStride == 0 {
+		expr := ""
+		switch typ := arg.Type().(type) {
+		case *prog.FlagsType:
+			expr = ctx.readableFlags(typ, val)
+		case *prog.LenType:
+			if structType, ok := parent.Type().(*prog.StructType); ok {
+				expr = ctx.readableSizeof(arg, structType.Fields, parent.Inner)
+			}
+		}
+		if expr != "" {
+			return handleBigEndian(v, expr)
+		}
+	}
+	return handleBigEndian(v, ctx.constArgToStr(v, ""))
+}
+
+// readableFlags returns value of a flags arg as a combination of named flags.
+func (ctx *context) readableFlags(typ *prog.FlagsType, val uint64) string {
+	var names []string
+	for _, name := range ctx.target.FlagsMap[typ.Name()] {
+		if _, ok := ctx.target.ConstMap[name]; ok && cConstRe.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	var flags []string
+	remainder := val
+	if typ.BitMask {
+		attempts := 256
+		flags, remainder = ctx.factorizeAsFlags(val, names, &attempts)
+	} else {
+		for _, name := range names {
+			if ctx.target.ConstMap[name] == val {
+				flags, remainder = []string{name}, 0
+				break
+			}
+		}
+	}
+	if len(flags) == 0 {
+		return ""
+	}
+	if ctx.readable.consts == nil {
+		ctx.readable.consts = make(map[string]uint64)
+	}
+	for _, name := range flags {
+		ctx.readable.consts[name] = ctx.target.ConstMap[name]
+	}
+	if remainder != 0 {
+		flags = append(flags, fmt.Sprintf("0x%x", remainder))
+	}
+	return strings.Join(flags, "|")
+}
+
+// readableSizeof returns sizeof expression for a len arg if it refers to a represented struct.
+func (ctx *context) readableSizeof(arg prog.Arg, fields []prog.Field, args []prog.Arg) string {
+	typ, ok := arg.Type().(*prog.LenType)
+	if !ok || typ.Offset || len(typ.Path) != 1 || typ.BitSize != 0 && typ.BitSize != 8 {
+		return ""
+	}
+	constArg, ok := arg.(*prog.ConstArg)
+	if !ok {
+		return ""
+	}
+	for i, field := range fields {
+		if field.Name != typ.Path[0] {
+			continue
+		}
+		inner := prog.InnerArg(args[i])
+		if inner == nil {
+			return ""
+		}
+		structType, ok := inner.Type().(*prog.StructType)
+		if !ok || inner.Size() != constArg.Val {
+			return ""
+		}
+		if tag := ctx.readableStruct(structType); tag != "" {
+			return fmt.Sprintf("sizeof(struct %v)", tag)
+		}
+		return ""
+	}
+	return ""
+}
+
+// readableStruct declares C struct for typ and returns its tag, or "" if typ can't be represented.
+func (ctx *context) readableStruct(typ *prog.StructType) string {
+	rt := &ctx.readable
+	if rt.structs == nil {
+		rt.structs = make(map[string]string)
+		rt.tags = make(map[string]bool)
+	}
+	if tag, ok := rt.structs[typ.Name()]; ok {
+		return tag
+	}
+	rt.structs[typ.Name()] = ""
+	if typ.Varlen() || typ.Size() == 0 || typ.Size() > maxReadableStructSize || typ.OverlayField != 0 {
+		return ""
+	}
+	body := new(bytes.Buffer)
+	for i, field := range typ.Fields {
+		if field.Condition != nil {
+			return ""
+		}
+		elem, dims, ok := ctx.readableFieldType(field.Type)
+		if !ok {
+			return ""
+		}
+		fmt.Fprintf(body, "\t%v %v%v;\n", elem, cFieldName(typ, i), dims)
+	}
+	// Template instantiations are named after the template.
+	name, _, _ := strings.Cut(typ.Name(), "[")
+	base := "syz_" + strings.Trim(cIdentRe.ReplaceAllString(name, "_"), "_")
+	tag := base
+	for seq := 2; rt.tags[tag] || reservedTags[tag]; seq++ {
+		tag = fmt.Sprintf("%v_%v", base, seq)
+	}
+	rt.tags[tag] = true
+	rt.structs[typ.Name()] = tag
+	rt.decls = append(rt.decls, fmt.Sprintf("struct %v {\n%v} __attribute__((packed));\n", tag, body))
+	return tag
+}
+
+// readableFieldType returns C element type and array dimensions of a struct field.
+func (ctx *context) readableFieldType(typ prog.Type) (string, string, bool) {
+	if prog.IsPad(typ) {
+		if typ.IsBitfield() {
+			return "", "", false
+		}
+		return "uint8", fmt.Sprintf("[%v]", typ.Size()), true
+	}
+	switch t := typ.(type) {
+	case *prog.StructType:
+		if tag := ctx.readableStruct(t); tag != "" {
+			return "struct " + tag, "", true
+		}
+	case *prog.ArrayType:
+		if t.Varlen() || t.Size() == 0 || t.Elem.Size() == 0 {
+			break
+		}
+		elem, dims, ok := ctx.readableFieldType(t.Elem)
+		if ok {
+			return elem, fmt.Sprintf("[%v]%v", t.Size()/t.Elem.Size(), dims), true
+		}
+	case *prog.BufferType, *prog.UnionType:
+		if !t.Varlen() && t.Size() != 0 {
+			return "uint8", fmt.Sprintf("[%v]", t.Size()), true
+		}
+	case *prog.IntType, *prog.ConstType, *prog.FlagsType, *prog.LenType, *prog.ProcType,
+		*prog.CsumType, *prog.ResourceType, *prog.VmaType, *prog.PtrType:
+		if typ.IsBitfield() || typ.Format() != prog.FormatNative && typ.Format() != prog.FormatBigEndian {
+			break
+		}
+		switch size := typ.Size(); size {
+		case 1, 2, 4, 8:
+			return fmt.Sprintf("uint%v", size*8), "", true
+		}
+	}
+	return "", "", false
+}
+
+// copyinMatcher matches copyins of a call to the leaf args of a struct.
+type copyinMatcher struct {
+	copyins  []prog.ExecCopyin
+	replaced map[int]bool // copyins already replaced by other structs
+	used     map[int]bool // copyins matched to the struct
+	raw      [][2]uint64  // address ranges of unions stored with raw copyins
+}
+
+// find returns the only unused copyin at addr.
+func (m *copyinMatcher) find(addr uint64) (prog.ExecArg, bool) {
+	found := -1
+	for i, copyin := range m.copyins {
+		if copyin.Addr != addr || isCsumCopyin(copyin) || m.replaced[i] || m.used[i] {
+			continue
+		}
+		if found != -1 {
+			return nil, false
+		}
+		found = i
+	}
+	if found == -1 {
+		return nil, false
+	}
+	m.used[found] = true
+	return m.copyins[found].Arg, true
+}
+
+// first returns index of the first copyin to the struct at [addr, addr+size).
+// It fails if any other copyin except for raw union copyins writes to the struct.
+// Checksums are not considered since they are stored after all other copyins.
+func (m *copyinMatcher) first(addr, size uint64) (int, bool) {
+	first := len(m.copyins)
+	for i, copyin := range m.copyins {
+		if isCsumCopyin(copyin) {
+			continue
+		}
+		start, end := copyin.Addr, copyin.Addr+copyinSize(copyin.Arg)
+		if end <= addr || start >= addr+size {
+			continue
+		}
+		if !m.used[i] && (m.replaced[i] || !m.isRaw(start, end)) {
+			return 0, false
+		}
+		first = min(first, i)
+	}
+	return first, true
+}
+
+func (m *copyinMatcher) isRaw(start, end uint64) bool {
+	for _, r := range m.raw {
+		if start >= r[0] && end <= r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+func isCsumCopyin(copyin prog.ExecCopyin) bool {
+	_, ok := copyin.Arg.(prog.ExecArgCsum)
+	return ok
+}
+
+func copyinSize(arg prog.ExecArg) uint64 {
+	switch a := arg.(type) {
+	case prog.ExecArgConst:
+		return a.Size
+	case prog.ExecArgResult:
+		return a.Size
+	case prog.ExecArgData:
+		return uint64(len(a.Data))
+	case prog.ExecArgCsum:
+		return a.Size
+	}
+	panic(fmt.Sprintf("bad argument type: %+v", arg))
+}
+
+func cFieldName(typ *prog.StructType, i int) string {
+	field := typ.Fields[i]
+	if prog.IsPad(field.Type) || field.Name == "" {
+		return fmt.Sprintf("_pad%v", i)
+	}
+	return cIdent(field.Name)
+}
+
+// cIdent turns a syzlang field name into a C identifier that does not clash with
+// keywords and object-like macros defined by the headers.
+func cIdent(name string) string {
+	name = cIdentRe.ReplaceAllString(name, "_")
+	if cConstRe.MatchString(name) {
+		// Fields named after constants.
+		name = strings.ToLower(name)
+	}
+	if cReserved[name] {
+		name += "_"
+	}
+	return name
+}
+
+var (
+	cIdentRe = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+	cConstRe = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+	cReserved = map[string]bool{
+		"auto": true, "break": true, "case": true, "char": true, "const": true, "continue": true,
+		"default": true, "do": true, "double": true, "else": true, "enum": true, "extern": true,
+		"float": true, "for": true, "goto": true, "if": true, "inline": true, "int": true,
+		"long": true, "register": true, "restrict": true, "return": true, "short": true,
+		"signed": true, "sizeof": true, "static": true, "struct": true, "switch": true,
+		"typedef": true, "union": true, "unsigned": true, "void": true, "volatile": true,
+		"while": true, "bool": true, "true": true, "false": true,
+		"errno": true, "stdin": true, "stdout": true, "stderr": true,
+		"linux": true, "unix": true, "i386": true,
+		"st_atime": true, "st_mtime": true, "st_ctime": true, "sa_handler": true, "sa_sigaction": true,
+	}
+	// Names used in the initializers that must not be shadowed by the local variables.
+	localReserved = map[string]bool{
+		"r": true, "procid": true, "memcpy": true, "memset": true,
+	}
+	// Struct tags used by the common headers.
+	reservedTags = map[string]bool{
+		"syz_fuse_req_out": true,
+	}
+)
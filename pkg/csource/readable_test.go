@@ -0,0 +1,63 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package csource
+
+import (
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadable(t *testing.T) {
+	if runtime.GOOS != targets.Linux {
+		t.Skipf("the test program can only be built on linux")
+	}
+	target, err := prog.GetTarget(targets.Linux, targets.AMD64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := targets.Get(target.OS, target.Arch).BrokenCompiler; err != "" {
+		t.Skipf("target compiler is broken: %v", err)
+	}
+	p, err := target.Deserialize([]byte(`
+r0 = socket$inet(0x2, 0x1, 0x0)
+bind$inet(r0, &(0x7f0000000000)={0x2, 0x4e20, @loopback}, 0x10)
+r1 = epoll_create1(0x0)
+epoll_ctl$EPOLL_CTL_ADD(r1, 0x1, r0, &(0x7f0000000040)={0x80000001, 0x4})
+timer_create(0x0, &(0x7f0000000080)={0x0, 0x0, 0x0, @tid=0x0}, &(0x7f00000000c0))
+`), prog.Strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := Write(p, Options{
+		Slowdown: 1,
+		Readable: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		// Unions are declared as byte arrays and stored with raw copyins after the struct.
+		"struct syz_sockaddr_in {\n\tuint16_t family;\n\tuint16_t port;\n\tuint8_t addr[4];\n" +
+			"\tuint8_t _pad3[8];\n} __attribute__((packed));\n",
+		"\t{\n\t\tstruct syz_sockaddr_in addr = {\n\t\t\t.family = 2,\n\t\t\t.port = htobe16(0x4e20),\n\t\t};\n" +
+			"memcpy((void*)0x20000000, &addr, sizeof(addr));\n\t}\n*(uint32_t*)0x20000004 = htobe32(0x7f000001);\n",
+		"/*addrlen=*/sizeof(struct syz_sockaddr_in)",
+		"#ifndef EPOLLET\n#define EPOLLET 0x80000000\n#endif\n",
+		"\t\t\t.ev = EPOLLET|POLLIN,\n\t\t\t.data = 4,\n",
+		// All fields are zero.
+		"memset((void*)0x20000080, 0, sizeof(struct syz_sigevent));\n",
+	} {
+		assert.Contains(t, string(src), want)
+	}
+	bin, err := Build(target, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(bin)
+}
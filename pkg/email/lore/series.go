@@ -0,0 +1,180 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package lore
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/syzkaller/dashboard/dashapi"
+	"github.com/google/syzkaller/pkg/email"
+)
+
+// Series is a patch series posted to a mailing list.
+type Series struct {
+	// Subject and MessageID of the thread (of the cover letter, or of the patch if it's a single patch).
+	Subject   string
+	MessageID string
+	Link      string
+	Version   int
+	Author    string
+	// Cc contains all recipients of the series.
+	Cc []string
+	// BaseCommit is the commit the series is based on (from the "base-commit:" trailer added by
+	// git format-patch --base), empty if it's not specified.
+	BaseCommit string
+	Patches    []*Patch
+}
+
+type Patch struct {
+	Seq   int
+	Title string
+	Diff  string
+}
+
+// PatchSeries extracts complete patch series from the threads.
+// A series is complete if all its patches (1/N .. N/N) are present in the thread.
+func PatchSeries(threads []*Thread) []*Series {
+	var ret []*Series
+	for _, thread := range threads {
+		if thread.Type != dashapi.DiscussionPatch {
+			continue
+		}
+		if series := threadSeries(thread); series != nil {
+			ret = append(ret, series)
+		}
+	}
+	return ret
+}
+
+func threadSeries(thread *Thread) *Series {
+	root := thread.Messages[0]
+	rootTag, ok := parsePatchSubject(root.Subject)
+	if !ok {
+		return nil
+	}
+	series := &Series{
+		Subject:   root.Subject,
+		MessageID: root.MessageID,
+		Link:      root.Link,
+		Version:   rootTag.version,
+		Author:    root.Author,
+		Cc:        email.MergeEmailLists([]string{root.Author}, root.Cc),
+	}
+	patches := map[int]*Patch{}
+	for _, msg := range thread.Messages {
+		tag, ok := parsePatchSubject(msg.Subject)
+		if !ok || tag.version != rootTag.version || tag.total != rootTag.total {
+			continue
+		}
+		if series.BaseCommit == "" {
+			series.BaseCommit = parseBaseCommit(msg)
+		}
+		if msg.Patch == "" {
+			continue
+		}
+		if patches[tag.seq] != nil || tag.total != 0 && (tag.seq < 1 || tag.seq > tag.total) {
+			continue
+		}
+		patches[tag.seq] = &Patch{
+			Seq:   tag.seq,
+			Title: tag.title,
+			Diff:  msg.Patch,
+		}
+	}
+	total := rootTag.total
+	if total == 0 {
+		// A single patch without the number.
+		total = 1
+		if patches[0] != nil {
+			patches[1] = patches[0]
+			patches[1].Seq = 1
+		}
+	}
+	for seq := 1; seq <= total; seq++ {
+		if patches[seq] == nil {
+			return nil
+		}
+		series.Patches = append(series.Patches, patches[seq])
+	}
+	return series
+}
+
+// Files returns the sorted list of files modified by the series.
+func (series *Series) Files() []string {
+	files := map[string]bool{}
+	for _, patch := range series.Patches {
+		for _, file := range PatchFiles(patch.Diff) {
+			files[file] = true
+		}
+	}
+	var ret []string
+	for file := range files {
+		ret = append(ret, file)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// PatchFiles returns the files modified by the diff.
+func PatchFiles(diff string) []string {
+	var ret []string
+	for _, match := range patchFileRe.FindAllStringSubmatch(diff, -1) {
+		if match[1] != "/dev/null" {
+			ret = append(ret, strings.TrimPrefix(match[1], "b/"))
+		}
+	}
+	return ret
+}
+
+var patchFileRe = regexp.MustCompile(`(?m)^\+\+\+ (\S+)`)
+
+// parseBaseCommit extracts the base commit from the cover letter or from one of the patches.
+func parseBaseCommit(msg *email.Email) string {
+	for _, text := range []string{msg.Body, msg.Patch} {
+		if match := baseCommitRe.FindStringSubmatch(text); match != nil {
+			return match[1]
+		}
+	}
+	return ""
+}
+
+var baseCommitRe = regexp.MustCompile(`(?m)^base-commit: ([0-9a-f]{40})\s*$`)
+
+type patchTag struct {
+	version int
+	seq     int
+	total   int
+	title   string
+}
+
+// parsePatchSubject parses subjects like "[PATCH net-next v2 3/5] net: fix something".
+func parsePatchSubject(subject string) (patchTag, bool) {
+	match := patchTagRe.FindStringSubmatch(subject)
+	if match == nil || strings.HasPrefix(strings.ToLower(strings.TrimSpace(subject)), "re:") {
+		return patchTag{}, false
+	}
+	tag := patchTag{
+		version: 1,
+		title:   strings.TrimSpace(subject[len(match[0]):]),
+	}
+	for _, token := range strings.Fields(match[1]) {
+		token = strings.ToLower(token)
+		if ver := patchVersionRe.FindStringSubmatch(token); ver != nil {
+			tag.version, _ = strconv.Atoi(ver[1])
+		} else if num := patchNumberRe.FindStringSubmatch(token); num != nil {
+			tag.seq, _ = strconv.Atoi(num[1])
+			tag.total, _ = strconv.Atoi(num[2])
+		}
+	}
+	return tag, true
+}
+
+var (
+	patchTagRe     = regexp.MustCompile(`(?i)^\s*\[((?:[^\]]*\s)?patch(?:\s[^\]]*)?)\]`)
+	patchVersionRe = regexp.MustCompile(`^v(\d+)$`)
+	patchNumberRe  = regexp.MustCompile(`^(\d+)/(\d+)$`)
+)
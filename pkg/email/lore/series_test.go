@@ -0,0 +1,112 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package lore
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/email"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatchSeries(t *testing.T) {
+	patchMsg := func(id, inReplyTo, subject, file string) string {
+		header := fmt.Sprintf("Date: Sun, 7 May 2017 19:54:00 -0700\nSubject: %v\nMessage-ID: <%v>\n", subject, id)
+		if inReplyTo != "" {
+			header += fmt.Sprintf("In-Reply-To: <%v>\n", inReplyTo)
+		}
+		body := "Some description."
+		if file != "" {
+			body += fmt.Sprintf(`
+
+diff --git a/%[1]v b/%[1]v
+--- a/%[1]v
++++ b/%[1]v
+@@ -1,1 +1,1 @@
+-a
++b
+`, file)
+		}
+		return header + `From: UserA <a@user.com>
+To: list@lists.com
+Cc: UserB <b@user.com>
+Content-Type: text/plain
+
+` + body
+	}
+	messages := []string{
+		// A complete series with a cover letter and a review.
+		patchMsg("A-0", "", "[PATCH net v2 0/2] net: fix things", ""),
+		patchMsg("A-1", "A-0", "[PATCH net v2 1/2] net: fix foo", "net/foo.c"),
+		patchMsg("A-2", "A-0", "[PATCH net v2 2/2] net: fix bar", "net/bar.c"),
+		patchMsg("A-1-1", "A-1", "Re: [PATCH net v2 1/2] net: fix foo", "net/foo.c"),
+		// An incomplete series.
+		patchMsg("B-1", "", "[PATCH 1/3] mm: first", "mm/first.c"),
+		patchMsg("B-2", "B-1", "[PATCH 2/3] mm: second", "mm/second.c"),
+		// A single patch.
+		patchMsg("C", "", "[RFC PATCH] fs: change", "fs/open.c"),
+		// Not a patch.
+		patchMsg("D", "", "Question", ""),
+	}
+	messages[0] += "\n\nbase-commit: 0123456789abcdef0123456789abcdef01234567\n"
+	var emails []*email.Email
+	for _, m := range messages {
+		msg, err := email.Parse(strings.NewReader(m), nil, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		emails = append(emails, msg)
+	}
+	series := PatchSeries(Threads(emails))
+	sort.Slice(series, func(i, j int) bool {
+		return series[i].MessageID < series[j].MessageID
+	})
+	if !assert.Len(t, series, 2) {
+		return
+	}
+
+	a := series[0]
+	assert.Equal(t, "<A-0>", a.MessageID)
+	assert.Equal(t, "[PATCH net v2 0/2] net: fix things", a.Subject)
+	assert.Equal(t, 2, a.Version)
+	assert.Equal(t, "a@user.com", a.Author)
+	assert.ElementsMatch(t, []string{"a@user.com", "b@user.com", "list@lists.com"}, a.Cc)
+	assert.Len(t, a.Patches, 2)
+	assert.Equal(t, "net: fix foo", a.Patches[0].Title)
+	assert.Equal(t, "net: fix bar", a.Patches[1].Title)
+	assert.Equal(t, []string{"net/bar.c", "net/foo.c"}, a.Files())
+	assert.Equal(t, "0123456789abcdef0123456789abcdef01234567", a.BaseCommit)
+
+	c := series[1]
+	assert.Equal(t, "<C>", c.MessageID)
+	assert.Equal(t, 1, c.Version)
+	assert.Len(t, c.Patches, 1)
+	assert.Equal(t, "fs: change", c.Patches[0].Title)
+	assert.Equal(t, []string{"fs/open.c"}, c.Files())
+	assert.Empty(t, c.BaseCommit)
+}
+
+func TestParsePatchSubject(t *testing.T) {
+	tests := []struct {
+		subject string
+		tag     patchTag
+		ok      bool
+	}{
+		{"[PATCH] foo", patchTag{version: 1, title: "foo"}, true},
+		{"[PATCH v3 2/7] foo: bar", patchTag{version: 3, seq: 2, total: 7, title: "foo: bar"}, true},
+		{"[PATCH net-next 0/2] foo", patchTag{version: 1, seq: 0, total: 2, title: "foo"}, true},
+		{"[RESEND PATCH V2] foo", patchTag{version: 2, title: "foo"}, true},
+		{"Re: [PATCH] foo", patchTag{}, false},
+		{"[syzbot] foo", patchTag{}, false},
+		{"[dispatcher] foo", patchTag{}, false},
+	}
+	for _, test := range tests {
+		tag, ok := parsePatchSubject(test.subject)
+		assert.Equal(t, test.ok, ok, test.subject)
+		assert.Equal(t, test.tag, tag, test.subject)
+	}
+}
@@ -243,6 +243,11 @@ func CanonicalEmail(email string) string {
 	return strings.ToLower(addr.Address)
 }
 
+// ParseCommands extracts #syz commands from a free-form text (e.g. a comment in an issue tracker).
+func ParseCommands(body string) []*SingleCommand {
+	return extractCommands(body)
+}
+
 func extractCommands(body string) []*SingleCommand {
 	var ret []*SingleCommand
 	for body != "" {
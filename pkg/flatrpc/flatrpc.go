@@ -396,6 +396,7 @@ const (
 	CallFlagFinished      CallFlag = 2
 	CallFlagBlocked       CallFlag = 4
 	CallFlagFaultInjected CallFlag = 8
+	CallFlagCached        CallFlag = 16
 )
 
 var EnumNamesCallFlag = map[CallFlag]string{
@@ -403,6 +404,7 @@ var EnumNamesCallFlag = map[CallFlag]string{
 	CallFlagFinished:      "Finished",
 	CallFlagBlocked:       "Blocked",
 	CallFlagFaultInjected: "FaultInjected",
+	CallFlagCached:        "Cached",
 }
 
 var EnumValuesCallFlag = map[string]CallFlag{
@@ -410,6 +412,7 @@ var EnumValuesCallFlag = map[string]CallFlag{
 	"Finished":      CallFlagFinished,
 	"Blocked":       CallFlagBlocked,
 	"FaultInjected": CallFlagFaultInjected,
+	"Cached":        CallFlagCached,
 }
 
 func (v CallFlag) String() string {
@@ -429,6 +432,7 @@ const (
 	SnapshotStateExecute     SnapshotState = 4
 	SnapshotStateExecuted    SnapshotState = 5
 	SnapshotStateFailed      SnapshotState = 6
+	SnapshotStatePrefixReady SnapshotState = 7
 )
 
 var EnumNamesSnapshotState = map[SnapshotState]string{
@@ -439,6 +443,7 @@ var EnumNamesSnapshotState = map[SnapshotState]string{
 	SnapshotStateExecute:     "Execute",
 	SnapshotStateExecuted:    "Executed",
 	SnapshotStateFailed:      "Failed",
+	SnapshotStatePrefixReady: "PrefixReady",
 }
 
 var EnumValuesSnapshotState = map[string]SnapshotState{
@@ -449,6 +454,7 @@ var EnumValuesSnapshotState = map[string]SnapshotState{
 	"Execute":     SnapshotStateExecute,
 	"Executed":    SnapshotStateExecuted,
 	"Failed":      SnapshotStateFailed,
+	"PrefixReady": SnapshotStatePrefixReady,
 }
 
 func (v SnapshotState) String() string {
@@ -2499,11 +2505,13 @@ func ExecutingMessageRawEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 }
 
 type CallInfoRawT struct {
-	Flags  CallFlag          `json:"flags"`
-	Error  int32             `json:"error"`
-	Signal []uint64          `json:"signal"`
-	Cover  []uint64          `json:"cover"`
-	Comps  []*ComparisonRawT `json:"comps"`
+	Flags      CallFlag          `json:"flags"`
+	Error      int32             `json:"error"`
+	Signal     []uint64          `json:"signal"`
+	Cover      []uint64          `json:"cover"`
+	Comps      []*ComparisonRawT `json:"comps"`
+	Elapsed    uint64            `json:"elapsed"`
+	KernelTime uint64            `json:"kernel_time"`
 }
 
 func (t *CallInfoRawT) Pack(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
@@ -2543,6 +2551,8 @@ func (t *CallInfoRawT) Pack(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	CallInfoRawAddSignal(builder, signalOffset)
 	CallInfoRawAddCover(builder, coverOffset)
 	CallInfoRawAddComps(builder, compsOffset)
+	CallInfoRawAddElapsed(builder, t.Elapsed)
+	CallInfoRawAddKernelTime(builder, t.KernelTime)
 	return CallInfoRawEnd(builder)
 }
 
@@ -2566,6 +2576,8 @@ func (rcv *CallInfoRaw) UnPackTo(t *CallInfoRawT) {
 		rcv.Comps(&x, j)
 		t.Comps[j] = x.UnPack()
 	}
+	t.Elapsed = rcv.Elapsed()
+	t.KernelTime = rcv.KernelTime()
 }
 
 func (rcv *CallInfoRaw) UnPack() *CallInfoRawT {
@@ -2699,8 +2711,32 @@ func (rcv *CallInfoRaw) CompsLength() int {
 	return 0
 }
 
+func (rcv *CallInfoRaw) Elapsed() uint64 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(14))
+	if o != 0 {
+		return rcv._tab.GetUint64(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *CallInfoRaw) MutateElapsed(n uint64) bool {
+	return rcv._tab.MutateUint64Slot(14, n)
+}
+
+func (rcv *CallInfoRaw) KernelTime() uint64 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(16))
+	if o != 0 {
+		return rcv._tab.GetUint64(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *CallInfoRaw) MutateKernelTime(n uint64) bool {
+	return rcv._tab.MutateUint64Slot(16, n)
+}
+
 func CallInfoRawStart(builder *flatbuffers.Builder) {
-	builder.StartObject(5)
+	builder.StartObject(7)
 }
 func CallInfoRawAddFlags(builder *flatbuffers.Builder, flags CallFlag) {
 	builder.PrependByteSlot(0, byte(flags), 0)
@@ -2726,6 +2762,12 @@ func CallInfoRawAddComps(builder *flatbuffers.Builder, comps flatbuffers.UOffset
 func CallInfoRawStartCompsVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
 	return builder.StartVector(32, numElems, 8)
 }
+func CallInfoRawAddElapsed(builder *flatbuffers.Builder, elapsed uint64) {
+	builder.PrependUint64Slot(5, elapsed, 0)
+}
+func CallInfoRawAddKernelTime(builder *flatbuffers.Builder, kernelTime uint64) {
+	builder.PrependUint64Slot(6, kernelTime, 0)
+}
 func CallInfoRawEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
@@ -3616,6 +3658,7 @@ type SnapshotRequestT struct {
 	AllCallSignal  uint64   `json:"all_call_signal"`
 	AllExtraSignal bool     `json:"all_extra_signal"`
 	ProgData       []byte   `json:"prog_data"`
+	SnapshotPrefix int32    `json:"snapshot_prefix"`
 }
 
 func (t *SnapshotRequestT) Pack(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
@@ -3632,6 +3675,7 @@ func (t *SnapshotRequestT) Pack(builder *flatbuffers.Builder) flatbuffers.UOffse
 	SnapshotRequestAddAllCallSignal(builder, t.AllCallSignal)
 	SnapshotRequestAddAllExtraSignal(builder, t.AllExtraSignal)
 	SnapshotRequestAddProgData(builder, progDataOffset)
+	SnapshotRequestAddSnapshotPrefix(builder, t.SnapshotPrefix)
 	return SnapshotRequestEnd(builder)
 }
 
@@ -3641,6 +3685,7 @@ func (rcv *SnapshotRequest) UnPackTo(t *SnapshotRequestT) {
 	t.AllCallSignal = rcv.AllCallSignal()
 	t.AllExtraSignal = rcv.AllExtraSignal()
 	t.ProgData = rcv.ProgDataBytes()
+	t.SnapshotPrefix = rcv.SnapshotPrefix()
 }
 
 func (rcv *SnapshotRequest) UnPack() *SnapshotRequestT {
@@ -3761,8 +3806,20 @@ func (rcv *SnapshotRequest) MutateProgData(j int, n byte) bool {
 	return false
 }
 
+func (rcv *SnapshotRequest) SnapshotPrefix() int32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(14))
+	if o != 0 {
+		return rcv._tab.GetInt32(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *SnapshotRequest) MutateSnapshotPrefix(n int32) bool {
+	return rcv._tab.MutateInt32Slot(14, n)
+}
+
 func SnapshotRequestStart(builder *flatbuffers.Builder) {
-	builder.StartObject(5)
+	builder.StartObject(6)
 }
 func SnapshotRequestAddExecFlags(builder *flatbuffers.Builder, execFlags ExecFlag) {
 	builder.PrependUint64Slot(0, uint64(execFlags), 0)
@@ -3779,6 +3836,9 @@ func SnapshotRequestAddAllExtraSignal(builder *flatbuffers.Builder, allExtraSign
 func SnapshotRequestAddProgData(builder *flatbuffers.Builder, progData flatbuffers.UOffsetT) {
 	builder.PrependUOffsetTSlot(4, flatbuffers.UOffsetT(progData), 0)
 }
+func SnapshotRequestAddSnapshotPrefix(builder *flatbuffers.Builder, snapshotPrefix int32) {
+	builder.PrependInt32Slot(5, snapshotPrefix, 0)
+}
 func SnapshotRequestStartProgDataVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
 	return builder.StartVector(1, numElems, 1)
 }
@@ -649,23 +649,25 @@ enum class CallFlag : uint8_t {
   Finished = 2,
   Blocked = 4,
   FaultInjected = 8,
+  Cached = 16,
   NONE = 0,
-  ANY = 15
+  ANY = 31
 };
 FLATBUFFERS_DEFINE_BITMASK_OPERATORS(CallFlag, uint8_t)
 
-inline const CallFlag (&EnumValuesCallFlag())[4] {
+inline const CallFlag (&EnumValuesCallFlag())[5] {
   static const CallFlag values[] = {
     CallFlag::Executed,
     CallFlag::Finished,
     CallFlag::Blocked,
-    CallFlag::FaultInjected
+    CallFlag::FaultInjected,
+    CallFlag::Cached
   };
   return values;
 }
 
 inline const char * const *EnumNamesCallFlag() {
-  static const char * const names[9] = {
+  static const char * const names[17] = {
     "Executed",
     "Finished",
     "",
@@ -674,13 +676,21 @@ inline const char * const *EnumNamesCallFlag() {
     "",
     "",
     "FaultInjected",
+    "",
+    "",
+    "",
+    "",
+    "",
+    "",
+    "",
+    "Cached",
     nullptr
   };
   return names;
 }
 
 inline const char *EnumNameCallFlag(CallFlag e) {
-  if (flatbuffers::IsOutRange(e, CallFlag::Executed, CallFlag::FaultInjected)) return "";
+  if (flatbuffers::IsOutRange(e, CallFlag::Executed, CallFlag::Cached)) return "";
   const size_t index = static_cast<size_t>(e) - static_cast<size_t>(CallFlag::Executed);
   return EnumNamesCallFlag()[index];
 }
@@ -693,11 +703,12 @@ enum class SnapshotState : uint64_t {
   Execute = 4ULL,
   Executed = 5ULL,
   Failed = 6ULL,
+  PrefixReady = 7ULL,
   MIN = Initial,
-  MAX = Failed
+  MAX = PrefixReady
 };
 
-inline const SnapshotState (&EnumValuesSnapshotState())[7] {
+inline const SnapshotState (&EnumValuesSnapshotState())[8] {
   static const SnapshotState values[] = {
     SnapshotState::Initial,
     SnapshotState::Handshake,
@@ -705,13 +716,14 @@ inline const SnapshotState (&EnumValuesSnapshotState())[7] {
     SnapshotState::Snapshotted,
     SnapshotState::Execute,
     SnapshotState::Executed,
-    SnapshotState::Failed
+    SnapshotState::Failed,
+    SnapshotState::PrefixReady
   };
   return values;
 }
 
 inline const char * const *EnumNamesSnapshotState() {
-  static const char * const names[8] = {
+  static const char * const names[9] = {
     "Initial",
     "Handshake",
     "Ready",
@@ -719,13 +731,14 @@ inline const char * const *EnumNamesSnapshotState() {
     "Execute",
     "Executed",
     "Failed",
+    "PrefixReady",
     nullptr
   };
   return names;
 }
 
 inline const char *EnumNameSnapshotState(SnapshotState e) {
-  if (flatbuffers::IsOutRange(e, SnapshotState::Initial, SnapshotState::Failed)) return "";
+  if (flatbuffers::IsOutRange(e, SnapshotState::Initial, SnapshotState::PrefixReady)) return "";
   const size_t index = static_cast<size_t>(e);
   return EnumNamesSnapshotState()[index];
 }
@@ -2129,6 +2142,8 @@ struct CallInfoRawT : public flatbuffers::NativeTable {
   std::vector<uint64_t> signal{};
   std::vector<uint64_t> cover{};
   std::vector<rpc::ComparisonRaw> comps{};
+  uint64_t elapsed = 0;
+  uint64_t kernel_time = 0;
 };
 
 struct CallInfoRaw FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
@@ -2139,7 +2154,9 @@ struct CallInfoRaw FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
     VT_ERROR = 6,
     VT_SIGNAL = 8,
     VT_COVER = 10,
-    VT_COMPS = 12
+    VT_COMPS = 12,
+    VT_ELAPSED = 14,
+    VT_KERNEL_TIME = 16
   };
   rpc::CallFlag flags() const {
     return static_cast<rpc::CallFlag>(GetField<uint8_t>(VT_FLAGS, 0));
@@ -2156,6 +2173,12 @@ struct CallInfoRaw FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
   const flatbuffers::Vector<const rpc::ComparisonRaw *> *comps() const {
     return GetPointer<const flatbuffers::Vector<const rpc::ComparisonRaw *> *>(VT_COMPS);
   }
+  uint64_t elapsed() const {
+    return GetField<uint64_t>(VT_ELAPSED, 0);
+  }
+  uint64_t kernel_time() const {
+    return GetField<uint64_t>(VT_KERNEL_TIME, 0);
+  }
   bool Verify(flatbuffers::Verifier &verifier) const {
     return VerifyTableStart(verifier) &&
            VerifyField<uint8_t>(verifier, VT_FLAGS, 1) &&
@@ -2166,6 +2189,8 @@ struct CallInfoRaw FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
            verifier.VerifyVector(cover()) &&
            VerifyOffset(verifier, VT_COMPS) &&
            verifier.VerifyVector(comps()) &&
+           VerifyField<uint64_t>(verifier, VT_ELAPSED, 8) &&
+           VerifyField<uint64_t>(verifier, VT_KERNEL_TIME, 8) &&
            verifier.EndTable();
   }
   CallInfoRawT *UnPack(const flatbuffers::resolver_function_t *_resolver = nullptr) const;
@@ -2192,6 +2217,12 @@ struct CallInfoRawBuilder {
   void add_comps(flatbuffers::Offset<flatbuffers::Vector<const rpc::ComparisonRaw *>> comps) {
     fbb_.AddOffset(CallInfoRaw::VT_COMPS, comps);
   }
+  void add_elapsed(uint64_t elapsed) {
+    fbb_.AddElement<uint64_t>(CallInfoRaw::VT_ELAPSED, elapsed, 0);
+  }
+  void add_kernel_time(uint64_t kernel_time) {
+    fbb_.AddElement<uint64_t>(CallInfoRaw::VT_KERNEL_TIME, kernel_time, 0);
+  }
   explicit CallInfoRawBuilder(flatbuffers::FlatBufferBuilder &_fbb)
         : fbb_(_fbb) {
     start_ = fbb_.StartTable();
@@ -2209,8 +2240,12 @@ inline flatbuffers::Offset<CallInfoRaw> CreateCallInfoRaw(
     int32_t error = 0,
     flatbuffers::Offset<flatbuffers::Vector<uint64_t>> signal = 0,
     flatbuffers::Offset<flatbuffers::Vector<uint64_t>> cover = 0,
-    flatbuffers::Offset<flatbuffers::Vector<const rpc::ComparisonRaw *>> comps = 0) {
+    flatbuffers::Offset<flatbuffers::Vector<const rpc::ComparisonRaw *>> comps = 0,
+    uint64_t elapsed = 0,
+    uint64_t kernel_time = 0) {
   CallInfoRawBuilder builder_(_fbb);
+  builder_.add_kernel_time(kernel_time);
+  builder_.add_elapsed(elapsed);
   builder_.add_comps(comps);
   builder_.add_cover(cover);
   builder_.add_signal(signal);
@@ -2225,7 +2260,9 @@ inline flatbuffers::Offset<CallInfoRaw> CreateCallInfoRawDirect(
     int32_t error = 0,
     const std::vector<uint64_t> *signal = nullptr,
     const std::vector<uint64_t> *cover = nullptr,
-    const std::vector<rpc::ComparisonRaw> *comps = nullptr) {
+    const std::vector<rpc::ComparisonRaw> *comps = nullptr,
+    uint64_t elapsed = 0,
+    uint64_t kernel_time = 0) {
   auto signal__ = signal ? _fbb.CreateVector<uint64_t>(*signal) : 0;
   auto cover__ = cover ? _fbb.CreateVector<uint64_t>(*cover) : 0;
   auto comps__ = comps ? _fbb.CreateVectorOfStructs<rpc::ComparisonRaw>(*comps) : 0;
@@ -2235,7 +2272,9 @@ inline flatbuffers::Offset<CallInfoRaw> CreateCallInfoRawDirect(
       error,
       signal__,
       cover__,
-      comps__);
+      comps__,
+      elapsed,
+      kernel_time);
 }
 
 flatbuffers::Offset<CallInfoRaw> CreateCallInfoRaw(flatbuffers::FlatBufferBuilder &_fbb, const CallInfoRawT *_o, const flatbuffers::rehasher_function_t *_rehasher = nullptr);
@@ -2756,6 +2795,7 @@ struct SnapshotRequestT : public flatbuffers::NativeTable {
   uint64_t all_call_signal = 0;
   bool all_extra_signal = false;
   std::vector<uint8_t> prog_data{};
+  int32_t snapshot_prefix = 0;
 };
 
 struct SnapshotRequest FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
@@ -2766,7 +2806,8 @@ struct SnapshotRequest FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
     VT_NUM_CALLS = 6,
     VT_ALL_CALL_SIGNAL = 8,
     VT_ALL_EXTRA_SIGNAL = 10,
-    VT_PROG_DATA = 12
+    VT_PROG_DATA = 12,
+    VT_SNAPSHOT_PREFIX = 14
   };
   rpc::ExecFlag exec_flags() const {
     return static_cast<rpc::ExecFlag>(GetField<uint64_t>(VT_EXEC_FLAGS, 0));
@@ -2783,6 +2824,9 @@ struct SnapshotRequest FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
   const flatbuffers::Vector<uint8_t> *prog_data() const {
     return GetPointer<const flatbuffers::Vector<uint8_t> *>(VT_PROG_DATA);
   }
+  int32_t snapshot_prefix() const {
+    return GetField<int32_t>(VT_SNAPSHOT_PREFIX, 0);
+  }
   bool Verify(flatbuffers::Verifier &verifier) const {
     return VerifyTableStart(verifier) &&
            VerifyField<uint64_t>(verifier, VT_EXEC_FLAGS, 8) &&
@@ -2791,6 +2835,7 @@ struct SnapshotRequest FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
            VerifyField<uint8_t>(verifier, VT_ALL_EXTRA_SIGNAL, 1) &&
            VerifyOffset(verifier, VT_PROG_DATA) &&
            verifier.VerifyVector(prog_data()) &&
+           VerifyField<int32_t>(verifier, VT_SNAPSHOT_PREFIX, 4) &&
            verifier.EndTable();
   }
   SnapshotRequestT *UnPack(const flatbuffers::resolver_function_t *_resolver = nullptr) const;
@@ -2817,6 +2862,9 @@ struct SnapshotRequestBuilder {
   void add_prog_data(flatbuffers::Offset<flatbuffers::Vector<uint8_t>> prog_data) {
     fbb_.AddOffset(SnapshotRequest::VT_PROG_DATA, prog_data);
   }
+  void add_snapshot_prefix(int32_t snapshot_prefix) {
+    fbb_.AddElement<int32_t>(SnapshotRequest::VT_SNAPSHOT_PREFIX, snapshot_prefix, 0);
+  }
   explicit SnapshotRequestBuilder(flatbuffers::FlatBufferBuilder &_fbb)
         : fbb_(_fbb) {
     start_ = fbb_.StartTable();
@@ -2834,10 +2882,12 @@ inline flatbuffers::Offset<SnapshotRequest> CreateSnapshotRequest(
     int32_t num_calls = 0,
     uint64_t all_call_signal = 0,
     bool all_extra_signal = false,
-    flatbuffers::Offset<flatbuffers::Vector<uint8_t>> prog_data = 0) {
+    flatbuffers::Offset<flatbuffers::Vector<uint8_t>> prog_data = 0,
+    int32_t snapshot_prefix = 0) {
   SnapshotRequestBuilder builder_(_fbb);
   builder_.add_all_call_signal(all_call_signal);
   builder_.add_exec_flags(exec_flags);
+  builder_.add_snapshot_prefix(snapshot_prefix);
   builder_.add_prog_data(prog_data);
   builder_.add_num_calls(num_calls);
   builder_.add_all_extra_signal(all_extra_signal);
@@ -2850,7 +2900,8 @@ inline flatbuffers::Offset<SnapshotRequest> CreateSnapshotRequestDirect(
     int32_t num_calls = 0,
     uint64_t all_call_signal = 0,
     bool all_extra_signal = false,
-    const std::vector<uint8_t> *prog_data = nullptr) {
+    const std::vector<uint8_t> *prog_data = nullptr,
+    int32_t snapshot_prefix = 0) {
   auto prog_data__ = prog_data ? _fbb.CreateVector<uint8_t>(*prog_data) : 0;
   return rpc::CreateSnapshotRequest(
       _fbb,
@@ -2858,7 +2909,8 @@ inline flatbuffers::Offset<SnapshotRequest> CreateSnapshotRequestDirect(
       num_calls,
       all_call_signal,
       all_extra_signal,
-      prog_data__);
+      prog_data__,
+      snapshot_prefix);
 }
 
 flatbuffers::Offset<SnapshotRequest> CreateSnapshotRequest(flatbuffers::FlatBufferBuilder &_fbb, const SnapshotRequestT *_o, const flatbuffers::rehasher_function_t *_rehasher = nullptr);
@@ -3374,6 +3426,8 @@ inline void CallInfoRaw::UnPackTo(CallInfoRawT *_o, const flatbuffers::resolver_
   { auto _e = signal(); if (_e) { _o->signal.resize(_e->size()); for (flatbuffers::uoffset_t _i = 0; _i < _e->size(); _i++) { _o->signal[_i] = _e->Get(_i); } } }
   { auto _e = cover(); if (_e) { _o->cover.resize(_e->size()); for (flatbuffers::uoffset_t _i = 0; _i < _e->size(); _i++) { _o->cover[_i] = _e->Get(_i); } } }
   { auto _e = comps(); if (_e) { _o->comps.resize(_e->size()); for (flatbuffers::uoffset_t _i = 0; _i < _e->size(); _i++) { _o->comps[_i] = *_e->Get(_i); } } }
+  { auto _e = elapsed(); _o->elapsed = _e; }
+  { auto _e = kernel_time(); _o->kernel_time = _e; }
 }
 
 inline flatbuffers::Offset<CallInfoRaw> CallInfoRaw::Pack(flatbuffers::FlatBufferBuilder &_fbb, const CallInfoRawT* _o, const flatbuffers::rehasher_function_t *_rehasher) {
@@ -3389,13 +3443,17 @@ inline flatbuffers::Offset<CallInfoRaw> CreateCallInfoRaw(flatbuffers::FlatBuffe
   auto _signal = _o->signal.size() ? _fbb.CreateVector(_o->signal) : 0;
   auto _cover = _o->cover.size() ? _fbb.CreateVector(_o->cover) : 0;
   auto _comps = _o->comps.size() ? _fbb.CreateVectorOfStructs(_o->comps) : 0;
+  auto _elapsed = _o->elapsed;
+  auto _kernel_time = _o->kernel_time;
   return rpc::CreateCallInfoRaw(
       _fbb,
       _flags,
       _error,
       _signal,
       _cover,
-      _comps);
+      _comps,
+      _elapsed,
+      _kernel_time);
 }
 
 inline ProgInfoRawT::ProgInfoRawT(const ProgInfoRawT &o)
@@ -3629,6 +3687,7 @@ inline void SnapshotRequest::UnPackTo(SnapshotRequestT *_o, const flatbuffers::r
   { auto _e = all_call_signal(); _o->all_call_signal = _e; }
   { auto _e = all_extra_signal(); _o->all_extra_signal = _e; }
   { auto _e = prog_data(); if (_e) { _o->prog_data.resize(_e->size()); std::copy(_e->begin(), _e->end(), _o->prog_data.begin()); } }
+  { auto _e = snapshot_prefix(); _o->snapshot_prefix = _e; }
 }
 
 inline flatbuffers::Offset<SnapshotRequest> SnapshotRequest::Pack(flatbuffers::FlatBufferBuilder &_fbb, const SnapshotRequestT* _o, const flatbuffers::rehasher_function_t *_rehasher) {
@@ -3644,13 +3703,15 @@ inline flatbuffers::Offset<SnapshotRequest> CreateSnapshotRequest(flatbuffers::F
   auto _all_call_signal = _o->all_call_signal;
   auto _all_extra_signal = _o->all_extra_signal;
   auto _prog_data = _o->prog_data.size() ? _fbb.CreateVector(_o->prog_data) : 0;
+  auto _snapshot_prefix = _o->snapshot_prefix;
   return rpc::CreateSnapshotRequest(
       _fbb,
       _exec_flags,
       _num_calls,
       _all_call_signal,
       _all_extra_signal,
-      _prog_data);
+      _prog_data,
+      _snapshot_prefix);
 }
 
 inline bool VerifyHostMessagesRaw(flatbuffers::Verifier &verifier, const void *obj, HostMessagesRaw type) {
@@ -0,0 +1,170 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"math/bits"
+	"sync"
+	"time"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/fuzzer/queue"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+)
+
+// CallTime contains aggregated execution time statistics for a single syscall.
+type CallTime struct {
+	// Number of executions of the syscall.
+	Count uint64
+	// Number of executions that did not finish before the call timeout (blocked calls).
+	// They are accounted with the timeout as the wall-clock execution time.
+	Blocked uint64
+	// Total wall-clock and kernel execution time of all executions
+	// (kernel time is known only for finished executions).
+	Total       time.Duration
+	KernelTotal time.Duration
+	// The longest observed execution.
+	Max time.Duration
+	// Number of executions that were considered anomalously slow.
+	Slow uint64
+}
+
+func (ct CallTime) Avg() time.Duration {
+	if ct.Count == 0 {
+		return 0
+	}
+	return ct.Total / time.Duration(ct.Count)
+}
+
+func (ct CallTime) KernelAvg() time.Duration {
+	if ct.Count == ct.Blocked {
+		return 0
+	}
+	return ct.KernelTotal / time.Duration(ct.Count-ct.Blocked)
+}
+
+type callTimes struct {
+	mu       sync.Mutex
+	timeouts targets.Timeouts
+	calls    map[*prog.Syscall]*CallTime
+}
+
+func newCallTimes(timeouts targets.Timeouts) *callTimes {
+	return &callTimes{
+		timeouts: timeouts,
+		calls:    make(map[*prog.Syscall]*CallTime),
+	}
+}
+
+// callTimeout returns the time after which the executor stops waiting for the call.
+func (ct *callTimes) callTimeout(meta *prog.Syscall) time.Duration {
+	return ct.timeouts.Syscall + time.Duration(meta.Attrs.Timeout)*time.Millisecond*ct.timeouts.Scale
+}
+
+const (
+	// We don't consider a call slow until we have seen enough executions of it
+	// to have a meaningful average.
+	slowCallMinSamples = 100
+	// Calls that take less than this are never considered slow (it's mostly noise).
+	slowCallMinTime = 10 * time.Millisecond
+	// A call is slow if it takes at least 2^slowCallMinBucket times longer than the average.
+	slowCallMinBucket = 3
+	// Slowness signal is tagged with this value in the high bits,
+	// the rest of the bits contain syscall ID and the slowness bucket.
+	slowSignalTag = uint64(0x5105) << 48
+)
+
+// record updates statistics with execution times of calls in the program.
+// It returns slowness buckets for calls that were anomalously slow
+// (call index -> bucket), the bucket is log2 of the ratio to the average execution time.
+func (ct *callTimes) record(p *prog.Prog, info *flatrpc.ProgInfo) map[int]int {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	var slow map[int]int
+	for i, call := range info.Calls {
+		if i >= len(p.Calls) || call == nil || call.Flags&flatrpc.CallFlagExecuted == 0 ||
+			call.Flags&flatrpc.CallFlagCached != 0 {
+			continue
+		}
+		meta := p.Calls[i].Meta
+		stat := ct.calls[meta]
+		if stat == nil {
+			stat = new(CallTime)
+			ct.calls[meta] = stat
+		}
+		elapsed := time.Duration(call.Elapsed)
+		if call.Flags&flatrpc.CallFlagFinished == 0 {
+			// The executor does not report execution time of blocked calls,
+			// but they took at least the call timeout.
+			stat.Blocked++
+			elapsed = ct.callTimeout(meta)
+		}
+		if bucket := slowBucket(stat, elapsed); bucket != 0 {
+			stat.Slow++
+			if slow == nil {
+				slow = make(map[int]int)
+			}
+			slow[i] = bucket
+		}
+		stat.Count++
+		stat.Total += elapsed
+		stat.KernelTotal += time.Duration(call.KernelTime)
+		stat.Max = max(stat.Max, elapsed)
+	}
+	return slow
+}
+
+func slowBucket(stat *CallTime, elapsed time.Duration) int {
+	if stat.Count < slowCallMinSamples || elapsed < slowCallMinTime {
+		return 0
+	}
+	avg := stat.Avg()
+	if avg <= 0 {
+		avg = 1
+	}
+	bucket := bits.Len64(uint64(elapsed / avg))
+	if bucket <= slowCallMinBucket {
+		return 0
+	}
+	return bucket
+}
+
+func (ct *callTimes) snapshot() map[string]CallTime {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	ret := make(map[string]CallTime, len(ct.calls))
+	for meta, stat := range ct.calls {
+		ret[meta.Name] = *stat
+	}
+	return ret
+}
+
+// slownessSignal returns synthetic signal for a slow call.
+// We return signal for all buckets up to the observed one, so that signal for slightly
+// different execution times of the same program intersects during deflaking.
+func slownessSignal(meta *prog.Syscall, bucket int) []uint64 {
+	var ret []uint64
+	for b := slowCallMinBucket + 1; b <= bucket; b++ {
+		ret = append(ret, slowSignalTag|uint64(meta.ID)<<8|uint64(b))
+	}
+	return ret
+}
+
+// CallTimes returns per-syscall execution time statistics.
+func (fuzzer *Fuzzer) CallTimes() map[string]CallTime {
+	return fuzzer.callTimes.snapshot()
+}
+
+func (fuzzer *Fuzzer) handleCallTimes(req *queue.Request, info *flatrpc.ProgInfo) {
+	p := req.Prog
+	slow := fuzzer.callTimes.record(p, info)
+	fuzzer.statSlowCalls.Add(len(slow))
+	if !fuzzer.Config.SlownessSignal || req.ExecOpts.ExecFlags&flatrpc.ExecFlagCollectSignal == 0 {
+		return
+	}
+	for call, bucket := range slow {
+		info.Calls[call].Signal = append(info.Calls[call].Signal, slownessSignal(p.Calls[call].Meta, bucket)...)
+	}
+}
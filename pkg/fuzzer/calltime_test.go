@@ -0,0 +1,74 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallTimes(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64Fuzz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := target.Deserialize([]byte("test$res0()\ntest$res2()\n"), prog.NonStrict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := func(first, second time.Duration) *flatrpc.ProgInfo {
+		return &flatrpc.ProgInfo{
+			Calls: []*flatrpc.CallInfo{
+				{
+					Flags:      flatrpc.CallFlagExecuted | flatrpc.CallFlagFinished,
+					Elapsed:    uint64(first),
+					KernelTime: uint64(first / 2),
+				},
+				{
+					Flags:   flatrpc.CallFlagExecuted,
+					Elapsed: uint64(second),
+				},
+			},
+		}
+	}
+	ct := newCallTimes(targets.Timeouts{Syscall: 50 * time.Millisecond, Scale: 1})
+	for i := 0; i < slowCallMinSamples; i++ {
+		assert.Empty(t, ct.record(p, info(2*time.Millisecond, time.Hour)))
+	}
+	// Too fast to be considered slow, even though it's 4x slower than the average.
+	assert.Empty(t, ct.record(p, info(8*time.Millisecond, time.Hour)))
+	// ~30x slower than the average.
+	slow := ct.record(p, info(64*time.Millisecond, time.Hour))
+	assert.Equal(t, map[int]int{0: 5}, slow)
+	assert.Len(t, slownessSignal(p.Calls[0].Meta, slow[0]), 2)
+
+	stats := ct.snapshot()
+	assert.Len(t, stats, 2)
+	stat := stats["test$res0"]
+	assert.Equal(t, uint64(slowCallMinSamples+2), stat.Count)
+	assert.Equal(t, uint64(1), stat.Slow)
+	assert.Equal(t, 64*time.Millisecond, stat.Max)
+	assert.Equal(t, stat.Avg()/2, stat.KernelAvg())
+
+	// The second call never finishes, it's accounted with the call timeout.
+	stat = stats["test$res2"]
+	assert.Equal(t, uint64(slowCallMinSamples+2), stat.Count)
+	assert.Equal(t, uint64(slowCallMinSamples+2), stat.Blocked)
+	assert.Equal(t, 50*time.Millisecond, stat.Avg())
+	assert.Equal(t, 50*time.Millisecond, stat.Max)
+	assert.Equal(t, time.Duration(0), stat.KernelAvg())
+
+	// Cached results (e.g. restored from a snapshot) are not accounted.
+	cached := info(time.Hour, time.Hour)
+	for _, call := range cached.Calls {
+		call.Flags |= flatrpc.CallFlagCached
+	}
+	assert.Empty(t, ct.record(p, cached))
+	assert.Equal(t, stats, ct.snapshot())
+}
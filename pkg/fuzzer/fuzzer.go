@@ -18,6 +18,7 @@ import (
 	"github.com/google/syzkaller/pkg/signal"
 	"github.com/google/syzkaller/pkg/stat"
 	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
 )
 
 type Fuzzer struct {
@@ -31,6 +32,7 @@ type Fuzzer struct {
 	target       *prog.Target
 	hintsLimiter prog.HintsLimiter
 	runningJobs  map[jobIntrospector]struct{}
+	callTimes    *callTimes
 
 	ct           *prog.ChoiceTable
 	ctProgs      int
@@ -56,6 +58,7 @@ func NewFuzzer(ctx context.Context, cfg *Config, rnd *rand.Rand,
 		rnd:         rnd,
 		target:      target,
 		runningJobs: map[jobIntrospector]struct{}{},
+		callTimes:   newCallTimes(cfg.Timeouts),
 
 		// We're okay to lose some of the messages -- if we are already
 		// regenerating the table, we don't want to repeat it right away.
@@ -123,6 +126,10 @@ func (fuzzer *Fuzzer) enqueue(executor queue.Executor, req *queue.Request, flags
 
 func (fuzzer *Fuzzer) processResult(req *queue.Request, res *queue.Result, flags ProgFlags, attempt int) bool {
 	inTriage := flags&progInTriage > 0
+	if res.Info != nil {
+		// This needs to happen before triage b/c it may add slowness signal.
+		fuzzer.handleCallTimes(req, res.Info)
+	}
 	// Triage the program.
 	// We do it before unblocking the waiting threads because
 	// it may result it concurrent modification of req.Prog.
@@ -195,10 +202,16 @@ type Config struct {
 	NoMutateCalls  map[int]bool
 	FetchRawCover  bool
 	NewInputFilter func(call string) bool
+	// Use anomalously slow call executions as additional feedback signal.
+	SlownessSignal bool
+	// Used to account execution time of calls that did not finish.
+	Timeouts targets.Timeouts
 }
 
 func (fuzzer *Fuzzer) triageProgCall(p *prog.Prog, info *flatrpc.CallInfo, call int, triage *map[int]*triageCall) {
-	if info == nil {
+	if info == nil || info.Flags&flatrpc.CallFlagCached != 0 {
+		// Signal of cached calls is stale (e.g. prefix calls restored from a snapshot),
+		// they were triaged when they were actually executed.
 		return
 	}
 	prio := signalPrio(p, info, call)
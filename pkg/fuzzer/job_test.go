@@ -121,3 +121,30 @@ func TestDeflake(t *testing.T) {
 		})
 	}
 }
+
+func TestTriageCachedCalls(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64Fuzz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := target.Deserialize([]byte("test$res0()\ntest$res2()\n"), prog.NonStrict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fuzzer := &Fuzzer{
+		Config: &Config{NewInputFilter: func(call string) bool { return true }},
+		Cover:  newCover(),
+	}
+	info := []*flatrpc.CallInfo{
+		{Flags: flatrpc.CallFlagExecuted | flatrpc.CallFlagCached, Signal: []uint64{1, 2}},
+		{Flags: flatrpc.CallFlagExecuted, Signal: []uint64{3, 4}},
+	}
+	var triage map[int]*triageCall
+	for call, inf := range info {
+		fuzzer.triageProgCall(p, inf, call, &triage)
+	}
+	// Stale signal of the cached call is neither triaged nor added to max signal.
+	assert.Len(t, triage, 1)
+	assert.NotNil(t, triage[1])
+	assert.Equal(t, 2, fuzzer.Cover.CopyMaxSignal().Len())
+}
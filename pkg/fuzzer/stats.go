@@ -25,6 +25,7 @@ type Stats struct {
 	statExecHint            *stat.Val
 	statExecSeed            *stat.Val
 	statExecCollide         *stat.Val
+	statSlowCalls           *stat.Val
 }
 
 func newStats() Stats {
@@ -64,5 +65,7 @@ func newStats() Stats {
 			stat.Rate{}, stat.StackedGraph("exec")),
 		statExecCollide: stat.New("exec collide", "Executions of programs in collide mode",
 			stat.Rate{}, stat.StackedGraph("exec")),
+		statSlowCalls: stat.New("slow calls", "Executions of calls that were anomalously slow",
+			stat.Rate{}, stat.Link("/syscalls")),
 	}
 }
@@ -50,6 +50,9 @@ type BuildKernelConfig struct {
 	SysctlFile   string
 	KernelConfig []byte
 	BuildCPUs    int
+	// Cache and KernelCommit are passed to build.Params.
+	Cache        *build.Cache
+	KernelCommit string
 }
 
 func NewEnv(cfg *mgrconfig.Config, buildSem, testSem *Semaphore) (Env, error) {
@@ -155,6 +158,8 @@ func (env *env) BuildKernel(buildCfg *BuildKernelConfig) (
 		SysctlFile:   buildCfg.SysctlFile,
 		Config:       buildCfg.KernelConfig,
 		BuildCPUs:    buildCfg.BuildCPUs,
+		Cache:        buildCfg.Cache,
+		KernelCommit: buildCfg.KernelCommit,
 	}
 	details, err := build.Image(params)
 	if err != nil {
@@ -34,7 +34,7 @@ func (kconf *KConfig) Minimize(base, full *ConfigFile, pred func(*ConfigFile) (b
 			candidate.Set(cfg.Name, cfg.Value)
 		}
 		for _, cfg := range suspects {
-			candidate.Set(cfg, Yes)
+			candidate.Set(cfg, full.Value(cfg))
 		}
 		return candidate, suspects
 	}
@@ -59,14 +59,14 @@ func (kconf *KConfig) Minimize(base, full *ConfigFile, pred func(*ConfigFile) (b
 	config, suspects := diffToConfig(result)
 	if suspects != nil {
 		dt.Log("minimized to %d configs; suspects: %v", len(result), suspects)
-		kconf.writeSuspects(dt, suspects)
+		kconf.writeSuspects(dt, full, suspects)
 	}
 	return config, nil
 }
 
 func (kconf *KConfig) missingConfigs(base, full *ConfigFile) (tristate []string, other []*Config) {
 	for _, cfg := range full.Configs {
-		if cfg.Value == Yes && base.Value(cfg.Name) == No {
+		if (cfg.Value == Yes || cfg.Value == Mod) && base.Value(cfg.Name) == No {
 			tristate = append(tristate, cfg.Name)
 		} else if cfg.Value != No && cfg.Value != Yes && cfg.Value != Mod {
 			other = append(other, cfg)
@@ -118,12 +118,12 @@ func (kconf *KConfig) addDependencies(base, full *ConfigFile, configs []string)
 
 const CauseConfigFile = "cause.config"
 
-func (kconf *KConfig) writeSuspects(dt debugtracer.DebugTracer, suspects []string) {
+func (kconf *KConfig) writeSuspects(dt debugtracer.DebugTracer, full *ConfigFile, suspects []string) {
 	cf := &ConfigFile{
 		Map: make(map[string]*Config),
 	}
 	for _, cfg := range suspects {
-		cf.Set(cfg, Yes)
+		cf.Set(cfg, full.Value(cfg))
 	}
 	dt.SaveFile(CauseConfigFile, cf.Serialize())
 }
@@ -23,6 +23,8 @@ config C
 config D
 config I
 config S
+config M
+	tristate "module"
 
 menuconfig HAMRADIO
 	depends on NET && !S390
@@ -50,6 +52,7 @@ CONFIG_S="foo"
 CONFIG_HAMRADIO=y
 CONFIG_AX25=y
 CONFIG_ROSE=y
+CONFIG_M=m
 `
 	)
 	type Test struct {
@@ -91,6 +94,18 @@ CONFIG_S="foo"
 CONFIG_AX25=y
 CONFIG_HAMRADIO=y
 CONFIG_ROSE=y
+`,
+		},
+		{
+			// Options enabled as modules are minimized as well.
+			pred: func(cf *ConfigFile) (bool, error) {
+				return cf.Value("M") == Mod, nil
+			},
+			result: `
+CONFIG_A=y
+CONFIG_I=42
+CONFIG_S="foo"
+CONFIG_M=m
 `,
 		},
 		{
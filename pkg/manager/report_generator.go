@@ -68,3 +68,12 @@ func CoverToPCs(cfg *mgrconfig.Config, cov []uint64) []uint64 {
 	}
 	return pcs
 }
+
+// PCsToCover converts coverage callback PCs back to the form stored in the corpus (the inverse of CoverToPCs).
+func PCsToCover(cfg *mgrconfig.Config, pcs []uint64) []uint64 {
+	cov := make([]uint64, 0, len(pcs))
+	for _, pc := range pcs {
+		cov = append(cov, backend.NextInstructionPC(cfg.SysTarget, cfg.Type, pc))
+	}
+	return cov
+}
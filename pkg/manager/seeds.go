@@ -28,9 +28,15 @@ type Seeds struct {
 }
 
 func LoadSeeds(cfg *mgrconfig.Config, immutable bool) Seeds {
+	return LoadCorpus(cfg, filepath.Join(cfg.Workdir, "corpus.db"), immutable, true)
+}
+
+// LoadCorpus loads programs from the corpus database file.
+// If seeds is set, the test programs from sys/OS/test are added as well.
+func LoadCorpus(cfg *mgrconfig.Config, file string, immutable, seeds bool) Seeds {
 	var info Seeds
 	var err error
-	info.CorpusDB, err = db.Open(filepath.Join(cfg.Workdir, "corpus.db"), !immutable)
+	info.CorpusDB, err = db.Open(file, !immutable)
 	if err != nil {
 		if info.CorpusDB == nil {
 			log.Fatalf("failed to open corpus database: %v", err)
@@ -71,7 +77,7 @@ func LoadSeeds(cfg *mgrconfig.Config, immutable bool) Seeds {
 			}
 		}
 		seedDir := filepath.Join(cfg.Syzkaller, "sys", cfg.TargetOS, "test")
-		if osutil.IsExist(seedDir) {
+		if seeds && osutil.IsExist(seedDir) {
 			seeds, err := os.ReadDir(seedDir)
 			if err != nil {
 				log.Fatalf("failed to read seeds dir: %v", err)
@@ -159,6 +159,12 @@ type Config struct {
 	// Disabled by default as it slows down fuzzing.
 	RawCover bool `json:"raw_cover"`
 
+	// Directory of a local coverage database (optional).
+	// If set, the manager saves the line coverage of the corpus there every few hours
+	// (the namespace is the manager name), the database can be used to build coverage
+	// heatmaps with syz-cover -heatmap. Requires cover.
+	CoverLocalDB string `json:"cover_local_db,omitempty"`
+
 	// Reproduce, localize and minimize crashers (default: true).
 	Reproduce bool `json:"reproduce"`
 
@@ -235,6 +241,35 @@ type Experimental struct {
 
 	// Use automatically (auto) generated or manually (manual) written descriptions or any (any) (default: manual)
 	DescriptionsMode string `json:"descriptions_mode"`
+
+	// Use anomalously slow syscall executions as an additional fuzzing feedback signal (default: false).
+	// This helps to find soft lockups and algorithmic complexity issues that don't crash the kernel.
+	SlownessSignal bool `json:"slowness_signal"`
+
+	// Reuse VMs across fuzzing sessions instead of rebooting them every time the VM running
+	// time expires (default: false). A VM is reused only if the session has not crashed,
+	// the old syz-executor processes are killed (requires pkill/pgrep in the image),
+	// the syz-executor binary still runs and reports the expected revisions, and
+	// the kernel is verified to be healthy (not tainted by warnings/oopses, no kernel error reports).
+	// This saves VM time on targets with slow boot (Android, GCE).
+	ReuseVMs bool `json:"reuse_vms"`
+
+	// Maximum lifetime of a reused VM in minutes (default: 0, unlimited).
+	VMMaxLifetime int `json:"vm_max_lifetime"`
+
+	// Recycle a reused VM when its available memory drops by more than this percent
+	// relative to the first health check, which usually means memory leaks (default: 20).
+	VMMaxMemoryDrop int `json:"vm_max_memory_drop"`
+
+	// In snapshot mode, take additional snapshots after program prefixes that are common
+	// to many executed programs (e.g. socket creation and setup), and execute programs with
+	// such prefixes starting from these snapshots. The value is the maximum number of prefix
+	// snapshots kept per VM (default: 0, disabled).
+	// Note: results (errno, signal, coverage) of the prefix calls are not re-collected, they are
+	// copied from the execution that took the snapshot. So flaky signal of the prefix calls is never
+	// observed, and such cached calls are not triaged. Programs that need precise results
+	// of all calls (triage, output collection) are always executed from the base snapshot.
+	SnapshotPrefixes int `json:"snapshot_prefixes"`
 }
 
 type Subsystem struct {
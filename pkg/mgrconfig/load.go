@@ -97,6 +97,7 @@ func defaultValues() *Config {
 			RemoteCover:      true,
 			CoverEdges:       true,
 			DescriptionsMode: manualDescriptions,
+			VMMaxMemoryDrop:  20,
 		},
 	}
 }
@@ -163,6 +164,12 @@ func Complete(cfg *Config) error {
 		}
 		cfg.Image = osutil.Abs(cfg.Image)
 	}
+	if cfg.CoverLocalDB != "" {
+		if !cfg.Cover {
+			return fmt.Errorf("cover_local_db requires cover")
+		}
+		cfg.CoverLocalDB = osutil.Abs(cfg.CoverLocalDB)
+	}
 	if err := cfg.completeBinaries(); err != nil {
 		return err
 	}
@@ -186,6 +193,19 @@ func Complete(cfg *Config) error {
 	if cfg.FuzzingVMs < 0 {
 		return fmt.Errorf("fuzzing_vms cannot be less than 0")
 	}
+	if cfg.Experimental.ReuseVMs && cfg.TargetOS != targets.Linux {
+		return fmt.Errorf("reuse_vms is only supported for linux")
+	}
+	if cfg.Experimental.VMMaxLifetime < 0 || cfg.Experimental.VMMaxMemoryDrop < 0 ||
+		cfg.Experimental.VMMaxMemoryDrop > 100 {
+		return fmt.Errorf("bad vm_max_lifetime/vm_max_memory_drop values")
+	}
+	if cfg.Experimental.SnapshotPrefixes < 0 {
+		return fmt.Errorf("snapshot_prefixes cannot be less than 0")
+	}
+	if cfg.Experimental.SnapshotPrefixes != 0 && !cfg.Snapshot {
+		return fmt.Errorf("snapshot_prefixes requires snapshot mode")
+	}
 
 	var err error
 	cfg.Syscalls, err = ParseEnabledSyscalls(cfg.Target, cfg.EnabledSyscalls, cfg.DisabledSyscalls,
@@ -131,23 +131,24 @@ func ctorLinux(cfg *config) (reporterImpl, []string, error) {
 		[]byte("FAULT_INJECTION: forcing a failure"),
 		[]byte("FAULT_FLAG_ALLOW_RETRY missing"),
 	}
-	suppressions := []string{
-		"panic: failed to start executor binary",
-		"panic: executor failed: pthread_create failed",
-		"panic: failed to create temp dir",
-		"fatal error: unexpected signal during runtime execution", // presubmably OOM turned into SIGBUS
-		"signal SIGBUS: bus error",                                // presubmably OOM turned into SIGBUS
-		"SYZFAIL: SIGBUS",
-		"Out of memory: Kill process .* \\(syz-executor\\)",
-		"Out of memory: Kill process .* \\(sshd\\)",
-		"Killed process .* \\(syz-executor\\)",
-		"Killed process .* \\(sshd\\)",
-		"lowmemorykiller: Killing 'syz-executor'",
-		"lowmemorykiller: Killing 'sshd'",
-		"INIT: PANIC: segmentation violation!",
-		"\\*\\*\\* stack smashing detected \\*\\*\\*: terminated",
-	}
-	return ctx, suppressions, nil
+	return ctx, linuxSuppressions, nil
+}
+
+var linuxSuppressions = []string{
+	"panic: failed to start executor binary",
+	"panic: executor failed: pthread_create failed",
+	"panic: failed to create temp dir",
+	"fatal error: unexpected signal during runtime execution", // presubmably OOM turned into SIGBUS
+	"signal SIGBUS: bus error",                                // presubmably OOM turned into SIGBUS
+	"SYZFAIL: SIGBUS",
+	"Out of memory: Kill process .* \\(syz-executor\\)",
+	"Out of memory: Kill process .* \\(sshd\\)",
+	"Killed process .* \\(syz-executor\\)",
+	"Killed process .* \\(sshd\\)",
+	"lowmemorykiller: Killing 'syz-executor'",
+	"lowmemorykiller: Killing 'sshd'",
+	"INIT: PANIC: segmentation violation!",
+	"\\*\\*\\* stack smashing detected \\*\\*\\*: terminated",
 }
 
 const contextConsole = "console"
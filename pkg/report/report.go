@@ -295,6 +295,61 @@ func IsSuppressed(reporter *Reporter, output []byte) bool {
 		bytes.Contains(output, gceConsoleHangup)
 }
 
+// CrashSignature describes kernel log messages of a particular crash. It is coarser than Parse,
+// but can be used outside of Go (e.g. in regression tests generated with csource.WriteKselftest).
+type CrashSignature struct {
+	// A kernel log message belongs to the crash if it contains all of these strings.
+	Match []string
+	// Messages that match any of these regular expressions are not crashes.
+	Suppressions []string
+}
+
+var (
+	crashSignatureFrameRe    = regexp.MustCompile(`\+0x[0-9a-f]+/0x[0-9a-f]+$`)
+	crashSignatureVolatileRe = regexp.MustCompile(`^(0x)?[0-9a-f]{8,}[:,]?$|^[0-9]+[:,]?$|:[0-9]+$|[\[\]]`)
+)
+
+// ExtractCrashSignature returns the signature of the crash in the report:
+// words of the first line that starts a kernel crash report (e.g. "BUG: KASAN: use-after-free in foo")
+// except for the ones that vary between crashes of the same bug (addresses, pids, source lines).
+func ExtractCrashSignature(OS string, report []byte) (*CrashSignature, error) {
+	if OS != targets.Linux {
+		return nil, fmt.Errorf("crash signatures are not supported for %v", OS)
+	}
+	// Common oopses are produced by syzkaller itself and Go programs, not by the kernel,
+	// and reboots are not visible in the kernel log.
+	skip := make(map[*oops]bool)
+	for _, oops := range commonOopses {
+		skip[oops] = true
+	}
+	for _, oops := range linuxOopses {
+		reboot := true
+		for _, format := range oops.formats {
+			reboot = reboot && format.reportType == crash.UnexpectedReboot
+		}
+		skip[oops] = skip[oops] || reboot
+	}
+	for _, line := range bytes.Split(report, []byte("\n")) {
+		for _, oops := range linuxOopses {
+			if skip[oops] || !bytes.Contains(line, oops.header) {
+				continue
+			}
+			sig := &CrashSignature{
+				Suppressions: linuxSuppressions,
+			}
+			for _, word := range strings.Fields(string(line)) {
+				word = crashSignatureFrameRe.ReplaceAllString(word, "")
+				if word == "" || crashSignatureVolatileRe.MatchString(word) {
+					continue
+				}
+				sig.Match = append(sig.Match, word)
+			}
+			return sig, nil
+		}
+	}
+	return nil, fmt.Errorf("no kernel crash found in the report")
+}
+
 // ParseAll returns all successive reports in output.
 func ParseAll(reporter *Reporter, output []byte) (reports []*Report) {
 	skipPos := 0
@@ -474,3 +474,30 @@ BCDEF`), Truncate([]byte(`0123456789ABCDEF`), 0, 5))
 
 DEF`), Truncate([]byte(`0123456789ABCDEF`), 4, 3))
 }
+
+func TestExtractCrashSignature(t *testing.T) {
+	sig, err := ExtractCrashSignature(targets.Linux, []byte(`
+[   52.197385][ T5120] ==================================================================
+[   52.197403][ T5120] BUG: KASAN: slab-use-after-free in ext4_xattr_inode_dec_ref fs/ext4/xattr.c:1052 [inline]
+[   52.197419][ T5120] Read of size 4 at addr ffff88807a4c3b10 by task syz-executor/5120
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"BUG:", "KASAN:", "slab-use-after-free", "in", "ext4_xattr_inode_dec_ref"}, sig.Match)
+	assert.Contains(t, sig.Suppressions, "SYZFAIL: SIGBUS")
+
+	sig, err = ExtractCrashSignature(targets.Linux, []byte(`
+WARNING: CPU: 1 PID: 5120 at fs/ext4/xattr.c:1052 ext4_xattr_inode_dec_ref_all+0x6f1/0x9b0 fs/ext4/xattr.c:1182
+Modules linked in:
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"WARNING:", "CPU:", "PID:", "at", "ext4_xattr_inode_dec_ref_all"}, sig.Match)
+
+	_, err = ExtractCrashSignature(targets.Linux, []byte("SYZFAIL: something\nBooting the kernel.\n"))
+	assert.Error(t, err)
+	_, err = ExtractCrashSignature(targets.Fuchsia, []byte("BUG: foo"))
+	assert.Error(t, err)
+}
@@ -8,6 +8,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
 	"slices"
 	"sort"
 	"strings"
@@ -41,6 +44,11 @@ type Config struct {
 	// Disabled for gVisor/Starnix which are not Linux.
 	FilterSignal      bool
 	PrintMachineCheck bool
+	// If set, machine check result is saved to this file and diffed against the previous run.
+	MachineCheckFile string
+	// If set, the check result the current diff is relative to is kept in this file
+	// until MachineCheckDiffReported is called, so that a not yet reported diff survives restarts.
+	MachineCheckBaseFile string
 	// Abort early on syz-executor not replying to requests and print extra debugging information.
 	DebugTimeouts bool
 	Procs         int
@@ -66,6 +74,14 @@ type Server interface {
 	ShutdownInstance(id int, crashed bool, extraExecs ...report.ExecutorInfo) ([]ExecRecord, []byte)
 	StopFuzzing(id int)
 	DistributeSignalDelta(plus signal.Signal)
+	// MachineCheckDiff returns changes in the machine check result relative to the previous run
+	// (nil if the check is not done yet or there is no previous result).
+	MachineCheckDiff() *vminfo.CheckDiff
+	// MachineCheckDiffReported says that the diff is reported, so the next diff is relative to this run.
+	MachineCheckDiffReported() error
+	// ModuleStats returns per-module coverage canonicalization diagnostics
+	// (nil if the check is not done yet).
+	ModuleStats() []cover.ModuleStat
 }
 
 type server struct {
@@ -84,6 +100,7 @@ type server struct {
 	setupFeatures    flatrpc.Feature
 	canonicalModules *cover.Canonicalizer
 	coverFilter      []uint64
+	checkDiff        atomic.Pointer[vminfo.CheckDiff]
 
 	mu             sync.Mutex
 	runners        map[int]*Runner
@@ -127,15 +144,26 @@ func New(cfg *mgrconfig.Config, mgr Manager, debug bool) (Server, error) {
 		// gVisor coverage is not a trace, so producing edges won't work.
 		UseCoverEdges: cfg.Experimental.CoverEdges && cfg.Type != targets.GVisor,
 		// gVisor/Starnix are not Linux, so filtering against Linux ranges won't work.
-		FilterSignal:      cfg.Type != targets.GVisor && cfg.Type != targets.Starnix,
-		PrintMachineCheck: true,
-		Procs:             cfg.Procs,
-		Slowdown:          cfg.Timeouts.Slowdown,
-		pcBase:            pcBase,
-		localModules:      cfg.LocalModules,
+		FilterSignal:         cfg.Type != targets.GVisor && cfg.Type != targets.Starnix,
+		PrintMachineCheck:    true,
+		MachineCheckFile:     filepath.Join(cfg.Workdir, "machine_check.json"),
+		MachineCheckBaseFile: machineCheckBaseFile(cfg),
+		Procs:                cfg.Procs,
+		Slowdown:             cfg.Timeouts.Slowdown,
+		pcBase:               pcBase,
+		localModules:         cfg.LocalModules,
 	}, mgr), nil
 }
 
+// machineCheckBaseFile returns the base file only if the diff is reported to the dashboard
+// (otherwise nobody resets the base and the diff would grow forever).
+func machineCheckBaseFile(cfg *mgrconfig.Config) string {
+	if cfg.DashboardAddr == "" || cfg.DashboardOnlyRepro {
+		return ""
+	}
+	return filepath.Join(cfg.Workdir, "machine_check_base.json")
+}
+
 func newImpl(ctx context.Context, cfg *Config, mgr Manager) *server {
 	// Note that we use VMArch, rather than Arch. We need the kernel address ranges and bitness.
 	sysTarget := targets.Get(cfg.Target.OS, cfg.VMArch)
@@ -260,7 +288,7 @@ func (serv *server) handleMachineInfo(infoReq *flatrpc.InfoRequestRawT) (handsha
 			infoReq.Error = err.Error()
 		}
 	}
-	modules = backend.FixModules(serv.cfg.localModules, modules, serv.cfg.pcBase)
+	modules, mismatches := backend.FixModules(serv.cfg.localModules, modules, serv.cfg.pcBase)
 	if infoReq.Error != "" {
 		log.Logf(0, "machine check failed: %v", infoReq.Error)
 		serv.checkFailures++
@@ -289,7 +317,10 @@ func (serv *server) handleMachineInfo(infoReq *flatrpc.InfoRequestRawT) (handsha
 			}
 		}()
 	})
-	canonicalizer := serv.canonicalModules.NewInstance(modules)
+	for _, mismatch := range mismatches {
+		log.Logf(1, "module %v: %v, discarding its coverage", mismatch.Name, mismatch.Reason)
+	}
+	canonicalizer := serv.canonicalModules.NewInstance(modules, mismatches)
 	return handshakeResult{
 		CovFilter:     canonicalizer.Decanonicalize(serv.coverFilter),
 		MachineInfo:   machineInfo,
@@ -344,6 +375,11 @@ func (serv *server) runCheck(checkFilesInfo []*flatrpc.FileInfo, checkFeatureInf
 	if checkErr != nil {
 		return checkErr
 	}
+	if serv.cfg.MachineCheckFile != "" {
+		if err := serv.diffMachineCheck(enabledCalls, disabledCalls, transitivelyDisabled, features); err != nil {
+			log.Logf(0, "failed to diff machine check: %v", err)
+		}
+	}
 	enabledFeatures := features.Enabled()
 	serv.setupFeatures = features.NeedSetup()
 	newSource := serv.mgr.MachineChecked(enabledFeatures, enabledCalls)
@@ -352,6 +388,64 @@ func (serv *server) runCheck(checkFilesInfo []*flatrpc.FileInfo, checkFeatureInf
 	return nil
 }
 
+func (serv *server) diffMachineCheck(enabledCalls map[*prog.Syscall]bool,
+	disabledCalls, transitivelyDisabled map[*prog.Syscall]string, features vminfo.Features) error {
+	allDisabled := maps.Clone(disabledCalls)
+	maps.Copy(allDisabled, transitivelyDisabled)
+	res := vminfo.NewCheckResult(enabledCalls, allDisabled, features)
+	var prev *vminfo.CheckResult
+	var err error
+	if serv.cfg.MachineCheckBaseFile != "" {
+		// The diff against the base is not reported yet, so it's accumulated with the new changes.
+		prev, err = vminfo.LoadCheckResult(serv.cfg.MachineCheckBaseFile)
+		if err != nil {
+			log.Logf(0, "failed to load base machine check: %v", err)
+		}
+	}
+	if prev == nil {
+		prev, err = vminfo.LoadCheckResult(serv.cfg.MachineCheckFile)
+		if err != nil {
+			log.Logf(0, "failed to load previous machine check: %v", err)
+		}
+		if prev != nil && serv.cfg.MachineCheckBaseFile != "" {
+			if err := prev.Save(serv.cfg.MachineCheckBaseFile); err != nil {
+				return err
+			}
+		}
+	}
+	if prev != nil {
+		diff := res.Diff(prev)
+		if !diff.Empty() {
+			log.Logf(0, "machine check changed since the previous run:\n%s", diff)
+		}
+		serv.checkDiff.Store(diff)
+	}
+	return res.Save(serv.cfg.MachineCheckFile)
+}
+
+func (serv *server) MachineCheckDiff() *vminfo.CheckDiff {
+	return serv.checkDiff.Load()
+}
+
+func (serv *server) MachineCheckDiffReported() error {
+	if serv.cfg.MachineCheckBaseFile == "" {
+		return nil
+	}
+	err := os.Remove(serv.cfg.MachineCheckBaseFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (serv *server) ModuleStats() []cover.ModuleStat {
+	// canonicalModules is set before the check is done.
+	if !serv.checkDone.Load() {
+		return nil
+	}
+	return serv.canonicalModules.ModuleStats()
+}
+
 func (serv *server) printMachineCheck(checkFilesInfo []*flatrpc.FileInfo, enabledCalls map[*prog.Syscall]bool,
 	disabledCalls, transitivelyDisabled map[*prog.Syscall]string, features vminfo.Features) {
 	buf := new(bytes.Buffer)
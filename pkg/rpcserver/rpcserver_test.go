@@ -5,6 +5,7 @@ package rpcserver
 
 import (
 	"net"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -12,6 +13,7 @@ import (
 	"github.com/google/syzkaller/pkg/flatrpc"
 	"github.com/google/syzkaller/pkg/mgrconfig"
 	"github.com/google/syzkaller/pkg/rpcserver/mocks"
+	"github.com/google/syzkaller/pkg/vminfo"
 	"github.com/google/syzkaller/prog"
 	"github.com/google/syzkaller/sys/targets"
 )
@@ -207,3 +209,37 @@ func TestHandleConn(t *testing.T) {
 		})
 	}
 }
+
+func TestMachineCheckDiff(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &Config{
+		MachineCheckFile:     filepath.Join(dir, "machine_check.json"),
+		MachineCheckBaseFile: filepath.Join(dir, "machine_check_base.json"),
+	}
+	open := &prog.Syscall{Name: "open"}
+	check := func(enabled bool) *server {
+		serv := &server{cfg: cfg}
+		var err error
+		if enabled {
+			err = serv.diffMachineCheck(map[*prog.Syscall]bool{open: true}, nil, nil, nil)
+		} else {
+			err = serv.diffMachineCheck(nil, map[*prog.Syscall]string{open: "disabled"}, nil, nil)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		return serv
+	}
+	assert.Nil(t, check(true).MachineCheckDiff())
+	disabled := &vminfo.CheckDiff{Disabled: []vminfo.CallChange{{Name: "open", Reason: "disabled"}}}
+	assert.Equal(t, disabled, check(false).MachineCheckDiff())
+	// The manager restarts before the diff is reported, the diff is still relative to the first run.
+	serv := check(false)
+	assert.Equal(t, disabled, serv.MachineCheckDiff())
+	assert.NoError(t, serv.MachineCheckDiffReported())
+	assert.True(t, check(false).MachineCheckDiff().Empty())
+	// Changes after the report accumulate again until the next report.
+	assert.Equal(t, &vminfo.CheckDiff{Enabled: []vminfo.CallChange{{Name: "open", Reason: "disabled"}}},
+		check(true).MachineCheckDiff())
+	assert.True(t, check(false).MachineCheckDiff().Empty())
+}
@@ -8,8 +8,10 @@ import (
 	"context"
 	"encoding/binary"
 	"encoding/hex"
+	"errors"
 	"flag"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -19,13 +21,18 @@ import (
 	"github.com/google/syzkaller/pkg/csource"
 	"github.com/google/syzkaller/pkg/flatrpc"
 	"github.com/google/syzkaller/pkg/fuzzer/queue"
+	"github.com/google/syzkaller/pkg/mgrconfig"
 	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/report"
 	"github.com/google/syzkaller/pkg/rpcserver"
+	"github.com/google/syzkaller/pkg/signal"
 	"github.com/google/syzkaller/pkg/testutil"
 	"github.com/google/syzkaller/pkg/vminfo"
 	"github.com/google/syzkaller/prog"
 	"github.com/google/syzkaller/sys/targets"
 	_ "github.com/google/syzkaller/sys/test/gen" // pull in the test target
+	"github.com/google/syzkaller/vm"
+	"github.com/google/syzkaller/vm/process"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -122,6 +129,153 @@ func test(t *testing.T, sysTarget *targets.Target) {
 	}
 }
 
+func TestProcessVM(t *testing.T) {
+	// End-to-end test that runs executor in the process VM type
+	// the same way syz-manager runs it in VMs.
+	if runtime.GOOS != targets.Linux {
+		t.Skipf("the process VM type runs executor in namespaces only on linux")
+	}
+	t.Parallel()
+	sysTarget := targets.Get(targets.TestOS, targets.TestArch64Fork)
+	target, err := prog.GetTarget(sysTarget.OS, sysTarget.Arch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	executor := csource.BuildExecutor(t, target, "../../", "-fsanitize-coverage=trace-pc")
+	cfg := &mgrconfig.Config{
+		Derived: mgrconfig.Derived{
+			Target:       target,
+			TargetOS:     sysTarget.OS,
+			TargetArch:   sysTarget.Arch,
+			TargetVMArch: sysTarget.Arch,
+			SysTarget:    sysTarget,
+			Timeouts:     sysTarget.Timeouts(10),
+		},
+		Name:    "runtest",
+		Workdir: t.TempDir(),
+		Type:    "process",
+		VM:      []byte(`{}`),
+		Sandbox: "none",
+		Cover:   true,
+		Procs:   4,
+		RPC:     ":0",
+	}
+	pool, err := vm.Create(cfg, *flagDebug)
+	if errors.Is(err, process.ErrNoNamespaces) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	// The test OS does not have a crash reporter, and the process VM runs on the host linux kernel.
+	reporter, err := report.NewReporter(&mgrconfig.Config{
+		Derived: mgrconfig.Derived{
+			TargetOS:     targets.Linux,
+			TargetArch:   runtime.GOARCH,
+			TargetVMArch: runtime.GOARCH,
+			SysTarget:    targets.Get(targets.Linux, runtime.GOARCH),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := make(map[*prog.Syscall]bool)
+	for _, call := range target.Syscalls {
+		calls[call] = true
+	}
+	ctx := &Context{
+		Dir:    filepath.Join("..", "..", "sys", target.OS, targets.TestOS),
+		Target: target,
+		Tests:  *flagFilter,
+		EnabledCalls: map[string]map[*prog.Syscall]bool{
+			"": calls,
+		},
+		LogFunc: func(text string) {
+			t.Helper()
+			t.Log(text)
+		},
+		Retries: 7,
+		Verbose: true,
+		Debug:   *flagDebug,
+	}
+	ctx.Init()
+	mgr := &processVMManager{source: ctx}
+	serv, err := rpcserver.New(cfg, mgr, *flagDebug)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mgr.serv = serv
+	if err := serv.Listen(); err != nil {
+		t.Fatal(err)
+	}
+	defer serv.Close()
+
+	inst, err := pool.Create(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer inst.Close()
+	fwdAddr, err := inst.Forward(serv.Port())
+	if err != nil {
+		t.Fatal(err)
+	}
+	host, port, err := net.SplitHostPort(fwdAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	executorBin, err := inst.Copy(executor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	injectExec := make(chan bool, 10)
+	serv.CreateInstance(inst.Index(), injectExec, nil)
+	defer serv.ShutdownInstance(inst.Index(), true)
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errc := make(chan error, 1)
+	go func() {
+		errc <- ctx.Run(runCtx)
+		cancel()
+	}()
+	cmd := fmt.Sprintf("%v runner %v %v %v", executorBin, inst.Index(), host, port)
+	output, rep, err := inst.Run(cfg.Timeouts.VMRunningTime, reporter, cmd,
+		vm.ExitTimeout, vm.StopContext(runCtx), vm.InjectExecuting(injectExec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep != nil {
+		t.Fatalf("got crash %q:\n%s", rep.Title, output)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+}
+
+type processVMManager struct {
+	source queue.Source
+	serv   rpcserver.Server
+}
+
+func (mgr *processVMManager) MachineChecked(features flatrpc.Feature,
+	syscalls map[*prog.Syscall]bool) queue.Source {
+	mgr.serv.TriagedCorpus()
+	return mgr.source
+}
+
+func (mgr *processVMManager) BugFrames() ([]string, []string) {
+	return nil, nil
+}
+
+func (mgr *processVMManager) MaxSignal() signal.Signal {
+	return nil
+}
+
+func (mgr *processVMManager) CoverageFilter(modules []*vminfo.KernelModule) []uint64 {
+	return nil
+}
+
 func TestCover(t *testing.T) {
 	// End-to-end test for coverage/signal/comparisons collection.
 	// We inject given blobs into KCOV buffer using syz_inject_cover,
@@ -236,6 +236,108 @@ func (git *git) initRepo(reason error) error {
 	return nil
 }
 
+func (git *git) Revert(commit string) (bool, error) {
+	if _, err := git.git("revert", "--no-commit", commit); err == nil {
+		return false, nil
+	}
+	// Restore the conflicting files to their HEAD state and keep the rest of the revert.
+	output, err := git.git("diff", "--name-only", "--diff-filter=U")
+	if err != nil {
+		return false, err
+	}
+	for _, file := range strings.Fields(string(output)) {
+		if _, err := git.git("checkout", "HEAD", "--", file); err != nil {
+			// The file is not present in HEAD.
+			if _, err := git.git("rm", "--force", "--quiet", "--", file); err != nil {
+				return false, err
+			}
+		}
+	}
+	if _, err := git.git("revert", "--quit"); err != nil {
+		return false, err
+	}
+	if _, err := git.git("diff", "--cached", "--quiet", "HEAD"); err == nil {
+		return false, fmt.Errorf("failed to revert %v: no changes revert cleanly", commit)
+	}
+	return true, nil
+}
+
+func (git *git) FunctionChanges(base, head, file, function string) ([]*FunctionChange, error) {
+	output, err := git.git("log", "--no-merges", "--format=%H", base+".."+head, "--", file)
+	if err != nil {
+		return nil, err
+	}
+	re, err := regexp.Compile(`\b` + regexp.QuoteMeta(function) + `\b`)
+	if err != nil {
+		return nil, err
+	}
+	var changes []*FunctionChange
+	for _, hash := range strings.Fields(string(output)) {
+		output, err := git.git("show", "--format=%B", "--no-color", hash, "--", file)
+		if err != nil {
+			return nil, err
+		}
+		msg, patch, _ := strings.Cut(string(output), "\ndiff --git ")
+		if !patchChangesFunction(patch, re) {
+			continue
+		}
+		com, err := git.getCommit(hash)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, &FunctionChange{
+			Commit:  com,
+			Message: strings.TrimSpace(msg),
+			Patch:   "diff --git " + patch,
+		})
+	}
+	return changes, nil
+}
+
+// patchChangesFunction checks whether any hunk of the patch is inside the function
+// (as determined by the hunk header) or changes the lines that mention the function.
+func patchChangesFunction(patch string, function *regexp.Regexp) bool {
+	for _, line := range strings.Split(patch, "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			// The header looks like "@@ -1,2 +1,3 @@ int foo(void)".
+			parts := strings.SplitN(line, "@@", 3)
+			if len(parts) == 3 && function.MatchString(parts[2]) {
+				return true
+			}
+		case strings.HasPrefix(line, "+++") || strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-"):
+			if function.MatchString(line) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (git *git) ListRefs(repo, pattern string) (map[string]string, error) {
+	output, err := git.git("ls-remote", "--refs", repo, pattern)
+	if err != nil {
+		return nil, err
+	}
+	refs := make(map[string]string)
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 {
+			refs[fields[1]] = fields[0]
+		}
+	}
+	return refs, nil
+}
+
+func (git *git) ChangedFiles(base, head string) ([]string, error) {
+	output, err := git.git("diff", "--name-only", base, head)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(output)), nil
+}
+
 func (git *git) Contains(commit string) (bool, error) {
 	_, err := git.git("merge-base", "--is-ancestor", commit, "HEAD")
 	return err == nil, nil
@@ -8,10 +8,12 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/syzkaller/pkg/debugtracer"
+	"github.com/stretchr/testify/assert"
 )
 
 func init() {
@@ -401,3 +403,143 @@ func TestBisect(t *testing.T) {
 		}
 	}
 }
+
+func TestRevert(t *testing.T) {
+	t.Parallel()
+	repo := MakeTestRepo(t, t.TempDir())
+	writeFiles := func(files map[string]string) {
+		for name, data := range files {
+			if err := os.WriteFile(filepath.Join(repo.Dir, name), []byte(data), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	checkFiles := func(files map[string]string) {
+		t.Helper()
+		for name, want := range files {
+			data, err := os.ReadFile(filepath.Join(repo.Dir, name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != want {
+				t.Fatalf("file %v: got %q, want %q", name, data, want)
+			}
+		}
+	}
+	commit := func(title string, files map[string]string) *Commit {
+		writeFiles(files)
+		repo.Git("add", ".")
+		repo.Git("commit", "-m", title)
+		com, err := repo.repo.HeadCommit()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return com
+	}
+	commit("base", map[string]string{"a": "1\n", "b": "1\n"})
+	culprit := commit("culprit", map[string]string{"a": "2\n", "b": "2\n"})
+	later := commit("later", map[string]string{"a": "3\n"})
+
+	// The later commit reverts cleanly.
+	partial, err := repo.repo.Revert(later.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if partial {
+		t.Fatalf("clean revert is reported as partial")
+	}
+	checkFiles(map[string]string{"a": "2\n", "b": "2\n"})
+	repo.Git("reset", "--hard")
+
+	// Changes to a conflict with the later commit, so only b is reverted.
+	partial, err = repo.repo.Revert(culprit.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !partial {
+		t.Fatalf("conflicting revert is not reported as partial")
+	}
+	checkFiles(map[string]string{"a": "3\n", "b": "1\n"})
+	repo.Git("reset", "--hard")
+
+	// Now nothing reverts cleanly.
+	commit("even later", map[string]string{"b": "4\n"})
+	if _, err := repo.repo.Revert(culprit.Hash); err == nil {
+		t.Fatalf("revert with all files conflicting did not fail")
+	}
+	repo.Git("reset", "--hard")
+	checkFiles(map[string]string{"a": "3\n", "b": "4\n"})
+}
+
+func TestFunctionChanges(t *testing.T) {
+	t.Parallel()
+	repo := MakeTestRepo(t, t.TempDir())
+	const (
+		foo = "int foo(int x)\n{\n\tint a = 1;\n\tint b = 2;\n\tint c = 3;\n\tint d = 4;\n\treturn x;\n}\n"
+		bar = "\nint bar(void)\n{\n\tint a = 1;\n\tint b = 2;\n\tint c = 3;\n\tint d = 4;\n\treturn foo(0);\n}\n"
+	)
+	commit := func(title, file, data string) *Commit {
+		if err := os.WriteFile(filepath.Join(repo.Dir, file), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		repo.Git("add", ".")
+		repo.Git("commit", "-m", title)
+		com, err := repo.repo.HeadCommit()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return com
+	}
+	base := commit("base", "a.c", foo+bar)
+	commit("change bar", "a.c", foo+strings.Replace(bar, "int d = 4", "int d = 5", 1))
+	fix := commit("fix foo\n\nLonger description.", "a.c",
+		strings.Replace(foo, "int d = 4", "int d = 5", 1)+strings.Replace(bar, "int d = 4", "int d = 5", 1))
+	commit("change other file", "b.c", foo)
+	head, err := repo.repo.HeadCommit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err := repo.repo.FunctionChanges(base.Hash, head.Hash, "a.c", "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 {
+		t.Fatalf("got %v changes, want 1", len(changes))
+	}
+	change := changes[0]
+	assert.Equal(t, fix.Hash, change.Commit.Hash)
+	assert.Equal(t, "fix foo\n\nLonger description.", change.Message)
+	assert.Contains(t, change.Patch, "+\tint d = 5;")
+}
+
+func TestListRefsAndChangedFiles(t *testing.T) {
+	t.Parallel()
+	baseDir := t.TempDir()
+	remote := MakeTestRepo(t, filepath.Join(baseDir, "remote"))
+	remote.CommitFileChange("master", "base")
+	base, err := remote.repo.HeadCommit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote.Git("checkout", "-b", "review/series1")
+	remote.CommitFileChange("review/series1", "change")
+	series, err := remote.repo.HeadCommit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote.Git("checkout", "-b", "other")
+	remote.CommitFileChange("other", "change")
+
+	repo := MakeTestRepo(t, filepath.Join(baseDir, "local"))
+	refs, err := repo.repo.ListRefs(remote.Dir, "refs/heads/review/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]string{"refs/heads/review/series1": series.Hash}, refs)
+
+	files, err := remote.repo.ChangedFiles(base.Hash, series.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"file"}, files)
+}
@@ -340,6 +340,46 @@ func (ctx *linux) Minimize(target *targets.Target, original, baseline []byte, ty
 	return minimizeCtx.getConfig(), nil
 }
 
+func (ctx *linux) BisectConfig(target *targets.Target, original, baseline []byte, dt debugtracer.DebugTracer,
+	pred func(test []byte) (BisectResult, error)) ([]byte, []string, error) {
+	kconf, err := kconfig.Parse(target, filepath.Join(ctx.git.dir, "Kconfig"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrBadKconfig, err)
+	}
+	full, err := kconfig.ParseConfigData(original, "original")
+	if err != nil {
+		return nil, nil, err
+	}
+	base, err := kconfig.ParseConfigData(baseline, "baseline")
+	if err != nil {
+		return nil, nil, err
+	}
+	setLinuxTagConfigs(base, nil)
+	// Unlike config minimization during bisection, here we want the precise answer,
+	// so allow much more steps. But each step is a kernel build and a reproducer run,
+	// so still limit them to keep the job within a day.
+	const bisectConfigSteps = 50
+	config, err := kconf.Minimize(base, full, func(cfg *kconfig.ConfigFile) (bool, error) {
+		cfg = cfg.Clone()
+		setLinuxTagConfigs(cfg, nil)
+		res, err := pred(serialize(cfg))
+		return res == BisectBad, err
+	}, bisectConfigSteps, dt)
+	if err != nil {
+		return nil, nil, err
+	}
+	setLinuxTagConfigs(config, nil)
+	var options []string
+	for _, cfg := range config.Configs {
+		// Options may be enabled as modules, non-tristate options may have non-default values.
+		if cfg.Value != kconfig.No && cfg.Value != base.Value(cfg.Name) {
+			options = append(options, fmt.Sprintf("CONFIG_%v=%v", cfg.Name, cfg.Value))
+		}
+	}
+	sort.Strings(options)
+	return serialize(config), options, nil
+}
+
 func serialize(cf *kconfig.ConfigFile) []byte {
 	return []byte(fmt.Sprintf("%v, rev: %v\n%s", configBisectTag, prog.GitRevision, cf.Serialize()))
 }
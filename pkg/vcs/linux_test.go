@@ -4,8 +4,13 @@
 package vcs
 
 import (
+	"bytes"
+	"path/filepath"
 	"testing"
 
+	"github.com/google/syzkaller/pkg/debugtracer"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/sys/targets"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -48,3 +53,37 @@ func TestGCCVersion(t *testing.T) {
 	expected = defaultCompiler
 	assert.Equal(t, actual, expected, "unexpected gcc path")
 }
+
+func TestBisectConfigModules(t *testing.T) {
+	dir := t.TempDir()
+	const kconf = `
+mainmenu "test"
+config A
+	bool "A"
+config B
+	bool "B"
+config M
+	tristate "M"
+`
+	if err := osutil.WriteFile(filepath.Join(dir, "Kconfig"), []byte(kconf)); err != nil {
+		t.Fatal(err)
+	}
+	repo := &linux{git: &git{dir: dir}}
+	full := []byte("CONFIG_A=y\nCONFIG_B=y\nCONFIG_M=m\n")
+	base := []byte("CONFIG_A=y\n")
+	// The crash needs the option enabled as a module, so it must be bisected down to it,
+	// and both the resulting config and the reported option must keep the module value.
+	config, options, err := repo.BisectConfig(targets.Get(targets.Linux, targets.AMD64), full, base,
+		&debugtracer.TestTracer{T: t}, func(test []byte) (BisectResult, error) {
+			if bytes.Contains(test, []byte("CONFIG_M=m")) {
+				return BisectBad, nil
+			}
+			return BisectGood, nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"CONFIG_M=m"}, options)
+	assert.Contains(t, string(config), "CONFIG_M=m")
+	assert.NotContains(t, string(config), "CONFIG_B=y")
+}
@@ -5,6 +5,8 @@ package vcs
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/google/syzkaller/pkg/debugtracer"
 	"github.com/google/syzkaller/pkg/report/crash"
@@ -15,7 +17,10 @@ type testos struct {
 	*git
 }
 
-var _ ConfigMinimizer = new(testos)
+var (
+	_ ConfigMinimizer = new(testos)
+	_ ConfigBisecter  = new(testos)
+)
 
 func newTestos(dir string, opts []RepoOpt) *testos {
 	return &testos{
@@ -56,6 +61,34 @@ func (ctx *testos) Minimize(target *targets.Target, original, baseline []byte, t
 	}
 }
 
+// BisectConfig treats configs as lists of lines and drops the lines one by one.
+func (ctx *testos) BisectConfig(target *targets.Target, original, baseline []byte, dt debugtracer.DebugTracer,
+	pred func(test []byte) (BisectResult, error)) ([]byte, []string, error) {
+	base := strings.Split(strings.TrimSpace(string(baseline)), "\n")
+	var options []string
+	for _, line := range strings.Split(strings.TrimSpace(string(original)), "\n") {
+		if !slices.Contains(base, line) {
+			options = append(options, line)
+		}
+	}
+	join := func(lines ...[]string) []byte {
+		return []byte(strings.Join(slices.Concat(lines...), "\n") + "\n")
+	}
+	for i := 0; i < len(options); {
+		rest := slices.Concat(options[:i], options[i+1:])
+		res, err := pred(join(base, rest))
+		if err != nil {
+			return nil, nil, err
+		}
+		if res == BisectBad {
+			options = rest
+		} else {
+			i++
+		}
+	}
+	return join(base, options), options, nil
+}
+
 func (ctx *testos) PrepareBisect() error {
 	return nil
 }
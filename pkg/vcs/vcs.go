@@ -98,11 +98,58 @@ type Bisecter interface {
 		kernelConfig []byte, backports []BackportCommit) (*BisectEnv, error)
 }
 
+// Reverter may be optionally implemented by Repo.
+type Reverter interface {
+	// Revert reverts the commit on top of the current HEAD in the working tree (without committing).
+	// If the commit does not revert cleanly, only changes to the files that revert cleanly are kept
+	// and partial is set. Returns an error if nothing could be reverted.
+	Revert(commit string) (partial bool, err error)
+}
+
 type ConfigMinimizer interface {
 	Minimize(target *targets.Target, original, baseline []byte, types []crash.Type,
 		dt debugtracer.DebugTracer, pred func(test []byte) (BisectResult, error)) ([]byte, error)
 }
 
+// ConfigBisecter may be optionally implemented by Repo.
+type ConfigBisecter interface {
+	// BisectConfig finds the minimal set of options that need to be enabled in the baseline config
+	// for the predicate to return BisectBad. The predicate is assumed to return BisectBad for
+	// the original config and BisectGood for the baseline config.
+	// Returns the resulting config and the list of the options.
+	BisectConfig(target *targets.Target, original, baseline []byte, dt debugtracer.DebugTracer,
+		pred func(test []byte) (BisectResult, error)) ([]byte, []string, error)
+}
+
+// ChangeSearcher may be optionally implemented by Repo.
+type ChangeSearcher interface {
+	// FunctionChanges returns commits reachable from head, but not from base,
+	// that modify the function in the file. Merge commits are not considered.
+	FunctionChanges(base, head, file, function string) ([]*FunctionChange, error)
+}
+
+// RefLister may be optionally implemented by Repo.
+type RefLister interface {
+	// ListRefs returns the refs of the remote repository that match the pattern
+	// (e.g. "refs/heads/review/*") mapped to the commit hashes.
+	ListRefs(repo, pattern string) (map[string]string, error)
+}
+
+// Differ may be optionally implemented by Repo.
+type Differ interface {
+	// ChangedFiles returns the files that differ between the two commits.
+	ChangedFiles(base, head string) ([]string, error)
+}
+
+// FunctionChange is a commit that modifies a particular function.
+type FunctionChange struct {
+	Commit *Commit
+	// Message is the full commit message.
+	Message string
+	// Patch contains the changes to the file with the function.
+	Patch string
+}
+
 type Commit struct {
 	Hash       string
 	Title      string
@@ -0,0 +1,166 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package vminfo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/prog"
+)
+
+// CheckResult is a serializable summary of a machine check.
+// It's persisted across manager restarts to detect changes between kernel builds.
+type CheckResult struct {
+	// Enabled syscalls.
+	Enabled []string
+	// Disabled syscalls with the reason (including transitively disabled).
+	Disabled map[string]string
+	Features map[string]Feature
+}
+
+func NewCheckResult(enabled map[*prog.Syscall]bool, disabled map[*prog.Syscall]string,
+	features Features) *CheckResult {
+	res := &CheckResult{
+		Disabled: make(map[string]string),
+		Features: make(map[string]Feature),
+	}
+	for call := range enabled {
+		res.Enabled = append(res.Enabled, call.Name)
+	}
+	sort.Strings(res.Enabled)
+	for call, reason := range disabled {
+		res.Disabled[call.Name] = reason
+	}
+	for feat, info := range features {
+		res.Features[flatrpc.EnumNamesFeature[feat]] = info
+	}
+	return res
+}
+
+// LoadCheckResult loads a previously saved check result.
+// It returns nil result and no error if the file does not exist.
+func LoadCheckResult(file string) (*CheckResult, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	res := new(CheckResult)
+	if err := json.Unmarshal(data, res); err != nil {
+		return nil, fmt.Errorf("failed to parse %v: %w", file, err)
+	}
+	return res, nil
+}
+
+func (res *CheckResult) Save(file string) error {
+	data, err := json.MarshalIndent(res, "", "\t")
+	if err != nil {
+		return err
+	}
+	return osutil.WriteFile(file, data)
+}
+
+// CheckDiff describes changes in the machine check result relative to the previous run.
+type CheckDiff struct {
+	// Newly disabled syscalls, reason is the reason they are disabled now.
+	Disabled []CallChange
+	// Newly enabled syscalls, reason is the reason they were disabled before.
+	Enabled  []CallChange
+	Features []FeatureChange
+}
+
+type CallChange struct {
+	Name   string
+	Reason string
+}
+
+type FeatureChange struct {
+	Name    string
+	Enabled bool
+	// Reason in the previous and the current check.
+	OldReason string
+	NewReason string
+}
+
+// Diff returns changes of the result relative to prev.
+// Syscalls that are not present in both results (e.g. added/removed descriptions) are ignored.
+func (res *CheckResult) Diff(prev *CheckResult) *CheckDiff {
+	diff := new(CheckDiff)
+	prevEnabled := make(map[string]bool)
+	for _, call := range prev.Enabled {
+		prevEnabled[call] = true
+	}
+	for _, call := range res.Enabled {
+		if reason, ok := prev.Disabled[call]; ok {
+			diff.Enabled = append(diff.Enabled, CallChange{call, reason})
+		}
+	}
+	for call, reason := range res.Disabled {
+		if prevEnabled[call] {
+			diff.Disabled = append(diff.Disabled, CallChange{call, reason})
+		}
+	}
+	sort.Slice(diff.Disabled, func(i, j int) bool {
+		return diff.Disabled[i].Name < diff.Disabled[j].Name
+	})
+	for name, info := range res.Features {
+		old, ok := prev.Features[name]
+		if !ok || old.Enabled == info.Enabled {
+			continue
+		}
+		diff.Features = append(diff.Features, FeatureChange{
+			Name:      name,
+			Enabled:   info.Enabled,
+			OldReason: old.Reason,
+			NewReason: info.Reason,
+		})
+	}
+	sort.Slice(diff.Features, func(i, j int) bool {
+		return diff.Features[i].Name < diff.Features[j].Name
+	})
+	return diff
+}
+
+func (diff *CheckDiff) Empty() bool {
+	return len(diff.Disabled) == 0 && len(diff.Enabled) == 0 && len(diff.Features) == 0
+}
+
+func (diff *CheckDiff) String() string {
+	buf := new(bytes.Buffer)
+	if len(diff.Disabled) != 0 {
+		fmt.Fprintf(buf, "newly disabled syscalls (%v):\n", len(diff.Disabled))
+		for _, call := range diff.Disabled {
+			fmt.Fprintf(buf, "%-44v: %v\n", call.Name, call.Reason)
+		}
+		fmt.Fprintf(buf, "\n")
+	}
+	if len(diff.Enabled) != 0 {
+		fmt.Fprintf(buf, "newly enabled syscalls (%v):\n", len(diff.Enabled))
+		for _, call := range diff.Enabled {
+			fmt.Fprintf(buf, "%-44v: was %v\n", call.Name, call.Reason)
+		}
+		fmt.Fprintf(buf, "\n")
+	}
+	if len(diff.Features) != 0 {
+		fmt.Fprintf(buf, "changed features:\n")
+		for _, feat := range diff.Features {
+			state := "disabled"
+			if feat.Enabled {
+				state = "enabled"
+			}
+			fmt.Fprintf(buf, "%-24v: %v (%v -> %v)\n", feat.Name, state, feat.OldReason, feat.NewReason)
+		}
+		fmt.Fprintf(buf, "\n")
+	}
+	return buf.String()
+}
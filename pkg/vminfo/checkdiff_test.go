@@ -0,0 +1,64 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package vminfo
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckDiff(t *testing.T) {
+	prev := &CheckResult{
+		Enabled: []string{"open", "read", "write"},
+		Disabled: map[string]string{
+			"ioctl$KVM": "/dev/kvm does not exist",
+		},
+		Features: map[string]Feature{
+			"Fault":    {Enabled: true, Reason: "enabled"},
+			"Coverage": {Enabled: true, Reason: "enabled"},
+		},
+	}
+	cur := &CheckResult{
+		Enabled: []string{"ioctl$KVM", "open", "new_call"},
+		Disabled: map[string]string{
+			"read":  "no such syscall",
+			"write": "no such syscall",
+		},
+		Features: map[string]Feature{
+			"Fault":    {Enabled: false, Reason: "CONFIG_FAULT_INJECTION is not enabled"},
+			"Coverage": {Enabled: true, Reason: "enabled"},
+		},
+	}
+	file := filepath.Join(t.TempDir(), "check.json")
+	loaded, err := LoadCheckResult(file)
+	assert.NoError(t, err)
+	assert.Nil(t, loaded)
+	assert.NoError(t, prev.Save(file))
+	loaded, err = LoadCheckResult(file)
+	assert.NoError(t, err)
+	assert.Equal(t, prev, loaded)
+
+	diff := cur.Diff(loaded)
+	assert.Equal(t, &CheckDiff{
+		Disabled: []CallChange{
+			{"read", "no such syscall"},
+			{"write", "no such syscall"},
+		},
+		Enabled: []CallChange{
+			{"ioctl$KVM", "/dev/kvm does not exist"},
+		},
+		Features: []FeatureChange{
+			{
+				Name:      "Fault",
+				Enabled:   false,
+				OldReason: "enabled",
+				NewReason: "CONFIG_FAULT_INJECTION is not enabled",
+			},
+		},
+	}, diff)
+	assert.False(t, diff.Empty())
+	assert.True(t, cur.Diff(cur).Empty())
+}
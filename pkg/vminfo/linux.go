@@ -23,6 +23,7 @@ func (linux) RequiredFiles() []string {
 		"/proc/modules",
 		"/proc/kallsyms",
 		"/sys/module/*/sections/.text",
+		"/sys/module/*/notes/.note.gnu.build-id",
 		"/sys/module/kvm*/parameters/*",
 	}
 }
@@ -71,7 +72,8 @@ func (linux) parseModules(files filesystem) ([]*KernelModule, error) {
 			// ex. module1['Addr'] + module1['Size'] > module2['Addr']
 			// runtime kernel doesn't export .text section size to /sys/module/*/sections/.text
 			// so we need to read it from elf
-			Size: modSize - offset,
+			Size:    modSize - offset,
+			BuildID: linuxModuleBuildID(files, name),
 		})
 	}
 	_stext, _etext, err := linuxParseCoreKernel(files)
@@ -102,6 +104,15 @@ func linuxModuleTextAddr(files filesystem, module string) (uint64, error) {
 	return addr, nil
 }
 
+func linuxModuleBuildID(files filesystem, module string) string {
+	// The file is not present if the module was built without build-id.
+	data, err := files.ReadFile("/sys/module/" + module + "/notes/.note.gnu.build-id")
+	if err != nil {
+		return ""
+	}
+	return ParseBuildIDNote(data)
+}
+
 func linuxParseCoreKernel(files filesystem) (uint64, uint64, error) {
 	_Text, _ := files.ReadFile("/proc/kallsyms")
 	re := regexp.MustCompile(`([a-fA-F0-9]+) T _stext\n`)
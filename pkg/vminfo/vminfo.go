@@ -15,6 +15,8 @@ package vminfo
 import (
 	"bytes"
 	"context"
+	"encoding/binary"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -33,6 +35,36 @@ type KernelModule struct {
 	Addr uint64
 	Size uint64
 	Path string
+	// GNU build-id of the module (hex), empty if unknown.
+	// Build-ids are used to verify that the module loaded in the VM matches the local object file.
+	BuildID string `json:",omitempty"`
+}
+
+// ParseBuildIDNote extracts GNU build-id from the contents of an ELF notes section
+// (e.g. .note.gnu.build-id section or /sys/module/*/notes/.note.gnu.build-id file).
+// Returns an empty string if there is no build-id note.
+func ParseBuildIDNote(data []byte) string {
+	const noteGNUBuildID = 3
+	align4 := func(v uint32) uint32 { return (v + 3) &^ 3 }
+	// Notes use the target byte order, note names are short, so a large name size means wrong byte order.
+	var order binary.ByteOrder = binary.LittleEndian
+	if len(data) >= 4 && order.Uint32(data) > 0xffff {
+		order = binary.BigEndian
+	}
+	for len(data) >= 12 {
+		nameSize, descSize, typ := order.Uint32(data), order.Uint32(data[4:]), order.Uint32(data[8:])
+		data = data[12:]
+		nameEnd := uint64(align4(nameSize))
+		descEnd := nameEnd + uint64(align4(descSize))
+		if descEnd > uint64(len(data)) {
+			break
+		}
+		if typ == noteGNUBuildID && string(data[:nameSize]) == "GNU\x00" {
+			return hex.EncodeToString(data[nameEnd : nameEnd+uint64(descSize)])
+		}
+		data = data[descEnd:]
+	}
+	return ""
 }
 
 type Checker struct {
@@ -5,6 +5,7 @@ package vminfo
 
 import (
 	"context"
+	"encoding/binary"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -181,3 +182,34 @@ func readFile(file string) *flatrpc.FileInfo {
 		Data:   data,
 	}
 }
+
+func TestParseBuildIDNote(t *testing.T) {
+	note := func(order binary.AppendByteOrder, name string, typ uint32, desc []byte) []byte {
+		var buf []byte
+		buf = order.AppendUint32(buf, uint32(len(name)))
+		buf = order.AppendUint32(buf, uint32(len(desc)))
+		buf = order.AppendUint32(buf, typ)
+		buf = append(buf, name...)
+		for len(buf)%4 != 0 {
+			buf = append(buf, 0)
+		}
+		buf = append(buf, desc...)
+		for len(buf)%4 != 0 {
+			buf = append(buf, 0)
+		}
+		return buf
+	}
+	buildID := []byte{0xde, 0xad, 0xbe, 0xef, 0x01}
+	for _, order := range []binary.AppendByteOrder{binary.LittleEndian, binary.BigEndian} {
+		data := append(note(order, "Linux\x00", 1, []byte{1, 2}), note(order, "GNU\x00", 3, buildID)...)
+		if got := ParseBuildIDNote(data); got != "deadbeef01" {
+			t.Errorf("%v: got build-id %q", order, got)
+		}
+		if got := ParseBuildIDNote(data[:len(data)-4]); got != "" {
+			t.Errorf("%v: got build-id %q for truncated note", order, got)
+		}
+	}
+	if got := ParseBuildIDNote(nil); got != "" {
+		t.Errorf("got build-id %q for empty note", got)
+	}
+}
@@ -100,6 +100,24 @@ func (target *Target) DeserializeExec(exec []byte, stats map[string]int) (ExecPr
 	return p, nil
 }
 
+// ExecPrefixes returns instructions of the exec-encoded program exec (without the header)
+// for all program prefixes: the i-th element corresponds to the first i+1 calls
+// (including copyouts of their results). The returned slices point into exec.
+// Execution of equal prefixes leaves executor in the same state.
+func (target *Target) ExecPrefixes(exec []byte) ([][]byte, error) {
+	dec := &execDecoder{target: target, data: exec, prefixes: true}
+	dec.parse()
+	if dec.err != nil {
+		return nil, dec.err
+	}
+	_, header := binary.Varint(exec)
+	var res [][]byte
+	for _, end := range dec.prefixEnds {
+		res = append(res, exec[header:end])
+	}
+	return res, nil
+}
+
 type execDecoder struct {
 	target  *Target
 	data    []byte
@@ -109,14 +127,21 @@ type execDecoder struct {
 	call    ExecCall
 	calls   []ExecCall
 	stats   map[string]int
+	// If prefixes is set, prefixEnds[i] is set to the offset of the first instruction
+	// after the first i+1 calls.
+	prefixes   bool
+	prefixEnds []int
 }
 
 func (dec *execDecoder) parse() {
+	size := len(dec.data)
 	ncalls := dec.read("header")
 	for dec.err == nil {
+		pos := size - len(dec.data)
 		switch instr := dec.read("instr/opcode"); instr {
 		case execInstrCopyin:
 			dec.commitCall()
+			dec.checkPrefix(pos)
 			dec.call.Copyin = append(dec.call.Copyin, ExecCopyin{
 				Addr: dec.read("instr/copyin") + dec.target.DataOffset,
 				Arg:  dec.readArg(),
@@ -129,15 +154,18 @@ func (dec *execDecoder) parse() {
 			})
 		case execInstrEOF:
 			dec.commitCall()
+			dec.checkPrefix(pos)
 			if ncalls != uint64(len(dec.calls)) {
 				dec.err = fmt.Errorf("bad number of calls: %v/%v", ncalls, len(dec.calls))
 			}
 			return
 		case execInstrSetProps:
 			dec.commitCall()
+			dec.checkPrefix(pos)
 			dec.readCallProps(&dec.call.Props)
 		default:
 			dec.commitCall()
+			dec.checkPrefix(pos)
 			if instr >= uint64(len(dec.target.Syscalls)) {
 				dec.setErr(fmt.Errorf("bad syscall %v", instr))
 				return
@@ -157,6 +185,12 @@ func (dec *execDecoder) parse() {
 	}
 }
 
+func (dec *execDecoder) checkPrefix(pos int) {
+	if dec.prefixes && len(dec.prefixEnds) < len(dec.calls) {
+		dec.prefixEnds = append(dec.prefixEnds, pos)
+	}
+}
+
 func (dec *execDecoder) readCallProps(props *CallProps) {
 	props.ForeachProp(func(_, _ string, value reflect.Value) {
 		arg := dec.read("call prop")
@@ -761,3 +761,47 @@ func TestSerializeForExecOverflow(t *testing.T) {
 		})
 	}
 }
+
+func TestExecPrefixes(t *testing.T) {
+	target := initTargetTest(t, "test", "64")
+	prefixes := func(text string) ([]byte, [][]byte) {
+		p, err := target.Deserialize([]byte(text), Strict)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := p.SerializeForExec()
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := target.ExecPrefixes(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res) != len(p.Calls) {
+			t.Fatalf("got %v prefixes for %v calls", len(res), len(p.Calls))
+		}
+		for i := 1; i < len(res); i++ {
+			if !bytes.HasPrefix(res[i], res[i-1]) {
+				t.Fatalf("prefix %v does not start with the previous prefix", i)
+			}
+		}
+		return data, res
+	}
+	p0, prefixes0 := prefixes("r0 = test$res0()\nr1 = test$res0()\ntest$res1(r0)\n")
+	_, prefixes1 := prefixes("r0 = test$res0()\nr1 = test$res0()\ntest$res1(r0)\ntest$res1(r0)\n")
+	// The second result is used only in this program, so it's copied out.
+	_, prefixes2 := prefixes("r0 = test$res0()\nr1 = test$res0()\ntest$res1(r0)\ntest$res1(r1)\n")
+	if !bytes.Equal(prefixes0[1], prefixes1[1]) {
+		t.Fatalf("equal prefixes are different")
+	}
+	if !bytes.Equal(prefixes0[0], prefixes2[0]) {
+		t.Fatalf("equal prefixes are different")
+	}
+	if bytes.Equal(prefixes0[1], prefixes2[1]) {
+		t.Fatalf("different prefixes are equal")
+	}
+	if len(prefixes0[2]) != len(p0)-2 {
+		// Everything except for the header and the EOF instruction.
+		t.Fatalf("wrong full prefix size %v/%v", len(prefixes0[2]), len(p0))
+	}
+}
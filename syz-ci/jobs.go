@@ -33,6 +33,7 @@ type JobManager struct {
 	managers          []*Manager
 	parallelJobFilter *ManagerJobs
 	shutdownPending   <-chan struct{}
+	buildCache        *build.Cache
 }
 
 type JobProcessor struct {
@@ -51,6 +52,12 @@ func newJobManager(cfg *Config, managers []*Manager, shutdownPending chan struct
 	if err != nil {
 		return nil, err
 	}
+	var buildCache *build.Cache
+	if cfg.BuildCache != "" {
+		if buildCache, err = build.NewCache(cfg.BuildCache); err != nil {
+			return nil, err
+		}
+	}
 	return &JobManager{
 		cfg:             cfg,
 		dash:            dash,
@@ -58,6 +65,7 @@ func newJobManager(cfg *Config, managers []*Manager, shutdownPending chan struct
 		shutdownPending: shutdownPending,
 		// For now let's only parallelize patch testing requests.
 		parallelJobFilter: &ManagerJobs{TestPatches: true},
+		buildCache:        buildCache,
 	}, nil
 }
 
@@ -293,9 +301,11 @@ func (jp *JobProcessor) pollJobs() {
 			jobs = jobs.Filter(jp.jobFilter)
 		}
 		apiJobs := dashapi.ManagerJobs{
-			TestPatches: jobs.TestPatches,
-			BisectCause: jobs.BisectCause,
-			BisectFix:   jobs.BisectFix,
+			TestPatches:  jobs.TestPatches,
+			BisectCause:  jobs.BisectCause,
+			BisectFix:    jobs.BisectFix,
+			BisectConfig: jobs.BisectConfig,
+			FixSearch:    jobs.FixSearch,
 		}
 		if apiJobs.Any() {
 			poll.Managers[mgr.name] = apiJobs
@@ -389,7 +399,7 @@ func (jp *JobProcessor) process(job *Job) *dashapi.JobDoneReq {
 	case dashapi.JobTestPatch:
 		resp.Build.KernelCommit = "[unknown]"
 		mgrcfg.Name += "-test" + jp.instanceSuffix
-	case dashapi.JobBisectCause, dashapi.JobBisectFix:
+	case dashapi.JobBisectCause, dashapi.JobBisectFix, dashapi.JobBisectConfig, dashapi.JobFixSearch:
 		resp.Build.KernelCommit = req.KernelCommit
 		resp.Build.KernelCommitTitle = req.KernelCommitTitle
 		mgrcfg.Name += "-bisect" + jp.instanceSuffix
@@ -433,7 +443,7 @@ func (jp *JobProcessor) process(job *Job) *dashapi.JobDoneReq {
 	switch req.Type {
 	case dashapi.JobTestPatch:
 		err = jp.testPatch(job, mgrcfg)
-	case dashapi.JobBisectCause, dashapi.JobBisectFix:
+	case dashapi.JobBisectCause, dashapi.JobBisectFix, dashapi.JobBisectConfig, dashapi.JobFixSearch:
 		err = jp.bisect(job, mgrcfg)
 	}
 	if err != nil {
@@ -460,6 +470,9 @@ func (jp *JobProcessor) bisect(job *Job, mgrcfg *mgrconfig.Config) error {
 			return fmt.Errorf("failed to read baseline config: %w", err)
 		}
 	}
+	if req.Type == dashapi.JobBisectConfig && len(baseline) == 0 {
+		return fmt.Errorf("config bisection requires kernel_baseline_config")
+	}
 	err := jp.prepareBisectionRepo(mgrcfg, req)
 	if err != nil {
 		return err
@@ -487,13 +500,14 @@ func (jp *JobProcessor) bisect(job *Job, mgrcfg *mgrconfig.Config) error {
 		// Bisection jobs are now executed in parallel to patch testing, so it doesn't destroy user experience.
 		// Let's set the timeout to 12h.
 		Timeout:         12 * time.Hour,
-		Fix:             req.Type == dashapi.JobBisectFix,
+		Fix:             req.Type == dashapi.JobBisectFix || req.Type == dashapi.JobFixSearch,
 		DefaultCompiler: mgr.mgrcfg.Compiler,
 		CompilerType:    mgr.mgrcfg.CompilerType,
 		BinDir:          jp.cfg.BisectBinDir,
 		Linker:          mgr.mgrcfg.Linker,
 		Ccache:          jp.cfg.Ccache,
 		BuildCPUs:       jp.cfg.BuildCPUs,
+		BuildCache:      jp.buildCache,
 		Kernel: bisect.KernelConfig{
 			Repo:           req.KernelRepo,
 			Branch:         req.KernelBranch,
@@ -516,11 +530,21 @@ func (jp *JobProcessor) bisect(job *Job, mgrcfg *mgrconfig.Config) error {
 			C:    req.ReproC,
 		},
 		CrossTree:      req.MergeBaseRepo != "",
+		VerifyCulprit:  jp.cfg.BisectVerifyCulprit,
+		ConfigBisect:   req.Type == dashapi.JobBisectConfig,
 		Manager:        mgrcfg,
 		BuildSemaphore: buildSem,
 		TestSemaphore:  testSem,
 	}
 
+	if req.Type == dashapi.JobFixSearch {
+		cfg.FixSearch = &bisect.FixSearch{
+			File:   req.GuiltyFile,
+			Frames: req.StackFrames,
+			Title:  req.CrashTitle,
+		}
+	}
+
 	res, err := bisect.Run(cfg)
 	resp.Log = trace.Bytes()
 	if err != nil {
@@ -530,6 +554,10 @@ func (jp *JobProcessor) bisect(job *Job, mgrcfg *mgrconfig.Config) error {
 		}
 		return err
 	}
+	if cfg.ConfigBisect {
+		resp.ConfigOptions = res.ConfigOptions
+		resp.Build.KernelConfig = res.Config
+	}
 	for _, com := range res.Commits {
 		resp.Commits = append(resp.Commits, dashapi.Commit{
 			Hash:       com.Hash,
@@ -550,6 +578,12 @@ func (jp *JobProcessor) bisect(job *Job, mgrcfg *mgrconfig.Config) error {
 		if res.IsRelease {
 			resp.Flags |= dashapi.BisectResultRelease
 		}
+		switch res.Verification {
+		case bisect.CulpritConfirmed:
+			resp.Flags |= dashapi.BisectResultConfirmed
+		case bisect.CulpritUnconfirmed:
+			resp.Flags |= dashapi.BisectResultUnconfirmed
+		}
 		const confidenceCutOff = 0.66
 		if res.Confidence < confidenceCutOff {
 			resp.Flags |= dashapi.BisectResultIgnore
@@ -643,6 +677,11 @@ func (jp *JobProcessor) testPatch(job *Job, mgrcfg *mgrconfig.Config) error {
 		[]byte("# CONFIG_DEBUG_INFO_BTF is not set"), -1)
 
 	log.Logf(0, "job: building kernel...")
+	// The patched sources don't match the commit, so only the build tree is reused.
+	cacheCommit := kernelCommit.Hash
+	if len(req.Patch) != 0 {
+		cacheCommit = ""
+	}
 	kernelConfig, details, err := env.BuildKernel(&instance.BuildKernelConfig{
 		CompilerBin:  mgr.mgrcfg.Compiler,
 		LinkerBin:    mgr.mgrcfg.Linker,
@@ -651,6 +690,8 @@ func (jp *JobProcessor) testPatch(job *Job, mgrcfg *mgrconfig.Config) error {
 		CmdlineFile:  mgr.mgrcfg.KernelCmdline,
 		SysctlFile:   mgr.mgrcfg.KernelSysctl,
 		KernelConfig: req.KernelConfig,
+		Cache:        jp.buildCache,
+		KernelCommit: cacheCommit,
 	})
 	resp.Build.CompilerID = details.CompilerID
 	if err != nil {
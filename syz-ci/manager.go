@@ -102,6 +102,7 @@ type ManagerDashapi interface {
 	LogError(name, msg string, args ...interface{})
 	CommitPoll() (*dashapi.CommitPollResp, error)
 	UploadCommits(commits []dashapi.Commit) error
+	ReportSeriesFindings(req *dashapi.SeriesFindingsReq) error
 }
 
 func createManager(cfg *Config, mgrcfg *ManagerConfig, stop chan struct{},
@@ -1069,8 +1070,17 @@ func (mgr *ManagerConfig) validate(cfg *Config) error {
 	if mgr.Jobs.PollCommits && (cfg.DashboardAddr == "" || mgr.DashboardClient == "") {
 		return fmt.Errorf("manager %v: commit_poll is set but no dashboard info", mgr.Name)
 	}
-	if (mgr.Jobs.BisectCause || mgr.Jobs.BisectFix) && cfg.BisectBinDir == "" {
+	if (mgr.Jobs.BisectCause || mgr.Jobs.BisectFix || mgr.Jobs.BisectConfig || mgr.Jobs.FixSearch) &&
+		cfg.BisectBinDir == "" {
 		return fmt.Errorf("manager %v: enabled bisection but no bisect_bin_dir", mgr.Name)
 	}
+	if mgr.Jobs.BisectConfig && mgr.KernelBaselineConfig == "" {
+		return fmt.Errorf("manager %v: enabled config bisection but no kernel_baseline_config", mgr.Name)
+	}
+	if mgr.Series != nil {
+		if err := mgr.Series.validate(); err != nil {
+			return fmt.Errorf("manager %v: %w", mgr.Name, err)
+		}
+	}
 	return nil
 }
@@ -0,0 +1,652 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/syzkaller/dashboard/dashapi"
+	"github.com/google/syzkaller/pkg/build"
+	"github.com/google/syzkaller/pkg/config"
+	"github.com/google/syzkaller/pkg/email"
+	"github.com/google/syzkaller/pkg/email/lore"
+	"github.com/google/syzkaller/pkg/instance"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/vcs"
+)
+
+// SeriesConfig enables fuzzing of proposed patch series before they are merged.
+// Each series is applied on top of its base commit (the "base-commit:" trailer of mailing list series,
+// or the manager's repo/branch if it's not specified), built and fuzzed for a limited time
+// with the coverage focused on the modified files. The base tree is fuzzed in the same way,
+// the results are reused for series with the same base commit.
+// Reproduced crashes that were not seen on the base tree are reported back to the series thread
+// (only for mailing list series, findings for git refs are only logged).
+type SeriesConfig struct {
+	// Dir with a local mirror of a lore archive (e.g. a clone of https://lore.kernel.org/netdev/0).
+	// The mirror is expected to be updated externally.
+	LoreArchive string `json:"lore_archive"`
+	// Alternatively, refs of Repo that match RefPattern (e.g. "refs/heads/review/*") are fuzzed as series.
+	Repo       string `json:"repo"`
+	RefPattern string `json:"ref_pattern"`
+	// Fuzzing time for each series (and for its base) in minutes (defaults to 120).
+	FuzzingMinutes int `json:"fuzzing_minutes"`
+	// Number of VMs used for fuzzing (defaults to 2).
+	VMs int `json:"vms"`
+}
+
+func (cfg *SeriesConfig) validate() error {
+	if (cfg.LoreArchive == "") == (cfg.RefPattern == "") {
+		return fmt.Errorf("series: exactly one of lore_archive and ref_pattern must be set")
+	}
+	if cfg.RefPattern != "" && cfg.Repo == "" {
+		return fmt.Errorf("series: ref_pattern requires repo")
+	}
+	return nil
+}
+
+const (
+	seriesPollPeriod = 30 * time.Minute
+	// Lore messages are kept for that long to collect all patches of a series.
+	seriesMessageAge = 3 * 24 * time.Hour
+	// Processed series are forgotten if they are not seen for that long.
+	seriesRetention = 30 * 24 * time.Hour
+)
+
+// seriesFuzzer fuzzes the proposed patch series for a manager.
+type seriesFuzzer struct {
+	mgr       *Manager
+	cfg       *SeriesConfig
+	dir       string
+	kernelDir string
+	repo      vcs.Repo
+	state     seriesState
+}
+
+// seriesState is persisted across restarts.
+type seriesState struct {
+	LoreHead string
+	// LoreMessages are the recent lore messages, they may belong to series that are not complete yet.
+	LoreMessages []*loreMessage
+	// Processed maps series IDs to the last time the series was seen.
+	Processed map[string]time.Time
+	// Bases maps base commits to the results of fuzzing the base tree.
+	Bases map[string]*seriesBase
+}
+
+type loreMessage struct {
+	// Hash of the lore archive commit with the message.
+	Hash string
+	Date time.Time
+	msg  *email.Email
+}
+
+type seriesBase struct {
+	// Files are the modified files the fuzzing was focused on.
+	Files []string
+	// Crashes are titles of the crashes found on the base tree.
+	Crashes []string
+	Time    time.Time
+}
+
+// seriesCrash is a crash saved in the manager workdir.
+type seriesCrash struct {
+	report []byte
+	repro  []byte
+}
+
+// proposedSeries is either a mailing list series or a git ref.
+type proposedSeries struct {
+	id   string
+	mail *lore.Series
+	ref  string
+	hash string
+}
+
+func (series *proposedSeries) String() string {
+	if series.mail != nil {
+		return fmt.Sprintf("%q (%v)", series.mail.Subject, series.mail.MessageID)
+	}
+	return fmt.Sprintf("%v (%v)", series.ref, series.hash)
+}
+
+func newSeriesFuzzer(mgr *Manager) (*seriesFuzzer, error) {
+	dir := filepath.Join(filepath.Dir(mgr.workDir), "series")
+	if err := osutil.MkdirAll(dir); err != nil {
+		return nil, err
+	}
+	kernelDir := filepath.Join(dir, "kernel")
+	repo, err := vcs.NewRepo(mgr.managercfg.TargetOS, mgr.managercfg.Type, kernelDir)
+	if err != nil {
+		return nil, err
+	}
+	sf := &seriesFuzzer{
+		mgr:       mgr,
+		cfg:       mgr.mgrcfg.Series,
+		dir:       dir,
+		kernelDir: kernelDir,
+		repo:      repo,
+	}
+	if osutil.IsExist(sf.stateFile()) {
+		if err := config.LoadFile(sf.stateFile(), &sf.state); err != nil {
+			return nil, err
+		}
+	}
+	if sf.state.Processed == nil {
+		sf.state.Processed = make(map[string]time.Time)
+	}
+	if sf.state.Bases == nil {
+		sf.state.Bases = make(map[string]*seriesBase)
+	}
+	return sf, nil
+}
+
+func (sf *seriesFuzzer) stateFile() string {
+	return filepath.Join(sf.dir, "state.json")
+}
+
+func (sf *seriesFuzzer) loop() {
+	ticker := time.NewTicker(seriesPollPeriod)
+	defer ticker.Stop()
+	for {
+		list, err := sf.poll()
+		if err != nil {
+			sf.mgr.Errorf("failed to poll series: %v", err)
+		}
+		for _, series := range list {
+			if sf.stopped() {
+				return
+			}
+			log.Logf(0, "%v: fuzzing series %v", sf.mgr.name, series)
+			if err := sf.process(series); err != nil {
+				sf.mgr.Errorf("failed to fuzz series %v: %v", series, err)
+			}
+			if sf.stopped() {
+				// The series was not fuzzed till the end, retry after restart.
+				return
+			}
+			sf.state.Processed[series.id] = time.Now()
+			if err := config.SaveFile(sf.stateFile(), sf.state); err != nil {
+				sf.mgr.Errorf("failed to save series state: %v", err)
+			}
+		}
+		select {
+		case <-ticker.C:
+		case <-sf.mgr.stop:
+			return
+		}
+	}
+}
+
+func (sf *seriesFuzzer) stopped() bool {
+	select {
+	case <-sf.mgr.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// poll returns the series that were not processed yet.
+func (sf *seriesFuzzer) poll() ([]*proposedSeries, error) {
+	var all []*proposedSeries
+	var err error
+	if sf.cfg.LoreArchive != "" {
+		all, err = sf.pollLore()
+	} else {
+		all, err = sf.pollRefs()
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ret []*proposedSeries
+	for _, series := range all {
+		if _, ok := sf.state.Processed[series.id]; ok {
+			sf.state.Processed[series.id] = time.Now()
+		} else {
+			ret = append(ret, series)
+		}
+	}
+	for id, seen := range sf.state.Processed {
+		if time.Since(seen) > seriesRetention {
+			delete(sf.state.Processed, id)
+		}
+	}
+	for commit, base := range sf.state.Bases {
+		if time.Since(base.Time) > seriesRetention {
+			delete(sf.state.Bases, commit)
+		}
+	}
+	return ret, config.SaveFile(sf.stateFile(), sf.state)
+}
+
+func (sf *seriesFuzzer) pollLore() ([]*proposedSeries, error) {
+	archive := vcs.NewLKMLRepo(sf.cfg.LoreArchive)
+	head, err := archive.HeadCommit()
+	if err != nil {
+		return nil, err
+	}
+	if sf.state.LoreHead == "" {
+		// Don't fuzz the whole archive history, start with the new messages.
+		sf.state.LoreHead = head.Hash
+		return nil, config.SaveFile(sf.stateFile(), sf.state)
+	}
+	if sf.state.LoreHead != head.Hash {
+		hashes, err := archive.ListCommitHashes(sf.state.LoreHead + ".." + head.Hash)
+		if err != nil {
+			return nil, err
+		}
+		for _, hash := range hashes {
+			if hash == "" {
+				continue
+			}
+			msg, err := sf.readLoreMessage(archive, hash)
+			if err != nil {
+				return nil, err
+			}
+			if msg != nil {
+				sf.state.LoreMessages = append(sf.state.LoreMessages, &loreMessage{
+					Hash: hash,
+					Date: msg.Date,
+					msg:  msg,
+				})
+			}
+		}
+		sf.state.LoreHead = head.Hash
+	}
+	var recent []*loreMessage
+	var messages []*email.Email
+	for _, m := range sf.state.LoreMessages {
+		if time.Since(m.Date) >= seriesMessageAge {
+			continue
+		}
+		if m.msg == nil {
+			// The messages are not persisted, only their hashes. Re-read them after restart.
+			msg, err := sf.readLoreMessage(archive, m.Hash)
+			if err != nil {
+				return nil, err
+			}
+			if msg == nil {
+				continue
+			}
+			m.msg = msg
+		}
+		recent = append(recent, m)
+		messages = append(messages, m.msg)
+	}
+	sf.state.LoreMessages = recent
+	var ret []*proposedSeries
+	for _, series := range lore.PatchSeries(lore.Threads(messages)) {
+		ret = append(ret, &proposedSeries{
+			id:   series.MessageID,
+			mail: series,
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].id < ret[j].id
+	})
+	return ret, nil
+}
+
+// readLoreMessage returns nil if the message can't be parsed.
+func (sf *seriesFuzzer) readLoreMessage(archive vcs.Repo, hash string) (*email.Email, error) {
+	data, err := archive.Object("m", hash)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := email.Parse(bytes.NewReader(data), nil, nil, nil)
+	if err != nil {
+		log.Logf(1, "%v: failed to parse lore message %v: %v", sf.mgr.name, hash, err)
+		return nil, nil
+	}
+	return msg, nil
+}
+
+func (sf *seriesFuzzer) pollRefs() ([]*proposedSeries, error) {
+	lister, ok := sf.repo.(vcs.RefLister)
+	if !ok {
+		return nil, fmt.Errorf("ref listing is not supported for %v", sf.mgr.managercfg.TargetOS)
+	}
+	refs, err := lister.ListRefs(sf.cfg.Repo, sf.cfg.RefPattern)
+	if err != nil {
+		return nil, err
+	}
+	var ret []*proposedSeries
+	for ref, hash := range refs {
+		ret = append(ret, &proposedSeries{
+			id:   ref + "@" + hash,
+			ref:  ref,
+			hash: hash,
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].id < ret[j].id
+	})
+	return ret, nil
+}
+
+func (sf *seriesFuzzer) process(series *proposedSeries) error {
+	base, files, err := sf.checkoutBase(series)
+	if err != nil || base == nil {
+		return err
+	}
+	// Fuzz the base tree first to know which crashes are not related to the series.
+	known, err := sf.baseCrashes(base, files)
+	if err != nil {
+		return err
+	}
+	for title := range readSeriesCrashes(sf.mgr.workDir) {
+		known[title] = true
+	}
+
+	if series.mail != nil {
+		for _, patch := range series.mail.Patches {
+			if err := vcs.Patch(sf.kernelDir, []byte(patch.Diff)); err != nil {
+				log.Logf(0, "%v: series %v does not apply: %v", sf.mgr.name, series, err)
+				return nil
+			}
+		}
+	} else if _, err := sf.repo.SwitchCommit(series.hash); err != nil {
+		return err
+	}
+	seriesImage := filepath.Join(sf.dir, "image")
+	if err := sf.build(seriesImage); err != nil {
+		log.Logf(0, "%v: series %v failed to build: %v", sf.mgr.name, series, err)
+		return nil
+	}
+	crashes, err := sf.fuzz("series", seriesImage, sf.focusFiles(files), base.Hash, true)
+	if err != nil {
+		return err
+	}
+	findings := newSeriesFindings(crashes, known)
+	log.Logf(0, "%v: series %v: %v crashes, %v new", sf.mgr.name, series, len(crashes), len(findings))
+	return sf.report(series, base, findings)
+}
+
+// checkoutBase checks out the commit the series is based on and returns it along with the modified files.
+// It returns nil commit if the series can't be fuzzed.
+func (sf *seriesFuzzer) checkoutBase(series *proposedSeries) (*vcs.Commit, []string, error) {
+	mgrcfg := sf.mgr.mgrcfg
+	if series.mail != nil {
+		if series.mail.BaseCommit == "" {
+			base, err := sf.repo.Poll(mgrcfg.Repo, mgrcfg.Branch)
+			return base, series.mail.Files(), err
+		}
+		base, err := sf.repo.CheckoutCommit(mgrcfg.Repo, series.mail.BaseCommit)
+		if err != nil {
+			// The series is based on a different tree, it may not apply or may behave differently
+			// on top of our tree.
+			log.Logf(0, "%v: series %v: failed to check out base commit %v: %v",
+				sf.mgr.name, series, series.mail.BaseCommit, err)
+			return nil, nil, nil
+		}
+		return base, series.mail.Files(), nil
+	}
+	head, err := sf.repo.Poll(mgrcfg.Repo, mgrcfg.Branch)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := sf.repo.CheckoutCommit(sf.cfg.Repo, series.hash); err != nil {
+		return nil, nil, err
+	}
+	bases, err := sf.repo.MergeBases(series.hash, head.Hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(bases) == 0 {
+		return nil, nil, fmt.Errorf("no merge base with %v", head.Hash)
+	}
+	base := bases[0]
+	differ, ok := sf.repo.(vcs.Differ)
+	if !ok {
+		return nil, nil, fmt.Errorf("diffs are not supported for %v", sf.mgr.managercfg.TargetOS)
+	}
+	files, err := differ.ChangedFiles(base.Hash, series.hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := sf.repo.SwitchCommit(base.Hash); err != nil {
+		return nil, nil, err
+	}
+	return base, files, nil
+}
+
+// baseCrashes returns titles of the crashes found on the base tree with the fuzzing focused on the files.
+// The base tree (checked out in kernelDir) is fuzzed only if it was not fuzzed for the same files before.
+func (sf *seriesFuzzer) baseCrashes(base *vcs.Commit, files []string) (map[string]bool, error) {
+	cached := sf.state.Bases[base.Hash]
+	if cached == nil {
+		cached = &seriesBase{}
+	}
+	allFiles := mergeSeriesFiles(cached.Files, files)
+	if len(allFiles) != len(cached.Files) {
+		baseImage := filepath.Join(sf.dir, "base-image")
+		if err := sf.build(baseImage); err != nil {
+			return nil, fmt.Errorf("failed to build the base: %w", err)
+		}
+		// Focus on the files of all series with this base, so that the results can be reused.
+		crashes, err := sf.fuzz("base", baseImage, sf.focusFiles(allFiles), base.Hash, false)
+		if err != nil {
+			return nil, err
+		}
+		if sf.stopped() {
+			return nil, fmt.Errorf("the base fuzzing was interrupted")
+		}
+		titles := make(map[string]bool)
+		for _, title := range cached.Crashes {
+			titles[title] = true
+		}
+		for title := range crashes {
+			titles[title] = true
+		}
+		cached.Crashes = nil
+		for title := range titles {
+			cached.Crashes = append(cached.Crashes, title)
+		}
+		sort.Strings(cached.Crashes)
+		cached.Files = allFiles
+		sf.state.Bases[base.Hash] = cached
+	} else {
+		log.Logf(0, "%v: using the cached results of fuzzing the base %v", sf.mgr.name, base.Hash)
+	}
+	cached.Time = time.Now()
+	if err := config.SaveFile(sf.stateFile(), sf.state); err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool)
+	for _, title := range cached.Crashes {
+		known[title] = true
+	}
+	return known, nil
+}
+
+func mergeSeriesFiles(a, b []string) []string {
+	files := make(map[string]bool)
+	for _, file := range append(append([]string{}, a...), b...) {
+		files[file] = true
+	}
+	var ret []string
+	for file := range files {
+		ret = append(ret, file)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func (sf *seriesFuzzer) build(imageDir string) error {
+	buildSem.Wait()
+	defer buildSem.Signal()
+	if err := os.RemoveAll(imageDir); err != nil {
+		return err
+	}
+	if err := osutil.MkdirAll(imageDir); err != nil {
+		return err
+	}
+	mgrcfg := sf.mgr.mgrcfg
+	_, err := build.Image(build.Params{
+		TargetOS:     sf.mgr.managercfg.TargetOS,
+		TargetArch:   sf.mgr.managercfg.TargetVMArch,
+		VMType:       sf.mgr.managercfg.Type,
+		KernelDir:    sf.kernelDir,
+		OutputDir:    imageDir,
+		Compiler:     mgrcfg.Compiler,
+		Linker:       mgrcfg.Linker,
+		Ccache:       mgrcfg.Ccache,
+		UserspaceDir: mgrcfg.Userspace,
+		CmdlineFile:  mgrcfg.KernelCmdline,
+		SysctlFile:   mgrcfg.KernelSysctl,
+		Config:       sf.mgr.configData,
+		Build:        mgrcfg.Build,
+		BuildCPUs:    sf.mgr.cfg.BuildCPUs,
+	})
+	return err
+}
+
+// focusFiles returns coverage filters for the modified source files that are compiled into the kernel.
+// The coverage filter fails if a filter does not match anything, so the other files are skipped.
+func (sf *seriesFuzzer) focusFiles(files []string) []string {
+	var ret []string
+	for _, file := range files {
+		if !strings.HasSuffix(file, ".c") {
+			continue
+		}
+		obj := filepath.Join(sf.kernelDir, filepath.FromSlash(strings.TrimSuffix(file, ".c")+".o"))
+		if osutil.IsExist(obj) {
+			ret = append(ret, "^"+regexp.QuoteMeta(file)+"$")
+		}
+	}
+	return ret
+}
+
+// fuzz runs a time-boxed fuzzing session on the image and returns the found crashes.
+// If reproduce is set, the manager tries to reproduce the crashes.
+func (sf *seriesFuzzer) fuzz(name, imageDir string, focus []string, tag string,
+	reproduce bool) (map[string]*seriesCrash, error) {
+	// The series are fuzzed on extra VMs.
+	testSem.Wait()
+	defer testSem.Signal()
+	workdir := filepath.Join(sf.dir, name+"-workdir")
+	if err := os.RemoveAll(workdir); err != nil {
+		return nil, err
+	}
+	if err := osutil.MkdirAll(workdir); err != nil {
+		return nil, err
+	}
+	// Start with the corpus of the main manager to reach the modified code faster.
+	if corpus := filepath.Join(sf.mgr.workDir, "corpus.db"); osutil.IsExist(corpus) {
+		if err := osutil.CopyFile(corpus, filepath.Join(workdir, "corpus.db")); err != nil {
+			return nil, err
+		}
+	}
+	mgrcfg := new(mgrconfig.Config)
+	*mgrcfg = *sf.mgr.managercfg
+	mgrcfg.Name += "-series"
+	mgrcfg.Tag = tag
+	mgrcfg.HTTP = fmt.Sprintf("localhost:%v", sf.mgr.mgrcfg.seriesHTTPPort)
+	mgrcfg.RPC = fmt.Sprintf(":%v", sf.mgr.mgrcfg.seriesRPCPort)
+	mgrcfg.Workdir = workdir
+	// The findings are reported in a different way.
+	mgrcfg.DashboardClient = ""
+	mgrcfg.DashboardAddr = ""
+	mgrcfg.HubClient = ""
+	mgrcfg.HubAddr = ""
+	mgrcfg.Reproduce = reproduce
+	mgrcfg.CovFilter = mgrconfig.CovFilterCfg{Files: focus}
+	if err := instance.SetConfigImage(mgrcfg, imageDir, false); err != nil {
+		return nil, err
+	}
+	if err := instance.OverrideVMCount(mgrcfg, sf.cfg.VMs); err != nil {
+		return nil, err
+	}
+	mgrcfg.KernelSrc = path.Join(sf.kernelDir, sf.mgr.mgrcfg.KernelSrcSuffix)
+	if err := mgrconfig.Complete(mgrcfg); err != nil {
+		return nil, fmt.Errorf("bad manager config: %w", err)
+	}
+	cfgFile := filepath.Join(sf.dir, name+"-manager.cfg")
+	if err := config.SaveFile(cfgFile, mgrcfg); err != nil {
+		return nil, err
+	}
+	bin := filepath.FromSlash("syzkaller/current/bin/syz-manager")
+	logFile := filepath.Join(sf.dir, name+"-manager.log")
+	cmd := NewManagerCmd(mgrcfg.Name, logFile, sf.mgr.Errorf, bin, "-config", cfgFile, "-vv", "1")
+	select {
+	case <-time.After(time.Duration(sf.cfg.FuzzingMinutes) * time.Minute):
+	case <-sf.mgr.stop:
+	}
+	cmd.Close()
+	return readSeriesCrashes(workdir), nil
+}
+
+// readSeriesCrashes returns the crashes saved in the manager workdir by titles.
+func readSeriesCrashes(workdir string) map[string]*seriesCrash {
+	crashDir := filepath.Join(workdir, "crashes")
+	entries, err := os.ReadDir(crashDir)
+	if err != nil {
+		return map[string]*seriesCrash{}
+	}
+	ret := make(map[string]*seriesCrash)
+	for _, ent := range entries {
+		dir := filepath.Join(crashDir, ent.Name())
+		desc, err := os.ReadFile(filepath.Join(dir, "description"))
+		if err != nil {
+			continue
+		}
+		crash := &seriesCrash{}
+		crash.report, _ = os.ReadFile(filepath.Join(dir, "report0"))
+		crash.repro, _ = os.ReadFile(filepath.Join(dir, "repro.prog"))
+		ret[strings.TrimSpace(string(desc))] = crash
+	}
+	return ret
+}
+
+// newSeriesFindings returns the reproduced crashes that are not known for the base tree.
+// Crashes without reproducers may be caused by the previous programs executed in the same VM,
+// so they are too noisy to be reported to the series authors.
+func newSeriesFindings(crashes map[string]*seriesCrash, known map[string]bool) []dashapi.SeriesFinding {
+	var ret []dashapi.SeriesFinding
+	for title, crash := range crashes {
+		if known[title] || len(crash.repro) == 0 {
+			continue
+		}
+		ret = append(ret, dashapi.SeriesFinding{
+			Title:    title,
+			Report:   crash.report,
+			ReproSyz: crash.repro,
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Title < ret[j].Title
+	})
+	return ret
+}
+
+func (sf *seriesFuzzer) report(series *proposedSeries, base *vcs.Commit, findings []dashapi.SeriesFinding) error {
+	for _, finding := range findings {
+		log.Logf(0, "%v: series %v: %v", sf.mgr.name, series, finding.Title)
+	}
+	if len(findings) == 0 || series.mail == nil || sf.mgr.dash == nil {
+		return nil
+	}
+	return sf.mgr.dash.ReportSeriesFindings(&dashapi.SeriesFindingsReq{
+		Manager:      sf.mgr.name,
+		MessageID:    series.mail.MessageID,
+		Subject:      series.mail.Subject,
+		Link:         series.mail.Link,
+		Cc:           series.mail.Cc,
+		KernelRepo:   sf.mgr.mgrcfg.Repo,
+		KernelBranch: sf.mgr.mgrcfg.Branch,
+		BaseCommit:   base.Hash,
+		Findings:     findings,
+	})
+}
@@ -0,0 +1,184 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/dashboard/dashapi"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/vcs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeriesPollLore(t *testing.T) {
+	baseDir := t.TempDir()
+	archive := vcs.MakeTestRepo(t, filepath.Join(baseDir, "lore"))
+	addMessage := func(id, inReplyTo, subject string) {
+		msg := fmt.Sprintf("Date: %v\nSubject: %v\nMessage-ID: <%v>\n",
+			time.Now().Format(time.RFC1123Z), subject, id)
+		if inReplyTo != "" {
+			msg += fmt.Sprintf("In-Reply-To: <%v>\n", inReplyTo)
+		}
+		msg += fmt.Sprintf(`From: UserA <a@user.com>
+To: list@lists.com
+Content-Type: text/plain
+
+Description.
+
+diff --git a/%[1]v.c b/%[1]v.c
+--- a/%[1]v.c
++++ b/%[1]v.c
+@@ -1,1 +1,1 @@
+-a
++b
+`, id)
+		if err := osutil.WriteFile(filepath.Join(archive.Dir, "m"), []byte(msg)); err != nil {
+			t.Fatal(err)
+		}
+		archive.Git("add", "m")
+		archive.Git("commit", "-m", id)
+	}
+	addMessage("old", "", "[PATCH] old patch")
+
+	managercfg := &mgrconfig.Config{Type: "qemu"}
+	managercfg.TargetOS = "linux"
+	mgr := &Manager{
+		name:    "test",
+		workDir: filepath.Join(baseDir, "manager", "workdir"),
+		mgrcfg: &ManagerConfig{
+			Series: &SeriesConfig{LoreArchive: archive.Dir},
+		},
+		managercfg: managercfg,
+	}
+	sf, err := newSeriesFuzzer(mgr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The existing messages are ignored.
+	list, err := sf.poll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, list, 0)
+
+	addMessage("A-0", "", "[PATCH 0/2] cover")
+	addMessage("A-1", "A-0", "[PATCH 1/2] first")
+	addMessage("B-1", "", "[PATCH 1/2] incomplete")
+	list, err = sf.poll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, list, 0)
+
+	// Messages of incomplete series survive restarts.
+	sf, err = newSeriesFuzzer(mgr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addMessage("A-2", "A-0", "[PATCH 2/2] second")
+	list, err = sf.poll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !assert.Len(t, list, 1) {
+		return
+	}
+	series := list[0].mail
+	assert.Equal(t, "<A-0>", series.MessageID)
+	assert.Equal(t, []string{"A-1.c", "A-2.c"}, series.Files())
+
+	// The processed series are not returned again.
+	sf.state.Processed[list[0].id] = time.Now()
+	list, err = sf.poll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, list, 0)
+}
+
+func TestSeriesFindings(t *testing.T) {
+	workdir := t.TempDir()
+	for i, title := range []string{"KASAN: use-after-free in foo", "WARNING in bar", "INFO: task hung in baz"} {
+		dir := filepath.Join(workdir, "crashes", fmt.Sprint(i))
+		if err := osutil.MkdirAll(dir); err != nil {
+			t.Fatal(err)
+		}
+		if err := osutil.WriteFile(filepath.Join(dir, "description"), []byte(title+"\n")); err != nil {
+			t.Fatal(err)
+		}
+		if err := osutil.WriteFile(filepath.Join(dir, "report0"), []byte("report of "+title)); err != nil {
+			t.Fatal(err)
+		}
+		if i == 2 {
+			// Not reproduced.
+			continue
+		}
+		if err := osutil.WriteFile(filepath.Join(dir, "repro.prog"), []byte("repro of "+title)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	crashes := readSeriesCrashes(workdir)
+	assert.Len(t, crashes, 3)
+	assert.Equal(t, &seriesCrash{
+		report: []byte("report of WARNING in bar"),
+		repro:  []byte("repro of WARNING in bar"),
+	}, crashes["WARNING in bar"])
+	assert.Len(t, readSeriesCrashes(filepath.Join(workdir, "missing")), 0)
+
+	known := map[string]bool{
+		"WARNING in bar":  true,
+		"INFO: task hung": true,
+	}
+	assert.Equal(t, []dashapi.SeriesFinding{
+		{
+			Title:    "KASAN: use-after-free in foo",
+			Report:   []byte("report of KASAN: use-after-free in foo"),
+			ReproSyz: []byte("repro of KASAN: use-after-free in foo"),
+		},
+	}, newSeriesFindings(crashes, known))
+}
+
+func TestSeriesBaseCache(t *testing.T) {
+	sf := &seriesFuzzer{
+		mgr: &Manager{name: "test"},
+		dir: t.TempDir(),
+		state: seriesState{
+			Bases: map[string]*seriesBase{
+				"1111": {
+					Files:   []string{"mm/foo.c", "net/bar.c"},
+					Crashes: []string{"WARNING in bar"},
+				},
+			},
+		},
+	}
+	// The base was already fuzzed with focus on the files, so it's not built and fuzzed again.
+	known, err := sf.baseCrashes(&vcs.Commit{Hash: "1111"}, []string{"net/bar.c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]bool{"WARNING in bar": true}, known)
+	assert.False(t, sf.state.Bases["1111"].Time.IsZero())
+	assert.Equal(t, []string{"a.c", "b.c", "c.c"}, mergeSeriesFiles([]string{"b.c", "a.c"}, []string{"c.c", "b.c"}))
+}
+
+func TestSeriesFocusFiles(t *testing.T) {
+	kernelDir := t.TempDir()
+	if err := osutil.MkdirAll(filepath.Join(kernelDir, "net")); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range []string{"net/foo.o", "net/foo+bar.o"} {
+		if err := osutil.WriteFile(filepath.Join(kernelDir, file), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sf := &seriesFuzzer{kernelDir: kernelDir}
+	focus := sf.focusFiles([]string{"net/foo.c", "net/foo+bar.c", "net/not_built.c", "include/foo.h"})
+	assert.Equal(t, []string{`^net/foo\.c$`, `^net/foo\+bar\.c$`}, focus)
+}
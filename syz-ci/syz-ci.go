@@ -125,10 +125,16 @@ type Config struct {
 	// in bisection results.
 	// Values of the map are ignored and can e.g. serve as comments.
 	BisectIgnore map[string]string `json:"bisect_ignore"`
+	// If set, cause bisection results are verified by reverting the culprit
+	// on top of the crashing commit and running the reproducer once more.
+	BisectVerifyCulprit bool `json:"bisect_verify_culprit"`
 	// Extra commits to cherry-pick to older kernel revisions.
 	// The list is concatenated with the similar parameter from ManagerConfig.
 	BisectBackports []vcs.BackportCommit `json:"bisect_backports"`
 	Ccache          string               `json:"ccache"`
+	// Dir for the kernel build cache shared by the job processors (optional).
+	// The cache keeps build trees for incremental kernel builds and the recently built images.
+	BuildCache string `json:"build_cache"`
 	// BuildCPUs defines the maximum number of parallel kernel build threads.
 	BuildCPUs int              `json:"build_cpus"`
 	Managers  []*ManagerConfig `json:"managers"`
@@ -214,30 +220,39 @@ type ManagerConfig struct {
 	// fuzzing won't be started on this instance.
 	// By default it's 30 days.
 	MaxKernelLagDays int `json:"max_kernel_lag_days"`
-	managercfg       *mgrconfig.Config
+	// Fuzzing of proposed patch series (optional), see SeriesConfig.
+	Series     *SeriesConfig `json:"series"`
+	managercfg *mgrconfig.Config
 
 	// Auto-assigned ports used by test instances.
 	testHTTPPort int
 	testRPCPort  int
+	// Auto-assigned ports used by series fuzzing instances.
+	seriesHTTPPort int
+	seriesRPCPort  int
 }
 
 type ManagerJobs struct {
-	TestPatches bool `json:"test_patches"` // enable patch testing jobs
-	PollCommits bool `json:"poll_commits"` // poll info about fix commits
-	BisectCause bool `json:"bisect_cause"` // do cause bisection
-	BisectFix   bool `json:"bisect_fix"`   // do fix bisection
+	TestPatches  bool `json:"test_patches"`  // enable patch testing jobs
+	PollCommits  bool `json:"poll_commits"`  // poll info about fix commits
+	BisectCause  bool `json:"bisect_cause"`  // do cause bisection
+	BisectFix    bool `json:"bisect_fix"`    // do fix bisection
+	BisectConfig bool `json:"bisect_config"` // do config bisection (needs kernel_baseline_config)
+	FixSearch    bool `json:"fix_search"`    // search for fixes among commits that modify the guilty function
 }
 
 func (m *ManagerJobs) AnyEnabled() bool {
-	return m.TestPatches || m.PollCommits || m.BisectCause || m.BisectFix
+	return m.TestPatches || m.PollCommits || m.BisectCause || m.BisectFix || m.BisectConfig || m.FixSearch
 }
 
 func (m *ManagerJobs) Filter(filter *ManagerJobs) *ManagerJobs {
 	return &ManagerJobs{
-		TestPatches: m.TestPatches && filter.TestPatches,
-		PollCommits: m.PollCommits && filter.PollCommits,
-		BisectCause: m.BisectCause && filter.BisectCause,
-		BisectFix:   m.BisectFix && filter.BisectFix,
+		TestPatches:  m.TestPatches && filter.TestPatches,
+		PollCommits:  m.PollCommits && filter.PollCommits,
+		BisectCause:  m.BisectCause && filter.BisectCause,
+		BisectFix:    m.BisectFix && filter.BisectFix,
+		BisectConfig: m.BisectConfig && filter.BisectConfig,
+		FixSearch:    m.FixSearch && filter.FixSearch,
 	}
 }
 
@@ -294,6 +309,19 @@ func main() {
 				defer wg.Done()
 				mgr.loop()
 			}()
+			if mgr.mgrcfg.Series == nil {
+				continue
+			}
+			sf, err := newSeriesFuzzer(mgr)
+			if err != nil {
+				log.Errorf("failed to create series fuzzer for %v: %v", mgr.name, err)
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				sf.loop()
+			}()
 		}
 	}
 	jp, err := newJobManager(cfg, managers, shutdownPending)
@@ -398,6 +426,7 @@ func loadConfig(filename string) (*Config, error) {
 	cfg.SyzkallerDescriptions = osutil.Abs(cfg.SyzkallerDescriptions)
 	cfg.BisectBinDir = osutil.Abs(cfg.BisectBinDir)
 	cfg.Ccache = osutil.Abs(cfg.Ccache)
+	cfg.BuildCache = osutil.Abs(cfg.BuildCache)
 	var managers []*ManagerConfig
 	for _, mgr := range cfg.Managers {
 		if mgr.Disabled == "" {
@@ -455,6 +484,19 @@ func loadManagerConfig(cfg *Config, mgr *ManagerConfig) error {
 	cfg.ManagerPort++
 	mgr.testRPCPort = cfg.RPCPort
 	cfg.RPCPort++
+	if mgr.Series != nil {
+		mgr.seriesHTTPPort = cfg.ManagerPort
+		cfg.ManagerPort++
+		mgr.seriesRPCPort = cfg.RPCPort
+		cfg.RPCPort++
+		mgr.Series.LoreArchive = osutil.Abs(mgr.Series.LoreArchive)
+		if mgr.Series.FuzzingMinutes == 0 {
+			mgr.Series.FuzzingMinutes = 120
+		}
+		if mgr.Series.VMs == 0 {
+			mgr.Series.VMs = 2
+		}
+	}
 	// Note: we don't change Compiler/Ccache because it may be just "gcc" referring
 	// to the system binary, or pkg/build/netbsd.go uses "g++" and "clang++" as special marks.
 	mgr.Userspace = osutil.Abs(mgr.Userspace)
@@ -0,0 +1,158 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/syzkaller/pkg/corpus"
+	"github.com/google/syzkaller/pkg/db"
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/fuzzer"
+	"github.com/google/syzkaller/pkg/fuzzer/queue"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/manager"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/signal"
+	"github.com/google/syzkaller/pkg/subsystem"
+	_ "github.com/google/syzkaller/pkg/subsystem/lists"
+)
+
+// corpusDistiller executes every corpus program once to collect its fresh signal and execution time,
+// then selects the subset of programs that preserves the signal with the smallest cost
+// and saves it to workdir/distilled.
+type corpusDistiller struct {
+	mgr       *Manager
+	cfg       corpus.DistillConfig
+	extractor *subsystem.Extractor // nil if there is no subsystem quota
+
+	mu         sync.Mutex
+	candidates []fuzzer.Candidate
+	inputs     []*corpus.DistillInput
+	seq        int
+	done       int
+}
+
+func newCorpusDistiller(mgr *Manager, candidates []fuzzer.Candidate) *corpusDistiller {
+	objective, err := corpus.ParseDistillObjective(*flagDistillObjective)
+	if err != nil {
+		log.Fatal(err)
+	}
+	cd := &corpusDistiller{
+		mgr: mgr,
+		cfg: corpus.DistillConfig{
+			Objective:      objective,
+			SubsystemQuota: *flagDistillQuota,
+		},
+		candidates: candidates,
+		inputs:     make([]*corpus.DistillInput, len(candidates)),
+	}
+	if cd.cfg.SubsystemQuota != 0 {
+		list := subsystem.GetList(mgr.cfg.TargetOS)
+		if list == nil {
+			log.Fatalf("no subsystem list for %v, -distill-quota is not supported", mgr.cfg.TargetOS)
+		}
+		cd.extractor = subsystem.MakeExtractor(list)
+	}
+	if !mgr.cfg.Cover {
+		log.Logf(0, "coverage is disabled, distilling the corpus by the fallback signal")
+	}
+	log.Logf(0, "distilling %v programs (objective: %v)", len(candidates), objective)
+	if len(candidates) == 0 {
+		go cd.finish()
+	}
+	return cd
+}
+
+func (cd *corpusDistiller) Next() *queue.Request {
+	cd.mu.Lock()
+	defer cd.mu.Unlock()
+	if cd.seq >= len(cd.candidates) {
+		return nil
+	}
+	idx := cd.seq
+	cd.seq++
+	p := cd.candidates[idx].Prog
+	req := &queue.Request{
+		Prog: p,
+		ExecOpts: flatrpc.ExecOpts{
+			ExecFlags: flatrpc.ExecFlagCollectSignal,
+		},
+		ReturnAllSignal: make([]int, 0, len(p.Calls)+1),
+		Important:       true,
+	}
+	for i := range p.Calls {
+		req.ReturnAllSignal = append(req.ReturnAllSignal, i)
+	}
+	req.ReturnAllSignal = append(req.ReturnAllSignal, -1)
+	req.OnDone(func(req *queue.Request, res *queue.Result) bool {
+		cd.saveResult(idx, res)
+		return true
+	})
+	return req
+}
+
+func (cd *corpusDistiller) saveResult(idx int, res *queue.Result) {
+	inp := &corpus.DistillInput{
+		Prog: cd.candidates[idx].Prog,
+	}
+	if res.Status == queue.Success && res.Info != nil {
+		for _, call := range res.Info.Calls {
+			if call != nil {
+				inp.Signal.Merge(signal.FromRaw(call.Signal, 0))
+			}
+		}
+		if res.Info.Extra != nil {
+			inp.Signal.Merge(signal.FromRaw(res.Info.Extra.Signal, 0))
+		}
+		inp.Elapsed = time.Duration(res.Info.Elapsed)
+	}
+	if cd.extractor != nil {
+		for _, s := range cd.extractor.Extract([]*subsystem.Crash{{SyzRepro: inp.Prog.Serialize()}}) {
+			inp.Subsystems = append(inp.Subsystems, s.Name)
+		}
+	}
+	cd.mu.Lock()
+	cd.inputs[idx] = inp
+	cd.done++
+	finished := cd.done == len(cd.candidates)
+	cd.mu.Unlock()
+	if finished {
+		go cd.finish()
+	}
+}
+
+func (cd *corpusDistiller) finish() {
+	res := corpus.Distill(cd.inputs, cd.cfg)
+	report := new(bytes.Buffer)
+	res.WriteReport(report)
+	log.Logf(0, "corpus distillation results:\n%s", report.Bytes())
+	if err := cd.save(res, report.Bytes()); err != nil {
+		log.Fatalf("failed to save distilled corpus: %v", err)
+	}
+	cd.mgr.exit("corpus distillation")
+}
+
+func (cd *corpusDistiller) save(res *corpus.DistillResult, report []byte) error {
+	dir := filepath.Join(cd.mgr.cfg.Workdir, "distilled")
+	if err := osutil.MkdirAll(dir); err != nil {
+		return err
+	}
+	var records []db.Record
+	for _, inp := range res.Inputs {
+		records = append(records, db.Record{Val: inp.Prog.Serialize()})
+	}
+	file := filepath.Join(dir, "corpus.db")
+	if err := db.Create(file, manager.CurrentDBVersion, records); err != nil {
+		return err
+	}
+	if err := osutil.WriteFile(filepath.Join(dir, "report.txt"), report); err != nil {
+		return err
+	}
+	log.Logf(0, "distilled corpus saved to %v", file)
+	return nil
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/google/syzkaller/pkg/log"
 	"github.com/google/syzkaller/pkg/manager"
 	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/signal"
 	"github.com/google/syzkaller/pkg/stat"
 	"github.com/google/syzkaller/pkg/vcs"
 	"github.com/google/syzkaller/prog"
@@ -53,6 +54,9 @@ func (mgr *Manager) initHTTP() {
 	handle("/cover", mgr.httpCover)
 	handle("/subsystemcover", mgr.httpSubsystemCover)
 	handle("/modulecover", mgr.httpModuleCover)
+	handle("/frontier", mgr.httpFrontier)
+	handle("/coverdiff", mgr.httpCoverDiff)
+	handle("/cover/who", mgr.httpCoverWho)
 	handle("/prio", mgr.httpPrio)
 	handle("/file", mgr.httpFile)
 	handle("/report", mgr.httpReport)
@@ -64,6 +68,7 @@ func (mgr *Manager) initHTTP() {
 	handle("/input", mgr.httpInput)
 	handle("/debuginput", mgr.httpDebugInput)
 	handle("/modules", mgr.modulesInfo)
+	handle("/modulestats", mgr.httpModuleStats)
 	handle("/jobs", mgr.httpJobs)
 	// Browsers like to request this, without special handler this goes to / handler.
 	handle("/favicon.ico", func(w http.ResponseWriter, r *http.Request) {})
@@ -86,6 +91,11 @@ func (mgr *Manager) httpSummary(w http.ResponseWriter, r *http.Request) {
 		Expert:       mgr.expertMode,
 		Log:          log.CachedLogOutput(),
 	}
+	mgr.mu.Lock()
+	if mgr.checkDiff != nil && !mgr.checkDiff.Empty() {
+		data.MachineCheckDiff = mgr.checkDiff.String()
+	}
+	mgr.mu.Unlock()
 
 	level := stat.Simple
 	if mgr.expertMode {
@@ -141,16 +151,27 @@ func (mgr *Manager) httpSyscalls(w http.ResponseWriter, r *http.Request) {
 	data := &UISyscallsData{
 		Name: mgr.cfg.Name,
 	}
+	var callTimes map[string]fuzzer.CallTime
+	if fuzzerObj := mgr.fuzzer.Load(); fuzzerObj != nil {
+		callTimes = fuzzerObj.CallTimes()
+	}
 	for c, cc := range mgr.collectSyscallInfo() {
 		var syscallID *int
 		if syscall, ok := mgr.target.SyscallMap[c]; ok {
 			syscallID = &syscall.ID
 		}
+		ct := callTimes[c]
 		data.Calls = append(data.Calls, UICallType{
-			Name:   c,
-			ID:     syscallID,
-			Inputs: cc.Count,
-			Cover:  len(cc.Cover),
+			Name:       c,
+			ID:         syscallID,
+			Inputs:     cc.Count,
+			Cover:      len(cc.Cover),
+			Execs:      ct.Count,
+			AvgTime:    ct.Avg().Microseconds(),
+			KernelTime: ct.KernelAvg().Microseconds(),
+			MaxTime:    ct.Max.Microseconds(),
+			Slow:       ct.Slow,
+			Blocked:    ct.Blocked,
 		})
 	}
 	sort.Slice(data.Calls, func(i, j int) bool {
@@ -287,6 +308,9 @@ const (
 	DoRawCover
 	DoFilterPCs
 	DoCoverJSONL
+	DoLCOV
+	DoCobertura
+	DoFrontier
 )
 
 func (mgr *Manager) httpCover(w http.ResponseWriter, r *http.Request) {
@@ -298,7 +322,18 @@ func (mgr *Manager) httpCover(w http.ResponseWriter, r *http.Request) {
 		mgr.httpCoverCover(w, r, DoCoverJSONL)
 		return
 	}
-	mgr.httpCoverCover(w, r, DoHTML)
+	switch r.FormValue("format") {
+	case "", "html":
+		mgr.httpCoverCover(w, r, DoHTML)
+	case "jsonl":
+		mgr.httpCoverCover(w, r, DoCoverJSONL)
+	case "lcov":
+		mgr.httpCoverCover(w, r, DoLCOV)
+	case "cobertura":
+		mgr.httpCoverCover(w, r, DoCobertura)
+	default:
+		http.Error(w, fmt.Sprintf("unknown coverage format %q", r.FormValue("format")), http.StatusBadRequest)
+	}
 }
 
 func (mgr *Manager) httpSubsystemCover(w http.ResponseWriter, r *http.Request) {
@@ -317,8 +352,151 @@ func (mgr *Manager) httpModuleCover(w http.ResponseWriter, r *http.Request) {
 	mgr.httpCoverCover(w, r, DoModuleCover)
 }
 
+func (mgr *Manager) httpFrontier(w http.ResponseWriter, r *http.Request) {
+	if !mgr.cfg.Cover {
+		mgr.httpCoverFallback(w, r)
+		return
+	}
+	mgr.httpCoverCover(w, r, DoFrontier)
+}
+
+// httpCoverDiff compares the current corpus coverage with the raw coverage
+// (in the /rawcover format) posted in the request body, e.g.:
+// curl --data-binary @old.rawcover 'http://manager/coverdiff?format=json'.
+// The base coverage must be collected on the same kernel build, use syz-cover -diff-base otherwise.
+func (mgr *Manager) httpCoverDiff(w http.ResponseWriter, r *http.Request) {
+	if !mgr.cfg.Cover {
+		http.Error(w, "coverage is not enabled", http.StatusInternalServerError)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "POST the base raw coverage", http.StatusMethodNotAllowed)
+		return
+	}
+	if !mgr.checkDone.Load() {
+		http.Error(w, "coverage is not ready, please try again later after fuzzer started", http.StatusInternalServerError)
+		return
+	}
+	basePCs, err := cover.ParseRawCover(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to parse the base coverage: %v", err), http.StatusBadRequest)
+		return
+	}
+	rg, err := mgr.reportGenerator.Get()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to generate coverage profile: %v", err), http.StatusInternalServerError)
+		return
+	}
+	mgr.mu.Lock()
+	var progs []cover.Prog
+	for _, inp := range mgr.corpus.Items() {
+		progs = append(progs, cover.Prog{
+			Sig:  inp.Sig,
+			Data: string(inp.Prog.Serialize()),
+			PCs:  manager.CoverToPCs(mgr.cfg, inp.Cover),
+		})
+	}
+	mgr.mu.Unlock()
+	force := r.FormValue("force") != ""
+	baseCov, err := rg.LineCoverage(cover.HandlerParams{
+		Progs: []cover.Prog{{PCs: basePCs}},
+		Force: force,
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to process the base coverage: %v", err), http.StatusInternalServerError)
+		return
+	}
+	cov, err := rg.LineCoverage(cover.HandlerParams{
+		Progs: progs,
+		Force: force,
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to generate coverage profile: %v", err), http.StatusInternalServerError)
+		return
+	}
+	diff := cover.DiffCoverage(baseCov, cov)
+	if r.FormValue("format") == "json" {
+		w.Header().Set("Content-Type", ctApplicationJSON)
+		err = diff.WriteJSON(w)
+	} else {
+		err = diff.WriteHTML(w)
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to write coverage diff: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// maxCoverWhoInputs is the maximum number of programs listed by /cover/who.
+const maxCoverWhoInputs = 100
+
+// httpCoverWho lists corpus programs that cover a source line or a function, e.g.:
+// /cover/who?file=net/socket.c&line=1234 or /cover/who?function=__sys_socket[&file=net/socket.c].
+// Programs that cover more coverage points of the line/function and shorter programs go first.
+// format=json returns the programs in JSON.
+func (mgr *Manager) httpCoverWho(w http.ResponseWriter, r *http.Request) {
+	if !mgr.cfg.Cover {
+		http.Error(w, "coverage is not enabled", http.StatusInternalServerError)
+		return
+	}
+	if !mgr.checkDone.Load() {
+		http.Error(w, "coverage is not ready, please try again later after fuzzer started", http.StatusInternalServerError)
+		return
+	}
+	rg, err := mgr.reportGenerator.Get()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to generate coverage profile: %v", err), http.StatusInternalServerError)
+		return
+	}
+	data := &UICoverWho{
+		File:     r.FormValue("file"),
+		Function: r.FormValue("function"),
+	}
+	var pcs []uint64
+	switch {
+	case data.Function != "":
+		pcs, err = rg.FunctionPCs(data.File, data.Function)
+	case data.File != "" && r.FormValue("line") != "":
+		if data.Line, err = strconv.Atoi(r.FormValue("line")); err != nil {
+			http.Error(w, "bad line", http.StatusBadRequest)
+			return
+		}
+		pcs, err = rg.LinePCs(data.File, data.Line)
+	default:
+		http.Error(w, "specify file and line, or function", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	data.PCs = len(pcs)
+	items := mgr.corpus.CoveringItems(manager.PCsToCover(mgr.cfg, pcs))
+	data.Total = len(items)
+	if len(items) > maxCoverWhoInputs {
+		items = items[:maxCoverWhoInputs]
+	}
+	for _, item := range items {
+		data.Inputs = append(data.Inputs, &UICoverWhoInput{
+			Sig:     item.Item.Sig,
+			Short:   item.Item.Prog.String(),
+			Calls:   len(item.Item.Prog.Calls),
+			PCs:     item.PCs,
+			Program: string(item.Item.Prog.Serialize()),
+		})
+	}
+	if r.FormValue("format") == "json" {
+		w.Header().Set("Content-Type", ctApplicationJSON)
+		if err := json.NewEncoder(w).Encode(data); err != nil {
+			http.Error(w, fmt.Sprintf("failed to encode json: %v", err), http.StatusInternalServerError)
+		}
+		return
+	}
+	executeTemplate(w, coverWhoTemplate, data)
+}
+
 const ctTextPlain = "text/plain; charset=utf-8"
 const ctApplicationJSON = "application/json"
+const ctApplicationXML = "application/xml"
 
 func (mgr *Manager) httpCoverCover(w http.ResponseWriter, r *http.Request, funcFlag int) {
 	if !mgr.cfg.Cover {
@@ -348,6 +526,8 @@ func (mgr *Manager) httpCoverCover(w http.ResponseWriter, r *http.Request, funcF
 
 	mgr.mu.Lock()
 	var progs []cover.Prog
+	// Edge signal of the progs, it's used to compute branch coverage.
+	var edges signal.Signal
 	if sig := r.FormValue("input"); sig != "" {
 		inp := mgr.corpus.Item(sig)
 		if inp == nil {
@@ -372,6 +552,7 @@ func (mgr *Manager) httpCoverCover(w http.ResponseWriter, r *http.Request, funcF
 				PCs:  manager.CoverToPCs(mgr.cfg, inp.Cover),
 			})
 		}
+		edges = inp.Signal
 	} else {
 		call := r.FormValue("call")
 		for _, inp := range mgr.corpus.Items() {
@@ -383,9 +564,18 @@ func (mgr *Manager) httpCoverCover(w http.ResponseWriter, r *http.Request, funcF
 				Data: string(inp.Prog.Serialize()),
 				PCs:  manager.CoverToPCs(mgr.cfg, inp.Cover),
 			})
+			if call != "" {
+				edges.Merge(inp.Signal)
+			}
+		}
+		if call == "" {
+			edges = mgr.corpus.Signal()
 		}
 	}
 	mgr.mu.Unlock()
+	if !mgr.cfg.Experimental.CoverEdges {
+		edges = nil
+	}
 
 	var coverFilter map[uint64]struct{}
 	if r.FormValue("filter") != "" || funcFlag == DoFilterPCs {
@@ -401,6 +591,7 @@ func (mgr *Manager) httpCoverCover(w http.ResponseWriter, r *http.Request, funcF
 		Filter: coverFilter,
 		Debug:  r.FormValue("debug") != "",
 		Force:  r.FormValue("force") != "",
+		Signal: edges,
 	}
 
 	type handlerFuncType func(w io.Writer, params cover.HandlerParams) error
@@ -417,6 +608,9 @@ func (mgr *Manager) httpCoverCover(w http.ResponseWriter, r *http.Request, funcF
 		DoRawCover:       {rg.DoRawCover, ctTextPlain},
 		DoFilterPCs:      {rg.DoFilterPCs, ctTextPlain},
 		DoCoverJSONL:     {rg.DoCoverJSONL, ctApplicationJSON},
+		DoLCOV:           {rg.DoLCOV, ctTextPlain},
+		DoCobertura:      {rg.DoCobertura, ctApplicationXML},
+		DoFrontier:       {rg.DoFrontier, ""},
 	}
 
 	if ct := flagToFunc[funcFlag].contentType; ct != "" {
@@ -576,6 +770,53 @@ func (mgr *Manager) modulesInfo(w http.ResponseWriter, r *http.Request) {
 	w.Write(modules)
 }
 
+// /modulestats shows canonical kernel modules and per-module canonicalization diagnostics.
+func (mgr *Manager) httpModuleStats(w http.ResponseWriter, r *http.Request) {
+	if !mgr.checkDone.Load() {
+		http.Error(w, "info is not ready, please try again later after fuzzer started", http.StatusInternalServerError)
+		return
+	}
+	mgr.mu.Lock()
+	serv := mgr.serv
+	mgr.mu.Unlock()
+	var stats []cover.ModuleStat
+	if serv != nil {
+		stats = serv.ModuleStats()
+	}
+	data := &UIModulesData{}
+	modules := make(map[string]*UIModule)
+	for _, mod := range mgr.modules {
+		m := &UIModule{
+			Name:    mod.Name,
+			Addr:    mod.Addr,
+			Size:    mod.Size,
+			BuildID: mod.BuildID,
+			Path:    mod.Path,
+		}
+		modules[mod.Name] = m
+		data.Modules = append(data.Modules, m)
+	}
+	for _, stat := range stats {
+		m := modules[stat.Name]
+		if m == nil {
+			// The module was not loaded on the first instance or does not match the local object.
+			m = &UIModule{Name: stat.Name}
+			data.Modules = append(data.Modules, m)
+		}
+		m.Instances = stat.Instances
+		m.Relocated = stat.Relocated
+		m.Mismatches = stat.Mismatches
+		m.LastMismatch = stat.LastMismatch
+	}
+	sort.Slice(data.Modules, func(i, j int) bool {
+		if data.Modules[i].Mismatches != data.Modules[j].Mismatches {
+			return data.Modules[i].Mismatches > data.Modules[j].Mismatches
+		}
+		return data.Modules[i].Name < data.Modules[j].Name
+	})
+	executeTemplate(w, modulesTemplate, data)
+}
+
 var alphaNumRegExp = regexp.MustCompile(`^[a-zA-Z0-9]*$`)
 
 func isAlphanumeric(s string) bool {
@@ -828,6 +1069,8 @@ type UISummaryData struct {
 	Stats        []UIStat
 	Crashes      []*UICrashType
 	Log          string
+	// Changes in the machine check result since the previous run.
+	MachineCheckDiff string
 }
 
 type UIVMData struct {
@@ -880,6 +1123,13 @@ type UICallType struct {
 	ID     *int
 	Inputs int
 	Cover  int
+	Execs  uint64
+	// Execution times are in microseconds.
+	AvgTime    int64
+	KernelTime int64
+	MaxTime    int64
+	Slow       uint64
+	Blocked    uint64
 }
 
 type UICorpus struct {
@@ -894,6 +1144,23 @@ type UIInput struct {
 	Cover int
 }
 
+type UICoverWho struct {
+	File     string             `json:"file,omitempty"`
+	Line     int                `json:"line,omitempty"`
+	Function string             `json:"function,omitempty"`
+	PCs      int                `json:"pcs"`   // number of coverage points of the line/function
+	Total    int                `json:"total"` // number of covering programs (only the first ones are listed)
+	Inputs   []*UICoverWhoInput `json:"inputs"`
+}
+
+type UICoverWhoInput struct {
+	Sig     string `json:"sig"`
+	Short   string `json:"-"`
+	Calls   int    `json:"calls"`
+	PCs     int    `json:"pcs"` // number of the coverage points covered by the program
+	Program string `json:"program"`
+}
+
 var summaryTemplate = pages.Create(`
 <!doctype html>
 <html>
@@ -949,6 +1216,15 @@ var summaryTemplate = pages.Create(`
 	{{end}}
 </table>
 
+{{if .MachineCheckDiff}}
+<b>Machine check changes since the previous run:</b>
+<br>
+<textarea readonly rows="20" wrap=off>
+{{.MachineCheckDiff}}
+</textarea>
+<br>
+{{end}}
+
 <b>Log:</b>
 <br>
 <textarea id="log_textarea" readonly rows="20" wrap=off>
@@ -1007,6 +1283,17 @@ var syscallsTemplate = pages.Create(`
 		<th><a onclick="return sortTable(this, 'Syscall', textSort)" href="#">Syscall</a></th>
 		<th><a onclick="return sortTable(this, 'Inputs', numSort)" href="#">Inputs</a></th>
 		<th><a onclick="return sortTable(this, 'Coverage', numSort)" href="#">Coverage</a></th>
+		<th><a onclick="return sortTable(this, 'Execs', numSort)" href="#">Execs</a></th>
+		<th><a onclick="return sortTable(this, 'Avg time', numSort)" href="#"
+			title="Average wall-clock execution time (us)">Avg time</a></th>
+		<th><a onclick="return sortTable(this, 'Kernel time', numSort)" href="#"
+			title="Average time spent in the kernel (us)">Kernel time</a></th>
+		<th><a onclick="return sortTable(this, 'Max time', numSort)" href="#"
+			title="Maximum wall-clock execution time (us)">Max time</a></th>
+		<th><a onclick="return sortTable(this, 'Slow', numSort)" href="#"
+			title="Number of anomalously slow executions">Slow</a></th>
+		<th><a onclick="return sortTable(this, 'Blocked', numSort)" href="#"
+			title="Number of executions that did not finish before the call timeout">Blocked</a></th>
 		<th>Prio</th>
 	</tr>
 	{{range $c := $.Calls}}
@@ -1014,6 +1301,12 @@ var syscallsTemplate = pages.Create(`
 		<td>{{$c.Name}}{{if $c.ID }} [{{$c.ID}}]{{end}}</td>
 		<td><a href='/corpus?call={{$c.Name}}'>{{$c.Inputs}}</a></td>
 		<td><a href='/cover?call={{$c.Name}}'>{{$c.Cover}}</a></td>
+		<td>{{$c.Execs}}</td>
+		<td>{{$c.AvgTime}}</td>
+		<td>{{$c.KernelTime}}</td>
+		<td>{{$c.MaxTime}}</td>
+		<td>{{$c.Slow}}</td>
+		<td>{{$c.Blocked}}</td>
 		<td><a href='/prio?call={{$c.Name}}'>prio</a></td>
 	</tr>
 	{{end}}
@@ -1090,6 +1383,94 @@ var corpusTemplate = pages.Create(`
 </body></html>
 `)
 
+var coverWhoTemplate = pages.Create(`
+<!doctype html>
+<html>
+<head>
+	<title>syzkaller coverage</title>
+	{{HEAD}}
+</head>
+<body>
+
+<table class="list_table">
+	<caption>
+		Programs that cover {{if $.Function}}{{$.Function}}{{else}}{{$.File}}:{{$.Line}}{{end}}
+		({{$.PCs}} coverage points, {{$.Total}} programs{{if gt $.Total (len $.Inputs)}}, the first {{len $.Inputs}} are shown{{end}}):
+	</caption>
+	<tr>
+		<th>Covered points</th>
+		<th>Calls</th>
+		<th>Program</th>
+	</tr>
+	{{range $inp := $.Inputs}}
+	<tr>
+		<td><a href='/cover?input={{$inp.Sig}}'>{{$inp.PCs}}</a></td>
+		<td>{{$inp.Calls}}</td>
+		<td><a href="/input?sig={{$inp.Sig}}">{{$inp.Short}}</a></td>
+	</tr>
+	{{end}}
+</table>
+</body></html>
+`)
+
+type UIModulesData struct {
+	Modules []*UIModule
+}
+
+type UIModule struct {
+	Name         string
+	Addr         uint64
+	Size         uint64
+	BuildID      string
+	Path         string
+	Instances    int
+	Relocated    int
+	Mismatches   int
+	LastMismatch string
+}
+
+var modulesTemplate = pages.Create(`
+<!doctype html>
+<html>
+<head>
+	<title>syzkaller modules</title>
+	{{HEAD}}
+</head>
+<body>
+<a href='/modules'>json</a>
+<table class="list_table">
+	<caption>Kernel modules:</caption>
+	<tr>
+		<th><a onclick="return sortTable(this, 'Name', textSort)" href="#">Name</a></th>
+		<th>Address</th>
+		<th>Size</th>
+		<th>Build-id</th>
+		<th><a onclick="return sortTable(this, 'Instances', numSort)" href="#"
+			title="Number of VM instances that loaded the module">Instances</a></th>
+		<th><a onclick="return sortTable(this, 'Relocated', numSort)" href="#"
+			title="Number of VM instances that loaded the module at a different address">Relocated</a></th>
+		<th><a onclick="return sortTable(this, 'Mismatches', numSort)" href="#"
+			title="Number of VM instances where the module coverage was discarded">Mismatches</a></th>
+		<th>Last mismatch</th>
+		<th>Object file</th>
+	</tr>
+	{{range $m := $.Modules}}
+	<tr>
+		<td>{{if $m.Name}}{{$m.Name}}{{else}}[kernel]{{end}}</td>
+		<td>{{if $m.Path}}{{printf "0x%x" $m.Addr}}{{end}}</td>
+		<td>{{if $m.Path}}{{printf "0x%x" $m.Size}}{{end}}</td>
+		<td>{{$m.BuildID}}</td>
+		<td>{{$m.Instances}}</td>
+		<td>{{$m.Relocated}}</td>
+		<td>{{$m.Mismatches}}</td>
+		<td>{{$m.LastMismatch}}</td>
+		<td>{{$m.Path}}</td>
+	</tr>
+	{{end}}
+</table>
+</body></html>
+`)
+
 type UIPrioData struct {
 	Call  string
 	Prios []UIPrio
@@ -21,10 +21,13 @@ import (
 	"sync/atomic"
 	"time"
 
+	"cloud.google.com/go/civil"
 	"github.com/google/syzkaller/dashboard/dashapi"
 	"github.com/google/syzkaller/pkg/asset"
 	"github.com/google/syzkaller/pkg/corpus"
+	"github.com/google/syzkaller/pkg/cover"
 	"github.com/google/syzkaller/pkg/cover/backend"
+	"github.com/google/syzkaller/pkg/coveragedb"
 	"github.com/google/syzkaller/pkg/csource"
 	"github.com/google/syzkaller/pkg/db"
 	"github.com/google/syzkaller/pkg/flatrpc"
@@ -43,6 +46,7 @@ import (
 	"github.com/google/syzkaller/pkg/runtest"
 	"github.com/google/syzkaller/pkg/signal"
 	"github.com/google/syzkaller/pkg/stat"
+	"github.com/google/syzkaller/pkg/subsystem"
 	"github.com/google/syzkaller/pkg/vminfo"
 	"github.com/google/syzkaller/prog"
 	"github.com/google/syzkaller/sys/targets"
@@ -65,10 +69,22 @@ var (
 		" - corpus-triage: triage corpus and exit\n"+
 		"	This is useful mostly for benchmarking with testbed.\n"+
 		" - corpus-run: continuously run the corpus programs.\n"+
+		" - corpus-distill: distill the corpus and exit\n"+
+		"	Every corpus program is executed once to collect its signal and execution time,\n"+
+		"	then the smallest subset that preserves the signal according to -distill-objective\n"+
+		"	is saved to workdir/distilled/corpus.db along with workdir/distilled/report.txt.\n"+
+		"	The corpus is taken from -distill-corpus, the test seeds are not added.\n"+
 		" - run-tests: run unit tests\n"+
 		"	Run sys/os/test/* tests in various modes and print results.\n")
 
 	flagTests = flag.String("tests", "", "prefix to match test file names (for -mode run-tests)")
+
+	flagDistillObjective = flag.String("distill-objective", "programs", "what to minimize in the distilled corpus, "+
+		"one of: programs, calls, time (for -mode corpus-distill)")
+	flagDistillCorpus = flag.String("distill-corpus", "", "corpus database to distill, "+
+		"workdir/corpus.db by default (for -mode corpus-distill)")
+	flagDistillQuota = flag.Int("distill-quota", 0, "keep at most this number of programs per kernel subsystem, "+
+		"0 means no quota (for -mode corpus-distill)")
 )
 
 type Manager struct {
@@ -89,11 +105,13 @@ type Manager struct {
 	crashTypes      map[string]bool
 	enabledFeatures flatrpc.Feature
 	checkDone       atomic.Bool
+	checkDiff       *vminfo.CheckDiff
 	reportGenerator *manager.ReportGeneratorWrapper
 	fresh           bool
 	expertMode      bool
 	modules         []*vminfo.KernelModule
 	coverFilter     map[uint64]struct{} // includes only coverage PCs
+	vmHealth        map[int]*vmHealth   // nil if VM reuse is disabled
 
 	dash *dashapi.Dashboard
 	// This is specifically separated from dash, so that we can keep dash = nil when
@@ -135,6 +153,7 @@ const (
 	ModeCorpusTriage
 	ModeCorpusRun
 	ModeRunTests
+	ModeCorpusDistill
 )
 
 const (
@@ -186,6 +205,10 @@ func main() {
 		mode = ModeRunTests
 		cfg.DashboardClient = ""
 		cfg.HubClient = ""
+	case "corpus-distill":
+		mode = ModeCorpusDistill
+		cfg.DashboardClient = ""
+		cfg.HubClient = ""
 	default:
 		flag.PrintDefaults()
 		log.Fatalf("unknown mode: %v", *flagMode)
@@ -238,7 +261,7 @@ func RunManager(mode Mode, cfg *mgrconfig.Config) {
 	}
 
 	mgr.initStats()
-	if mode == ModeFuzzing || mode == ModeCorpusTriage || mode == ModeCorpusRun {
+	if mode == ModeFuzzing || mode == ModeCorpusTriage || mode == ModeCorpusRun || mode == ModeCorpusDistill {
 		go mgr.preloadCorpus()
 	} else {
 		close(mgr.corpusPreload)
@@ -295,6 +318,7 @@ func RunManager(mode Mode, cfg *mgrconfig.Config) {
 		return
 	}
 	mgr.pool = vm.NewDispatcher(mgr.vmPool, mgr.fuzzerInstance)
+	mgr.setupVMReuse()
 	mgr.reproLoop = manager.NewReproLoop(mgr, mgr.vmPool.Count()-mgr.cfg.FuzzingVMs, mgr.cfg.DashboardOnlyRepro)
 	ctx := vm.ShutdownCtx()
 	go mgr.processFuzzingResults(ctx)
@@ -473,7 +497,18 @@ func (mgr *Manager) processRepro(res *manager.ReproResult) {
 }
 
 func (mgr *Manager) preloadCorpus() {
-	info := manager.LoadSeeds(mgr.cfg, false)
+	var info manager.Seeds
+	if mgr.mode == ModeCorpusDistill {
+		// Distillation writes a separate database and must not modify the original one.
+		// The test seeds are not part of the distilled corpus.
+		file := *flagDistillCorpus
+		if file == "" {
+			file = filepath.Join(mgr.cfg.Workdir, "corpus.db")
+		}
+		info = manager.LoadCorpus(mgr.cfg, file, true, false)
+	} else {
+		info = manager.LoadSeeds(mgr.cfg, false)
+	}
 	mgr.fresh = info.Fresh
 	mgr.corpusDB = info.CorpusDB
 	mgr.corpusPreload <- info.Candidates
@@ -522,6 +557,7 @@ func (mgr *Manager) fuzzerInstance(ctx context.Context, inst *vm.Instance, updIn
 		extraExecs = []report.ExecutorInfo{*rep.Executor}
 	}
 	lastExec, machineInfo := serv.ShutdownInstance(inst.Index(), rep != nil, extraExecs...)
+	mgr.vmSessionFinished(inst, err == nil && rep == nil)
 	if rep != nil {
 		rpcserver.PrependExecuting(rep, lastExec)
 		if len(vmInfo) != 0 {
@@ -565,6 +601,7 @@ func (mgr *Manager) runInstanceInner(ctx context.Context, inst *vm.Instance, inj
 		return nil, nil, fmt.Errorf("failed to parse manager's address")
 	}
 	cmd := fmt.Sprintf("%v runner %v %v %v", executorBin, inst.Index(), host, port)
+	mgr.vmRunnerStarted(inst, executorBin)
 	_, rep, err := inst.Run(mgr.cfg.Timeouts.VMRunningTime, mgr.reporter, cmd,
 		vm.ExitTimeout, vm.StopContext(ctx), vm.InjectExecuting(injectExec),
 		finishCb,
@@ -1121,6 +1158,7 @@ func (mgr *Manager) MachineChecked(features flatrpc.Feature, enabledSyscalls map
 	}
 	mgr.enabledFeatures = features
 	mgr.targetEnabledSyscalls = enabledSyscalls
+	mgr.checkDiff = mgr.serv.MachineCheckDiff()
 	mgr.firstConnect.Store(time.Now().Unix())
 	statSyscalls := stat.New("syscalls", "Number of enabled syscalls",
 		stat.Simple, stat.NoGraph, stat.Link("/syscalls"))
@@ -1141,6 +1179,8 @@ func (mgr *Manager) MachineChecked(features flatrpc.Feature, enabledSyscalls map
 			EnabledCalls:   enabledSyscalls,
 			NoMutateCalls:  mgr.cfg.NoMutateCalls,
 			FetchRawCover:  mgr.cfg.RawCover,
+			SlownessSignal: mgr.cfg.Experimental.SlownessSignal,
+			Timeouts:       mgr.cfg.Timeouts,
 			Logf: func(level int, msg string, args ...interface{}) {
 				if level != 0 {
 					return
@@ -1158,6 +1198,9 @@ func (mgr *Manager) MachineChecked(features flatrpc.Feature, enabledSyscalls map
 
 		go mgr.corpusMinimization()
 		go mgr.fuzzerLoop(fuzzerObj)
+		if mgr.cfg.CoverLocalDB != "" {
+			go mgr.coverLocalDBLoop()
+		}
 		if mgr.dash != nil {
 			go mgr.dashboardReporter()
 			if mgr.cfg.Reproduce {
@@ -1182,6 +1225,8 @@ func (mgr *Manager) MachineChecked(features flatrpc.Feature, enabledSyscalls map
 			rnd:        rand.New(rand.NewSource(time.Now().UnixNano())),
 		}
 		return queue.DefaultOpts(ctx, opts)
+	} else if mgr.mode == ModeCorpusDistill {
+		return queue.DefaultOpts(newCorpusDistiller(mgr, corpus), opts)
 	} else if mgr.mode == ModeRunTests {
 		ctx := &runtest.Context{
 			Dir:      filepath.Join(mgr.cfg.Syzkaller, "sys", mgr.cfg.Target.OS, "test"),
@@ -1381,9 +1426,43 @@ func (mgr *Manager) trackUsedFiles() {
 	}
 }
 
+const coverLocalDBPeriod = 6 * time.Hour
+
+// coverLocalDBLoop periodically saves the corpus coverage to the local coverage database.
+// The coverage of the current date is replaced on every save, so the last save of the day wins.
+func (mgr *Manager) coverLocalDBLoop() {
+	storage := coveragedb.NewLocalStorage(mgr.cfg.CoverLocalDB)
+	for range time.NewTicker(coverLocalDBPeriod).C {
+		rg, err := mgr.reportGenerator.Get()
+		if err != nil {
+			log.Logf(0, "failed to get report generator: %v", err)
+			continue
+		}
+		mgr.mu.Lock()
+		var progs []cover.Prog
+		for _, inp := range mgr.corpus.Items() {
+			progs = append(progs, cover.Prog{
+				Sig:  inp.Sig,
+				Data: string(inp.Prog.Serialize()),
+				PCs:  manager.CoverToPCs(mgr.cfg, inp.Cover),
+			})
+		}
+		mgr.mu.Unlock()
+		date := civil.DateOf(time.Now())
+		err = rg.SaveDayCoverage(context.Background(), storage, mgr.cfg.Name, date,
+			cover.HandlerParams{Progs: progs}, subsystem.GetList(mgr.cfg.TargetOS))
+		if err != nil {
+			log.Logf(0, "failed to save coverage to %v: %v", mgr.cfg.CoverLocalDB, err)
+			continue
+		}
+		log.Logf(1, "saved coverage for %v to %v", date, mgr.cfg.CoverLocalDB)
+	}
+}
+
 func (mgr *Manager) dashboardReporter() {
 	webAddr := publicWebAddr(mgr.cfg.HTTP)
 	triageInfoSent := false
+	checkDiffSent := false
 	var lastFuzzingTime time.Duration
 	var lastCrashes, lastSuppressedCrashes, lastExecs uint64
 	for range time.NewTicker(time.Minute).C {
@@ -1406,12 +1485,25 @@ func (mgr *Manager) dashboardReporter() {
 			req.TriagedCoverage = uint64(mgr.corpus.StatSignal.Val())
 			req.TriagedPCs = uint64(mgr.corpus.StatCover.Val())
 		}
+		if mgr.checkDone.Load() && !checkDiffSent {
+			// Send the diff even if it's empty to reset the diff of the previous check.
+			req.MachineChecked = true
+			if mgr.checkDiff != nil {
+				req.MachineCheckDiff = mgr.checkDiff.String()
+			}
+		}
 		mgr.mu.Unlock()
 
 		if err := mgr.dash.UploadManagerStats(req); err != nil {
 			log.Logf(0, "failed to upload dashboard stats: %v", err)
 			continue
 		}
+		if req.MachineChecked {
+			checkDiffSent = true
+			if err := mgr.serv.MachineCheckDiffReported(); err != nil {
+				log.Logf(0, "failed to reset machine check diff: %v", err)
+			}
+		}
 		mgr.mu.Lock()
 		lastFuzzingTime += req.FuzzingTime
 		lastCrashes += req.Crashes
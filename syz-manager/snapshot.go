@@ -46,6 +46,7 @@ func (mgr *Manager) snapshotLoop(ctx context.Context, inst *vm.Instance) error {
 	}
 
 	builder := flatbuffers.NewBuilder(0)
+	cache := newSnapshotCache(mgr.cfg.Experimental.SnapshotPrefixes)
 	var envFlags flatrpc.ExecEnv
 	for first := true; ctx.Err() == nil; first = false {
 		queue.StatExecs.Add(1)
@@ -62,7 +63,7 @@ func (mgr *Manager) snapshotLoop(ctx context.Context, inst *vm.Instance) error {
 				envFlags, req.ExecOpts.EnvFlags))
 		}
 
-		res, output, err := mgr.snapshotRun(inst, builder, req)
+		res, output, err := mgr.snapshotRun(inst, builder, cache, req)
 		if err != nil {
 			req.Done(&queue.Result{Status: queue.Crashed})
 			return err
@@ -99,17 +100,21 @@ func (mgr *Manager) snapshotSetup(inst *vm.Instance, builder *flatbuffers.Builde
 	return inst.SetupSnapshot(builder.FinishedBytes())
 }
 
-func (mgr *Manager) snapshotRun(inst *vm.Instance, builder *flatbuffers.Builder, req *queue.Request) (
-	*queue.Result, []byte, error) {
+func (mgr *Manager) snapshotRun(inst *vm.Instance, builder *flatbuffers.Builder, cache *snapshotCache,
+	req *queue.Request) (*queue.Result, []byte, error) {
 	progData, err := req.Prog.SerializeForExec()
 	if err != nil {
 		queue.StatExecBufferTooSmall.Add(1)
 		return &queue.Result{Status: queue.ExecFailure}, nil, nil
 	}
+	// Failure to split the program into prefixes only disables the snapshot cache for the request.
+	prefixes, _ := mgr.target.ExecPrefixes(progData)
+	plan := cache.plan(req, prefixes)
 	msg := flatrpc.SnapshotRequestT{
-		ExecFlags: req.ExecOpts.ExecFlags,
-		NumCalls:  int32(len(req.Prog.Calls)),
-		ProgData:  progData,
+		ExecFlags:      req.ExecOpts.ExecFlags,
+		NumCalls:       int32(len(req.Prog.Calls)),
+		ProgData:       progData,
+		SnapshotPrefix: int32(plan.saveCalls),
 	}
 	for _, call := range req.ReturnAllSignal {
 		if call < 0 {
@@ -121,8 +126,12 @@ func (mgr *Manager) snapshotRun(inst *vm.Instance, builder *flatbuffers.Builder,
 	builder.Reset()
 	builder.Finish(msg.Pack(builder))
 
+	restore := ""
+	if plan.restore != nil {
+		restore = plan.restore.name
+	}
 	start := time.Now()
-	resData, output, err := inst.RunSnapshot(builder.FinishedBytes())
+	resData, output, saved, err := inst.RunSnapshotPrefix(builder.FinishedBytes(), restore, plan.saveName)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -146,6 +155,16 @@ func (mgr *Manager) snapshotRun(inst *vm.Instance, builder *flatbuffers.Builder,
 			res.Info.ExtraRaw = nil
 		}
 	}
+	for _, name := range cache.finish(plan, res, saved) {
+		if err := inst.DeleteSnapshot(name); err != nil {
+			return nil, nil, err
+		}
+	}
+	if calls, elapsed := plan.savings(); calls != 0 {
+		mgr.statSnapshotHits.Add(1)
+		mgr.statSnapshotSkipped.Add(calls)
+		mgr.statSnapshotSaved.Add(int(elapsed))
+	}
 
 	ret := &queue.Result{
 		Status: queue.Success,
@@ -0,0 +1,216 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"container/list"
+	"fmt"
+	"time"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/fuzzer/queue"
+	"github.com/google/syzkaller/pkg/hash"
+)
+
+// snapshotCache decides when to take additional snapshots after program prefixes
+// and when to execute programs starting from such snapshots in a single VM.
+// Prefixes are identified by the hash of their exec encoding (which determines
+// executor state after the prefix execution) and the exec flags.
+type snapshotCache struct {
+	maxEntries int
+	entries    map[hash.Sig]*snapshotEntry
+	// Number of times we've seen a prefix that does not have a snapshot yet.
+	// The elements of seenLRU are *seenPrefix, the most recently seen prefixes go first.
+	seen    map[hash.Sig]*list.Element
+	seenLRU *list.List
+	counter int
+	// Number of requests executed since the cache creation, used to calculate hit rates.
+	requests uint64
+}
+
+type snapshotEntry struct {
+	name  string
+	calls int
+	// Value of requests at the time of the entry creation.
+	created uint64
+	hits    uint64
+	// Results of the prefix calls, used for programs executed from the snapshot.
+	info []*flatrpc.CallInfo
+}
+
+type seenPrefix struct {
+	key   hash.Sig
+	count int
+}
+
+// snapshotPlan says how to execute a single request.
+type snapshotPlan struct {
+	// Restore from this snapshot (nil means the base snapshot).
+	restore *snapshotEntry
+	// Take a new snapshot after this number of calls (0 means don't take).
+	saveCalls int
+	saveKey   hash.Sig
+	saveName  string
+}
+
+const (
+	// We take a snapshot for a prefix only after we've seen it this number of times.
+	snapshotMinSeen = 3
+	// Entries are not evicted before they had a chance to be used for this number of requests.
+	snapshotMinAge = 1000
+	// Max number of remembered seen counts (to bound memory consumption),
+	// the least recently seen prefixes are forgotten first.
+	snapshotMaxSeen = 100000
+)
+
+func newSnapshotCache(maxEntries int) *snapshotCache {
+	return &snapshotCache{
+		maxEntries: maxEntries,
+		entries:    make(map[hash.Sig]*snapshotEntry),
+		seen:       make(map[hash.Sig]*list.Element),
+		seenLRU:    list.New(),
+	}
+}
+
+// plan decides how to execute the request, prefixes are the exec encoding prefixes
+// of the program (see prog.Target.ExecPrefixes).
+func (cache *snapshotCache) plan(req *queue.Request, prefixes [][]byte) *snapshotPlan {
+	plan := new(snapshotPlan)
+	cache.requests++
+	// Results of the prefix calls are taken from the cache, so we can't use it when
+	// the caller wants precise results for all calls.
+	if cache.maxEntries == 0 || len(req.ReturnAllSignal) != 0 || req.ReturnOutput ||
+		len(prefixes) != len(req.Prog.Calls) {
+		return plan
+	}
+	// Each prefix key is the hash of the previous key and the instructions of the next call,
+	// so that the keys of all prefixes are computed in linear time.
+	keys := make([]hash.Sig, len(prefixes))
+	prev, key := []byte(nil), hash.Hash(uint64(req.ExecOpts.ExecFlags))
+	for i, prefix := range prefixes {
+		key = hash.Hash(key, prefix[len(prev):])
+		keys[i], prev = key, prefix
+	}
+	// Use the longest prefix that has a snapshot, and consider the longest prefix
+	// that was seen enough times for a new snapshot (leaving at least one call to execute).
+	for calls := len(req.Prog.Calls) - 1; calls > 0; calls-- {
+		key := keys[calls-1]
+		if entry := cache.entries[key]; entry != nil {
+			entry.hits++
+			plan.restore = entry
+			break
+		}
+		if plan.saveCalls == 0 && cache.markSeen(key) >= snapshotMinSeen && cache.canAdd() {
+			plan.saveCalls = calls
+			plan.saveKey = key
+		}
+	}
+	if plan.saveCalls != 0 && plan.restore != nil && plan.restore.calls >= plan.saveCalls {
+		// Restored snapshot already covers the prefix.
+		plan.saveCalls = 0
+	}
+	if plan.saveCalls != 0 {
+		cache.counter++
+		plan.saveName = fmt.Sprintf("syz-prefix-%v", cache.counter)
+	}
+	return plan
+}
+
+// finish records results of execution of the request according to the plan.
+// It fills in results for the prefix calls that were not executed (marked as cached),
+// and returns names of the snapshots that need to be deleted from the VM.
+func (cache *snapshotCache) finish(plan *snapshotPlan, res *flatrpc.ExecResult, saved bool) []string {
+	if plan.restore != nil && res.Info != nil && len(res.Info.Calls) >= plan.restore.calls {
+		cached := &flatrpc.ProgInfo{Calls: plan.restore.info}
+		copy(res.Info.Calls, cached.Clone().Calls)
+		for _, info := range res.Info.Calls[:plan.restore.calls] {
+			info.Flags |= flatrpc.CallFlagCached
+		}
+	}
+	if !saved {
+		return nil
+	}
+	if res.Info == nil || res.Error != "" || len(res.Info.Calls) < plan.saveCalls {
+		// The snapshot is taken, but we don't have the prefix results, so we can't use it.
+		return []string{plan.saveName}
+	}
+	var evicted []string
+	if len(cache.entries) >= cache.maxEntries {
+		key := cache.evictionCandidate()
+		victim := cache.entries[key]
+		if victim == nil {
+			// All entries became too young to evict since the plan was made.
+			return []string{plan.saveName}
+		}
+		evicted = append(evicted, victim.name)
+		delete(cache.entries, key)
+	}
+	prefixInfo := &flatrpc.ProgInfo{Calls: res.Info.Calls[:plan.saveCalls]}
+	entry := &snapshotEntry{
+		name:    plan.saveName,
+		calls:   plan.saveCalls,
+		created: cache.requests,
+		info:    prefixInfo.Clone().Calls,
+	}
+	cache.entries[plan.saveKey] = entry
+	if elem := cache.seen[plan.saveKey]; elem != nil {
+		cache.seenLRU.Remove(elem)
+		delete(cache.seen, plan.saveKey)
+	}
+	return evicted
+}
+
+// markSeen increments and returns the number of times the prefix was seen.
+func (cache *snapshotCache) markSeen(key hash.Sig) int {
+	if elem := cache.seen[key]; elem != nil {
+		cache.seenLRU.MoveToFront(elem)
+		prefix := elem.Value.(*seenPrefix)
+		prefix.count++
+		return prefix.count
+	}
+	if cache.seenLRU.Len() >= snapshotMaxSeen {
+		oldest := cache.seenLRU.Back()
+		cache.seenLRU.Remove(oldest)
+		delete(cache.seen, oldest.Value.(*seenPrefix).key)
+	}
+	cache.seen[key] = cache.seenLRU.PushFront(&seenPrefix{key: key, count: 1})
+	return 1
+}
+
+func (cache *snapshotCache) canAdd() bool {
+	if len(cache.entries) < cache.maxEntries {
+		return true
+	}
+	_, ok := cache.entries[cache.evictionCandidate()]
+	return ok
+}
+
+// evictionCandidate returns the entry with the lowest hit rate
+// among entries that are old enough to judge (or an empty key if there are none).
+func (cache *snapshotCache) evictionCandidate() hash.Sig {
+	var res hash.Sig
+	bestRate := 2.0
+	for key, entry := range cache.entries {
+		age := cache.requests - entry.created
+		if age < snapshotMinAge {
+			continue
+		}
+		if rate := float64(entry.hits) / float64(age); rate < bestRate {
+			res, bestRate = key, rate
+		}
+	}
+	return res
+}
+
+// savings returns the number of calls and the execution time saved by restoring from the snapshot.
+func (plan *snapshotPlan) savings() (int, time.Duration) {
+	if plan.restore == nil {
+		return 0, 0
+	}
+	var elapsed time.Duration
+	for _, info := range plan.restore.info {
+		elapsed += time.Duration(info.Elapsed)
+	}
+	return plan.restore.calls, elapsed
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/fuzzer/queue"
+	"github.com/google/syzkaller/pkg/hash"
+	"github.com/google/syzkaller/prog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotCache(t *testing.T) {
+	// The cache does not look into the exec encoding, so a program is represented by a string
+	// with a letter per call, and the prefixes are the string prefixes.
+	request := func(text string) (*queue.Request, [][]byte) {
+		p := new(prog.Prog)
+		var prefixes [][]byte
+		for i := range text {
+			p.Calls = append(p.Calls, &prog.Call{Meta: &prog.Syscall{Name: text[i : i+1]}})
+			prefixes = append(prefixes, []byte(text[:i+1]))
+		}
+		return &queue.Request{Prog: p}, prefixes
+	}
+	execute := func(cache *snapshotCache, text string, saved bool) (*snapshotPlan, *flatrpc.ExecResult, []string) {
+		req, prefixes := request(text)
+		plan := cache.plan(req, prefixes)
+		res := &flatrpc.ExecResult{Info: flatrpc.EmptyProgInfo(len(req.Prog.Calls))}
+		for i, call := range res.Info.Calls {
+			call.Error = int32(i)
+			call.Elapsed = 10
+		}
+		deleted := cache.finish(plan, res, saved && plan.saveCalls != 0)
+		return plan, res, deleted
+	}
+	const (
+		prefix = "ab"
+		prog0  = prefix + "b"
+		prog1  = prefix + "c"
+	)
+	cache := newSnapshotCache(1)
+	for i := 1; i < snapshotMinSeen; i++ {
+		plan, _, _ := execute(cache, prog0, true)
+		assert.Nil(t, plan.restore)
+		assert.Equal(t, 0, plan.saveCalls)
+	}
+	plan, _, deleted := execute(cache, prog0, true)
+	assert.Nil(t, plan.restore)
+	assert.Equal(t, 2, plan.saveCalls)
+	assert.Empty(t, deleted)
+	assert.Len(t, cache.entries, 1)
+
+	// Programs with the same prefix are executed from the prefix snapshot,
+	// and results of the prefix calls come from the cache.
+	plan, res, _ := execute(cache, prog1, true)
+	assert.Equal(t, plan.saveName, "")
+	if assert.NotNil(t, plan.restore) {
+		assert.Equal(t, 2, plan.restore.calls)
+	}
+	calls, elapsed := plan.savings()
+	assert.Equal(t, 2, calls)
+	assert.EqualValues(t, 20, elapsed)
+	if assert.Len(t, res.Info.Calls, 3) {
+		// Cached results must not be triaged by the fuzzer.
+		for i, call := range res.Info.Calls {
+			assert.Equal(t, i < 2, call.Flags&flatrpc.CallFlagCached != 0, "call %v", i)
+		}
+	}
+
+	// Requests that need precise results of all calls don't use the cache.
+	req, prefixes := request(prog1)
+	req.ReturnAllSignal = []int{0}
+	assert.Nil(t, cache.plan(req, prefixes).restore)
+
+	// Prefixes of the same program executed with different flags are different.
+	req, prefixes = request(prog1)
+	req.ExecOpts.ExecFlags = flatrpc.ExecFlagCollectSignal
+	assert.Nil(t, cache.plan(req, prefixes).restore)
+
+	// A new prefix replaces the old one only after it had a chance to be used.
+	const prog2 = "acb"
+	for i := 0; i < snapshotMinSeen; i++ {
+		plan, _, _ := execute(cache, prog2, true)
+		assert.Equal(t, 0, plan.saveCalls)
+	}
+	cache.requests += snapshotMinAge
+	plan, _, deleted = execute(cache, prog2, true)
+	assert.Equal(t, 2, plan.saveCalls)
+	assert.Equal(t, []string{"syz-prefix-1"}, deleted)
+	assert.Len(t, cache.entries, 1)
+	plan, _, _ = execute(cache, prog1, true)
+	assert.Nil(t, plan.restore)
+
+	// Snapshots with failed executions are not used.
+	cache = newSnapshotCache(1)
+	req, prefixes = request(prog0)
+	for i := 0; i < snapshotMinSeen; i++ {
+		plan = cache.plan(req, prefixes)
+	}
+	deleted = cache.finish(plan, &flatrpc.ExecResult{Error: "failed"}, true)
+	assert.Equal(t, []string{plan.saveName}, deleted)
+	assert.Empty(t, cache.entries)
+}
+
+func TestSnapshotCacheSeen(t *testing.T) {
+	cache := newSnapshotCache(1)
+	key := func(i int) hash.Sig {
+		return hash.Hash(uint64(i))
+	}
+	for i := 0; i < snapshotMaxSeen; i++ {
+		assert.Equal(t, 1, cache.markSeen(key(i)))
+	}
+	assert.Equal(t, 2, cache.markSeen(key(0)))
+	// The least recently seen prefix is forgotten first.
+	assert.Equal(t, 1, cache.markSeen(key(snapshotMaxSeen)))
+	assert.Len(t, cache.seen, snapshotMaxSeen)
+	assert.Equal(t, snapshotMaxSeen, cache.seenLRU.Len())
+	assert.Equal(t, 3, cache.markSeen(key(0)))
+	assert.Equal(t, 2, cache.markSeen(key(2)))
+	assert.Equal(t, 1, cache.markSeen(key(1)))
+}
@@ -20,6 +20,10 @@ type Stats struct {
 	statFuzzingTime   *stat.Val
 	statAvgBootTime   *stat.Val
 	statCoverFiltered *stat.Val
+
+	statSnapshotHits    *stat.Val
+	statSnapshotSkipped *stat.Val
+	statSnapshotSaved   *stat.Val
 }
 
 func (mgr *Manager) initStats() {
@@ -49,6 +53,23 @@ func (mgr *Manager) initStats() {
 		func(v int, _ time.Duration) string {
 			return fmt.Sprintf("%v sec", v)
 		})
+	if mgr.cfg.Experimental.ReuseVMs {
+		stat.New("instance reuses", "Number of times a healthy VM was reused instead of restarting",
+			stat.Rate{}, stat.NoGraph, func() int {
+				return int(mgr.pool.Reused.Load())
+			})
+	}
+	if mgr.cfg.Experimental.SnapshotPrefixes != 0 {
+		mgr.statSnapshotHits = stat.New("snapshot prefix hits",
+			"Number of programs executed starting from a prefix snapshot",
+			stat.Rate{}, stat.Graph("snapshot prefixes"))
+		mgr.statSnapshotSkipped = stat.New("snapshot skipped calls",
+			"Number of calls not executed due to prefix snapshots",
+			stat.Rate{}, stat.Graph("snapshot prefixes"))
+		mgr.statSnapshotSaved = stat.New("snapshot saved time",
+			"Total execution time of calls not executed due to prefix snapshots (seconds)",
+			stat.NoGraph, func(v int, period time.Duration) string { return fmt.Sprintf("%v sec", v/1e9) })
+	}
 
 	stat.New("heap", "Process heap size (bytes)", stat.Graph("memory"),
 		func() int {
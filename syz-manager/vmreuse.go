@@ -0,0 +1,146 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/vm"
+)
+
+// vmHealth is the state used to decide whether a VM can be reused for the next fuzzing session.
+type vmHealth struct {
+	inst *vm.Instance
+	// The executor binary of the last runner started on the VM.
+	executorBin string
+	// Set when the last fuzzing session ended only due to the VM running time expiration.
+	cleanExit bool
+	// MemAvailable (in kB) at the first health check of this VM.
+	baseMemAvailable uint64
+}
+
+// Kernel taint flags that signify a kernel error (see Documentation/admin-guide/tainted-kernels.rst):
+// MACHINE_CHECK, BAD_PAGE, DIE, WARN, SOFTLOCKUP.
+const vmErrorTaints = 1<<4 | 1<<5 | 1<<7 | 1<<9 | 1<<14
+
+var vmHealthRe = regexp.MustCompile(`syz-health: tainted=([0-9]+) memavail=([0-9]+) executor=([^ ]+) ([^ \r\n]+)`)
+
+func (mgr *Manager) setupVMReuse() {
+	if !mgr.cfg.Experimental.ReuseVMs {
+		return
+	}
+	mgr.vmHealth = make(map[int]*vmHealth)
+	mgr.pool.SetReuse(mgr.vmReusable, time.Duration(mgr.cfg.Experimental.VMMaxLifetime)*time.Minute)
+}
+
+// vmRunnerStarted records the runner binary started on the VM, it's killed before the VM is reused.
+func (mgr *Manager) vmRunnerStarted(inst *vm.Instance, executorBin string) {
+	if mgr.vmHealth == nil {
+		return
+	}
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	mgr.vmHealthLocked(inst).executorBin = executorBin
+}
+
+// vmSessionFinished records the outcome of a fuzzing session on the VM.
+func (mgr *Manager) vmSessionFinished(inst *vm.Instance, clean bool) {
+	if mgr.vmHealth == nil {
+		return
+	}
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	mgr.vmHealthLocked(inst).cleanExit = clean
+}
+
+func (mgr *Manager) vmHealthLocked(inst *vm.Instance) *vmHealth {
+	health := mgr.vmHealth[inst.Index()]
+	if health == nil || health.inst != inst {
+		// The VM was re-created since the last session.
+		health = &vmHealth{inst: inst}
+		mgr.vmHealth[inst.Index()] = health
+	}
+	return health
+}
+
+func (mgr *Manager) vmReusable(inst *vm.Instance) bool {
+	mgr.mu.Lock()
+	health := mgr.vmHealth[inst.Index()]
+	// Only fuzzing sessions mark VMs as clean, VMs used for other purposes (e.g. repro) are recreated.
+	clean := health != nil && health.inst == inst && health.cleanExit && health.executorBin != ""
+	var executorBin string
+	if health != nil {
+		health.cleanExit = false
+		executorBin = health.executorBin
+	}
+	mgr.mu.Unlock()
+	if !clean {
+		return false
+	}
+	memAvailable, err := mgr.checkVMHealth(inst, executorBin)
+	if err != nil {
+		log.Logf(0, "VM %v: not reusing: %v", inst.Index(), err)
+		return false
+	}
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	if health.baseMemAvailable == 0 {
+		health.baseMemAvailable = memAvailable
+	}
+	maxDrop := health.baseMemAvailable * uint64(mgr.cfg.Experimental.VMMaxMemoryDrop) / 100
+	if maxDrop != 0 && health.baseMemAvailable-min(memAvailable, health.baseMemAvailable) > maxDrop {
+		log.Logf(0, "VM %v: not reusing: available memory dropped from %v to %v kB",
+			inst.Index(), health.baseMemAvailable, memAvailable)
+		return false
+	}
+	return true
+}
+
+// checkVMHealth kills the old runner and verifies that it's dead, that the VM is still alive
+// (it runs the command and the kernel does not print errors), that the executor binary
+// still runs and reports the expected revisions, and that the kernel is in a good state.
+// Returns the amount of available memory.
+func (mgr *Manager) checkVMHealth(inst *vm.Instance, executorBin string) (uint64, error) {
+	// The pattern is anchored to not match the shell that runs the command.
+	runner := fmt.Sprintf("^%v( |$)", regexp.QuoteMeta(executorBin))
+	cmd := fmt.Sprintf("pkill -9 -f '%[1]v'; sleep 1;"+
+		" if ! command -v pgrep >/dev/null; then echo syz-health: no pgrep;"+
+		" elif pgrep -f '%[1]v' >/dev/null; then echo syz-health: the old runner is still running;"+
+		" elif ! version=$(%[2]v version); then echo syz-health: executor failed;"+
+		" else echo syz-health: tainted=$(cat /proc/sys/kernel/tainted)"+
+		" memavail=$(grep MemAvailable /proc/meminfo | tr -dc 0-9) executor=$version; fi",
+		runner, executorBin)
+	output, rep, err := inst.Run(time.Minute*mgr.cfg.Timeouts.Scale, mgr.reporter, cmd)
+	if err != nil {
+		return 0, fmt.Errorf("failed to run health check: %w", err)
+	}
+	if rep != nil {
+		return 0, fmt.Errorf("kernel error: %v", rep.Title)
+	}
+	// The output is mixed with the console output, so take the last match.
+	matches := vmHealthRe.FindAllSubmatch(output, -1)
+	if len(matches) == 0 {
+		return 0, fmt.Errorf("unexpected health check output: %q",
+			bytes.TrimSpace(output[max(0, len(output)-256):]))
+	}
+	match := matches[len(matches)-1]
+	tainted, err := strconv.ParseUint(string(match[1]), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if tainted&vmErrorTaints != 0 {
+		return 0, fmt.Errorf("kernel is tainted: %v", tainted)
+	}
+	if gitRevision, syzRevision := string(match[3]), string(match[4]); gitRevision != prog.GitRevision ||
+		syzRevision != mgr.target.Revision {
+		return 0, fmt.Errorf("unexpected executor revisions: %v %v", gitRevision, syzRevision)
+	}
+	return strconv.ParseUint(string(match[2]), 10, 64)
+}
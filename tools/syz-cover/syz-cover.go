@@ -18,10 +18,18 @@
 // or use all pcs in rg.Symbols
 //
 //	syz-cover -config config_file
+//
+// To compare coverage with older coverage (possibly collected on a different kernel build):
+//
+//	syz-cover -config config_file -diff-base old.rawcover.file [-diff-base-config old_config_file] rawcover.file*
+//
+// To keep coverage history and build heatmaps without cloud services, save daily coverage to a local database:
+//
+//	syz-cover -config config_file -exports localdb -local-db db_dir [-namespace ns] [-to date] rawcover.file*
+//	syz-cover -heatmap ns -local-db db_dir [-group-by subsystem] [-from date] [-to date]
 package main
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -30,7 +38,6 @@ import (
 	"io"
 	"os"
 	"os/exec"
-	"strconv"
 	"strings"
 	"time"
 
@@ -41,6 +48,7 @@ import (
 	"github.com/google/syzkaller/pkg/log"
 	"github.com/google/syzkaller/pkg/mgrconfig"
 	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/subsystem"
 	"github.com/google/syzkaller/pkg/tool"
 	"github.com/google/syzkaller/pkg/vminfo"
 )
@@ -65,9 +73,16 @@ var (
 	flagSourceCommit = flag.String("source-commit", "", "[optional] filter input commit")
 	flagExports      = flag.String("exports", "cover",
 		"[optional] comma separated list of exports for which we want to generate coverage, "+
-			"possible values are: cover, subsystem, module, funccover, json, jsonl, rawcover, rawcoverfiles, all")
+			"possible values are: cover, subsystem, module, funccover, json, jsonl, lcov, cobertura, "+
+			"rawcover, rawcoverfiles, localdb, all")
 	flagForce = flag.Bool("force", false, "[optional] create coverage report when "+
 		"there are missing coverage callbacks")
+	flagDiffBase = flag.String("diff-base", "", "[optional] comma separated list of raw coverage files "+
+		"to compare the coverage with (generates syz-cover-diff.html and syz-cover-diff.json)")
+	flagDiffBaseConfig = flag.String("diff-base-config", "", "[optional] configuration file of the kernel build "+
+		"used for -diff-base coverage (by default -config is used)")
+	flagLocalDB = flag.String("local-db", "", "[optional] local coverage database directory, "+
+		"-heatmap uses it instead of spanner, localdb export saves coverage for -namespace and -to date into it")
 )
 
 func parseDates() (civil.Date, civil.Date) {
@@ -96,14 +111,18 @@ func periodsFromDays(from, to civil.Date) []coveragedb.TimePeriod {
 func toolBuildNsHeatmap() {
 	buf := new(bytes.Buffer)
 	periods := periodsFromDays(parseDates())
+	storage := coveragedb.NewSpannerStorage(*flagProjectID)
+	if *flagLocalDB != "" {
+		storage = coveragedb.NewLocalStorage(*flagLocalDB)
+	}
 	var err error
 	switch *flagNsHeatmapGroupBy {
 	case "dir":
-		if err = cover.DoDirHeatMap(buf, *flagProjectID, *flagNsHeatmap, periods); err != nil {
+		if err = cover.DoDirHeatMap(buf, storage, *flagNsHeatmap, periods); err != nil {
 			tool.Fail(err)
 		}
 	case "subsystem":
-		if err = cover.DoSubsystemsHeatMap(buf, *flagProjectID, *flagNsHeatmap, periods); err != nil {
+		if err = cover.DoSubsystemsHeatMap(buf, storage, *flagNsHeatmap, periods); err != nil {
 			tool.Fail(err)
 		}
 	default:
@@ -177,8 +196,12 @@ func main() {
 		Force: *flagForce,
 	}
 
+	if *flagDiffBase != "" {
+		toolCoverDiff(rg, params)
+		return
+	}
 	if *flagExports == "all" {
-		*flagExports = "cover,subsystem,module,funccover,rawcover,rawcoverfiles"
+		*flagExports = "cover,subsystem,module,funccover,lcov,cobertura,rawcover,rawcoverfiles"
 	}
 	exports := strings.Split(*flagExports, ",")
 	for _, export := range exports {
@@ -200,6 +223,12 @@ func main() {
 			doReport(params, "json", rg.DoLineJSON)
 		case "jsonl":
 			doReport(params, "jsonl", rg.DoCoverJSONL)
+		case "lcov":
+			doReport(params, "syz-cover.lcov", rg.DoLCOV)
+		case "cobertura":
+			doReport(params, "syz-cover-cobertura.xml", rg.DoCobertura)
+		case "localdb":
+			toolSaveLocalDB(cfg, rg, params)
 		default:
 			tool.Failf("unknown export type: %q", export)
 		}
@@ -219,6 +248,67 @@ func doReport(params cover.HandlerParams, fname string,
 	exec.Command("xdg-open", fname).Start()
 }
 
+func toolCoverDiff(rg *cover.ReportGenerator, params cover.HandlerParams) {
+	baseRG := rg
+	if *flagDiffBaseConfig != "" {
+		baseCfg, err := mgrconfig.LoadFile(*flagDiffBaseConfig)
+		if err != nil {
+			tool.Fail(err)
+		}
+		baseRG, err = cover.MakeReportGenerator(baseCfg, baseCfg.KernelSubsystem, initModules(baseCfg), false)
+		if err != nil {
+			tool.Fail(err)
+		}
+	}
+	basePCs, err := readPCs(strings.Split(*flagDiffBase, ","))
+	if err != nil {
+		tool.Fail(err)
+	}
+	baseCov, err := baseRG.LineCoverage(cover.HandlerParams{
+		Progs: []cover.Prog{{PCs: basePCs}},
+		Force: *flagForce,
+	})
+	if err != nil {
+		tool.Fail(fmt.Errorf("base coverage: %w", err))
+	}
+	cov, err := rg.LineCoverage(params)
+	if err != nil {
+		tool.Fail(err)
+	}
+	diff := cover.DiffCoverage(baseCov, cov)
+	log.Logf(0, "coverage diff: +%v -%v lines in %v files", diff.GainedLines, diff.LostLines, len(diff.Files))
+	for fname, write := range map[string]func(io.Writer) error{
+		"syz-cover-diff.html": diff.WriteHTML,
+		"syz-cover-diff.json": diff.WriteJSON,
+	} {
+		buf := new(bytes.Buffer)
+		if err := write(buf); err != nil {
+			tool.Fail(err)
+		}
+		log.Logf(0, "write to %v", fname)
+		if err := osutil.WriteFile(fname, buf.Bytes()); err != nil {
+			tool.Fail(err)
+		}
+	}
+}
+
+func toolSaveLocalDB(cfg *mgrconfig.Config, rg *cover.ReportGenerator, params cover.HandlerParams) {
+	if *flagLocalDB == "" {
+		tool.Failf("localdb export requires -local-db")
+	}
+	dateTo, err := civil.ParseDate(*flagDateTo)
+	if err != nil {
+		tool.Failf("failed to parse date to: %v", err)
+	}
+	storage := coveragedb.NewLocalStorage(*flagLocalDB)
+	err = rg.SaveDayCoverage(context.Background(), storage, *flagNamespace, dateTo, params,
+		subsystem.GetList(cfg.TargetOS))
+	if err != nil {
+		tool.Fail(err)
+	}
+	log.Logf(0, "saved %v coverage for %v to %v", *flagNamespace, dateTo, *flagLocalDB)
+}
+
 func initPCs(rg *cover.ReportGenerator) []uint64 {
 	var pcs []uint64
 	if len(flag.Args()) == 0 {
@@ -239,17 +329,11 @@ func readPCs(files []string) ([]uint64, error) {
 		if err != nil {
 			return nil, err
 		}
-		for s := bufio.NewScanner(bytes.NewReader(data)); s.Scan(); {
-			line := strings.TrimSpace(s.Text())
-			if line == "" {
-				continue
-			}
-			pc, err := strconv.ParseUint(line, 0, 64)
-			if err != nil {
-				return nil, err
-			}
-			pcs = append(pcs, pc)
+		filePCs, err := cover.ParseRawCover(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", file, err)
 		}
+		pcs = append(pcs, filePCs...)
 	}
 	return pcs, nil
 }
@@ -15,6 +15,7 @@ import (
 	"github.com/google/syzkaller/pkg/coveragedb"
 	"github.com/google/syzkaller/pkg/covermerger"
 	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/subsystem"
 	_ "github.com/google/syzkaller/pkg/subsystem/lists"
 	"golang.org/x/exp/maps"
 )
@@ -32,6 +33,7 @@ var (
 	flagDashboardClientName = flag.String("dashboard-client-name", "coverage-merger", "[optional]")
 	flagSrcProvider         = flag.String("provider", "git-clone", "[optional] git-clone or web-git")
 	flagFilePathPrefix      = flag.String("file-path-prefix", "", "[optional] kernel file path prefix")
+	flagToLocalDB           = flag.String("to-local-db", "", "[optional] local coverage database directory")
 )
 
 func makeProvider() covermerger.FileVersProvider {
@@ -96,7 +98,19 @@ func main() {
 			log.Fatalf("failed to saveCoverage: %v", err)
 		}
 	}
-	printOnlyTotal := *flagToDashAPI != ""
+	if *flagToLocalDB != "" {
+		storage := coveragedb.NewLocalStorage(*flagToLocalDB)
+		if err := storage.SaveMergeResult(context.Background(), coverage, &coveragedb.HistoryRecord{
+			Namespace: *flagNamespace,
+			Repo:      *flagRepo,
+			Commit:    *flagCommit,
+			Duration:  *flagDuration,
+			DateTo:    dateTo,
+		}, *flagTotalRows, subsystem.GetList("linux")); err != nil {
+			log.Fatalf("failed to save coverage to local db: %v", err)
+		}
+	}
+	printOnlyTotal := *flagToDashAPI != "" || *flagToLocalDB != ""
 	printMergeResult(mergeResult, printOnlyTotal)
 }
 
@@ -9,8 +9,10 @@ import (
 	"log"
 	"os"
 	"runtime"
+	"time"
 
 	"github.com/google/syzkaller/pkg/csource"
+	"github.com/google/syzkaller/pkg/report"
 	"github.com/google/syzkaller/prog"
 	_ "github.com/google/syzkaller/sys"
 )
@@ -31,8 +33,16 @@ var (
 	flagTrace      = flag.Bool("trace", false, "trace syscall results")
 	flagStrict     = flag.Bool("strict", false, "parse input program in strict mode")
 	flagLeak       = flag.Bool("leak", false, "do leak checking")
+	flagReadable   = flag.Bool("readable", false, "use C structs and named flags instead of raw memory stores")
 	flagEnable     = flag.String("enable", "none", "enable only listed additional features")
 	flagDisable    = flag.String("disable", "none", "enable all additional features except listed")
+
+	flagKselftest = flag.Bool("kselftest", false, "generate a kselftest-style regression test "+
+		"that runs the program -repeat times and checks kernel log for crashes")
+	flagKselftestTitle  = flag.String("kselftest_title", "", "title of the bug checked by the kselftest")
+	flagKselftestReport = flag.String("kselftest_report", "", "file with the bug report, "+
+		"the kselftest fails if the kernel log contains the same crash")
+	flagKselftestTimeout = flag.Duration("kselftest_timeout", 5*time.Minute, "kselftest timeout")
 )
 
 func main() {
@@ -95,8 +105,14 @@ func main() {
 		UseTmpDir:     *flagUseTmpDir,
 		HandleSegv:    *flagHandleSegv,
 		Trace:         *flagTrace,
+		Readable:      *flagReadable,
+	}
+	var src []byte
+	if *flagKselftest {
+		src, err = writeKselftest(p, opts)
+	} else {
+		src, err = csource.Write(p, opts)
 	}
-	src, err := csource.Write(p, opts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to generate C source: %v\n", err)
 		os.Exit(1)
@@ -118,3 +134,27 @@ func main() {
 	os.Remove(bin)
 	fmt.Fprintf(os.Stderr, "binary build OK\n")
 }
+
+func writeKselftest(p *prog.Prog, opts csource.Options) ([]byte, error) {
+	if *flagRepeat <= 0 {
+		return nil, fmt.Errorf("kselftest requires a fixed number of iterations (-repeat)")
+	}
+	if *flagKselftestReport == "" {
+		return nil, fmt.Errorf("kselftest requires the bug report (-kselftest_report)")
+	}
+	rep, err := os.ReadFile(*flagKselftestReport)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := report.ExtractCrashSignature(p.Target.OS, rep)
+	if err != nil {
+		return nil, err
+	}
+	return csource.WriteKselftest(p, opts, csource.Kselftest{
+		Title:        *flagKselftestTitle,
+		Match:        signature.Match,
+		Suppressions: signature.Suppressions,
+		Iterations:   *flagRepeat,
+		Timeout:      *flagKselftestTimeout,
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"io"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/syzkaller/pkg/log"
@@ -21,17 +22,27 @@ type UpdateInfo func(cb func(info *Info))
 type Runner[T Instance] func(ctx context.Context, inst T, updInfo UpdateInfo)
 type CreateInstance[T Instance] func(int) (T, error)
 
+// ReuseCheck is called after a Runner has finished with an instance.
+// If it returns true, the instance is passed to the next Runner instead of being re-created.
+type ReuseCheck[T Instance] func(inst T) bool
+
 // Pool[T] provides the functionality of a generic pool of instances.
-// The instance is assumed to boot, be controlled by one Runner and then be re-created.
+// The instance is assumed to boot, be controlled by one Runner and then be re-created
+// (unless reuse is enabled with SetReuse and the instance passes the reuse check).
 // The pool is assumed to have one default Runner (e.g. to be used for fuzzing), while a
 // dynamically controlled sub-pool might be reserved for the arbitrary Runners.
 type Pool[T Instance] struct {
 	BootErrors chan error
 	BootTime   stat.AverageValue[time.Duration]
+	// Number of times an instance was reused instead of being re-created.
+	Reused atomic.Int64
 
 	creator    CreateInstance[T]
 	defaultJob Runner[T]
 	jobs       chan Runner[T]
+	reuse      ReuseCheck[T]
+	// Instances older than this are always re-created (0 means no limit).
+	maxLifetime time.Duration
 
 	// The mutex serializes ReserveForRun() and SetDefault() calls.
 	mu        sync.Mutex
@@ -70,6 +81,13 @@ func (p *Pool[T]) SetDefault(def Runner[T]) {
 	}
 }
 
+// SetReuse enables reuse of instances across Runner invocations.
+// Must be called before Loop.
+func (p *Pool[T]) SetReuse(check ReuseCheck[T], maxLifetime time.Duration) {
+	p.reuse = check
+	p.maxLifetime = maxLifetime
+}
+
 func (p *Pool[T]) Loop(ctx context.Context) {
 	var wg sync.WaitGroup
 	wg.Add(len(p.instances))
@@ -105,25 +123,44 @@ func (p *Pool[T]) runInstance(ctx context.Context, inst *poolInstance[T]) {
 
 	p.BootTime.Save(time.Since(start))
 
-	inst.status(StateWaiting)
-	// The job and jobChan fields are subject to concurrent updates.
-	inst.mu.Lock()
-	job, jobChan := inst.job, inst.jobChan
-	inst.mu.Unlock()
-
-	if job == nil {
-		select {
-		case newJob := <-jobChan:
-			job = newJob
-		case newJob := <-inst.switchToJob:
-			job = newJob
-		case <-ctx.Done():
+	for {
+		inst.status(StateWaiting)
+		// The job and jobChan fields are subject to concurrent updates.
+		inst.mu.Lock()
+		job, jobChan := inst.job, inst.jobChan
+		inst.mu.Unlock()
+
+		if job == nil {
+			select {
+			case newJob := <-jobChan:
+				job = newJob
+			case newJob := <-inst.switchToJob:
+				job = newJob
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		inst.status(StateRunning)
+		job(ctx, obj, inst.updateInfo)
+
+		if !p.canReuse(ctx, obj, start) {
 			return
 		}
+		p.Reused.Add(1)
+		log.Logf(1, "pool: reusing instance %d", inst.idx)
 	}
+}
 
-	inst.status(StateRunning)
-	job(ctx, obj, inst.updateInfo)
+func (p *Pool[T]) canReuse(ctx context.Context, obj T, start time.Time) bool {
+	if p.reuse == nil || ctx.Err() != nil {
+		// The instance was explicitly stopped (e.g. the job has changed).
+		return false
+	}
+	if p.maxLifetime != 0 && time.Since(start) > p.maxLifetime {
+		return false
+	}
+	return p.reuse(obj)
 }
 
 // ReserveForRun specifies the size of the sub-pool for the execution of custom runners.
@@ -193,6 +193,37 @@ func TestPoolNewDefault(t *testing.T) {
 	<-done
 }
 
+func TestPoolReuse(t *testing.T) {
+	var created, runs atomic.Int64
+	mgr := NewPool[*nilInstance](
+		1,
+		func(idx int) (*nilInstance, error) {
+			created.Add(1)
+			return &nilInstance{}, nil
+		},
+		func(ctx context.Context, _ *nilInstance, _ UpdateInfo) {
+			runs.Add(1)
+		},
+	)
+	// Reuse instances for the first 10 runs, then re-create them.
+	mgr.SetReuse(func(*nilInstance) bool {
+		return runs.Load() < 10
+	}, 0)
+	done := make(chan bool)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		mgr.Loop(ctx)
+		close(done)
+	}()
+	for runs.Load() < 20 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-done
+	assert.EqualValues(t, 9, mgr.Reused.Load())
+	assert.Greater(t, created.Load(), int64(10))
+}
+
 func makePool(count int) []testInstance {
 	var ret []testInstance
 	for i := 0; i < count; i++ {
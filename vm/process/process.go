@@ -0,0 +1,200 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// Package process provides a VM type that runs commands as child processes on the host
+// (in new user and mount namespaces by default, and optionally limited by a cgroup).
+// The command output is used as the console output.
+// It's intended for the test OS and for fuzzing the host kernel in throwaway namespaces,
+// e.g. for runtest, smoke tests and end-to-end manager tests on machines without VM images.
+package process
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/google/syzkaller/pkg/config"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/vm/vmimpl"
+)
+
+func init() {
+	vmimpl.Register("process", vmimpl.Type{
+		Ctor:       ctor,
+		Overcommit: true,
+	})
+}
+
+type Config struct {
+	Count int `json:"count"` // number of VMs to use (default: 1)
+	// Run commands in new user and mount namespaces (linux only, default: true).
+	// The current user is mapped to root in the user namespace.
+	// The pool refuses to start if the namespaces can't be created,
+	// set to false to explicitly run commands directly on the host.
+	Namespaces bool `json:"namespaces"`
+	// Parent cgroup v2 directory for per-VM cgroups, e.g. "/sys/fs/cgroup/syzkaller" (linux only).
+	// The manager needs write access to it. The limits below require it.
+	Cgroup string `json:"cgroup"`
+	Mem    int    `json:"mem"`  // memory limit per VM in MB (default: 0, unlimited)
+	CPU    int    `json:"cpu"`  // number of CPUs per VM (default: 0, unlimited)
+	Pids   int    `json:"pids"` // max number of processes and threads per VM (default: 0, unlimited)
+}
+
+// ErrNoNamespaces is returned when namespaces are enabled, but can't be created on the host.
+var ErrNoNamespaces = errors.New("can't create user and mount namespaces (set namespaces=false to run without them)")
+
+type Pool struct {
+	env *vmimpl.Env
+	cfg *Config
+}
+
+type instance struct {
+	cfg      *Config
+	debug    bool
+	scale    time.Duration
+	workdir  string
+	cgroup   *cgroup
+	closed   chan bool
+	closeMu  sync.Mutex
+	isClosed bool
+}
+
+func ctor(env *vmimpl.Env) (vmimpl.Pool, error) {
+	cfg := &Config{
+		Count:      1,
+		Namespaces: true,
+	}
+	if err := config.LoadData(env.Config, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse process vm config: %w", err)
+	}
+	if cfg.Count < 1 || cfg.Count > 128 {
+		return nil, fmt.Errorf("invalid config param count: %v, want [1, 128]", cfg.Count)
+	}
+	if cfg.Mem < 0 || cfg.CPU < 0 || cfg.Pids < 0 {
+		return nil, fmt.Errorf("mem/cpu/pids can't be negative")
+	}
+	if cfg.Cgroup == "" && (cfg.Mem != 0 || cfg.CPU != 0 || cfg.Pids != 0) {
+		return nil, fmt.Errorf("mem/cpu/pids limits require cgroup")
+	}
+	if err := checkConfig(cfg); err != nil {
+		return nil, err
+	}
+	pool := &Pool{
+		cfg: cfg,
+		env: env,
+	}
+	return pool, nil
+}
+
+func (pool *Pool) Count() int {
+	return pool.cfg.Count
+}
+
+func (pool *Pool) Create(workdir string, index int) (vmimpl.Instance, error) {
+	inst := &instance{
+		cfg:     pool.cfg,
+		debug:   pool.env.Debug,
+		scale:   pool.env.Timeouts.Scale,
+		workdir: workdir,
+		closed:  make(chan bool),
+	}
+	if pool.cfg.Cgroup != "" {
+		// The name needs to be unique across managers that share the parent cgroup.
+		name := regexp.MustCompile(`[^a-zA-Z0-9-_.]`).ReplaceAllString(pool.env.Name, "_")
+		dir := filepath.Join(pool.cfg.Cgroup, fmt.Sprintf("syz-%v-%v", name, index))
+		cg, err := createCgroup(dir, pool.cfg)
+		if err != nil {
+			return nil, err
+		}
+		inst.cgroup = cg
+	}
+	return inst, nil
+}
+
+func (inst *instance) Copy(hostSrc string) (string, error) {
+	dst := filepath.Join(inst.workdir, filepath.Base(hostSrc))
+	if err := osutil.CopyFile(hostSrc, dst); err != nil {
+		return "", err
+	}
+	if err := os.Chmod(dst, 0777); err != nil {
+		return "", err
+	}
+	return dst, nil
+}
+
+func (inst *instance) Forward(port int) (string, error) {
+	// The network namespace is shared with the host.
+	return fmt.Sprintf("127.0.0.1:%v", port), nil
+}
+
+func (inst *instance) Run(timeout time.Duration, stop <-chan bool, command string) (
+	<-chan []byte, <-chan error, error) {
+	rpipe, wpipe, err := osutil.LongPipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer wpipe.Close()
+	cmd := osutil.Command("/bin/sh", "-c", command)
+	cmd.Dir = inst.workdir
+	cmd.Stdout = wpipe
+	cmd.Stderr = wpipe
+	setupCmd(cmd, inst.cfg, inst.cgroup)
+	if err := cmd.Start(); err != nil {
+		rpipe.Close()
+		return nil, nil, fmt.Errorf("failed to start %q: %w", command, err)
+	}
+	wpipe.Close()
+	var tee io.Writer
+	if inst.debug {
+		tee = os.Stdout
+	}
+	merger := vmimpl.NewOutputMerger(tee)
+	merger.Add("process", rpipe)
+	return vmimpl.Multiplex(cmd, merger, timeout, vmimpl.MultiplexConfig{
+		Console: processKiller{cmd, inst.cgroup},
+		Stop:    stop,
+		Close:   inst.closed,
+		Debug:   inst.debug,
+		Scale:   inst.scale,
+	})
+}
+
+func (inst *instance) Diagnose(rep *report.Report) ([]byte, bool) {
+	return nil, false
+}
+
+func (inst *instance) Close() error {
+	inst.closeMu.Lock()
+	defer inst.closeMu.Unlock()
+	if inst.isClosed {
+		return nil
+	}
+	inst.isClosed = true
+	close(inst.closed)
+	if inst.cgroup != nil {
+		return inst.cgroup.destroy()
+	}
+	return nil
+}
+
+// processKiller kills the command with all its descendants
+// (the command may leave background processes that hold the output pipe).
+type processKiller struct {
+	cmd    *exec.Cmd
+	cgroup *cgroup
+}
+
+func (pk processKiller) Close() error {
+	killProcessGroup(pk.cmd)
+	if pk.cgroup != nil {
+		pk.cgroup.kill()
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package process
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/syzkaller/pkg/osutil"
+)
+
+type cgroup struct {
+	dir string
+	fd  *os.File
+}
+
+func checkConfig(cfg *Config) error {
+	if cfg.Namespaces {
+		// Don't silently fall back to running on the host if the namespaces are not supported
+		// (or disabled by sysctls/seccomp).
+		cmd := osutil.Command("/bin/sh", "-c", "true")
+		setupCmd(cmd, cfg, nil)
+		if output, err := osutil.Run(time.Minute, cmd); err != nil {
+			return fmt.Errorf("%w: %w\n%s", ErrNoNamespaces, err, output)
+		}
+	}
+	if cfg.Cgroup == "" {
+		return nil
+	}
+	// Enable the controllers we need for the child cgroups.
+	var controllers []string
+	if cfg.Mem != 0 {
+		controllers = append(controllers, "+memory")
+	}
+	if cfg.CPU != 0 {
+		controllers = append(controllers, "+cpu")
+	}
+	if cfg.Pids != 0 {
+		controllers = append(controllers, "+pids")
+	}
+	if len(controllers) == 0 {
+		return nil
+	}
+	file := filepath.Join(cfg.Cgroup, "cgroup.subtree_control")
+	if err := osutil.WriteFile(file, []byte(strings.Join(controllers, " "))); err != nil {
+		return fmt.Errorf("failed to enable cgroup controllers: %w", err)
+	}
+	return nil
+}
+
+func createCgroup(dir string, cfg *Config) (*cgroup, error) {
+	// Remove the cgroup left from a previous run, if any.
+	old := &cgroup{dir: dir}
+	if err := old.destroy(); err != nil {
+		return nil, err
+	}
+	if err := os.Mkdir(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cgroup: %w", err)
+	}
+	limits := make(map[string]string)
+	if cfg.Mem != 0 {
+		limits["memory.max"] = fmt.Sprint(cfg.Mem << 20)
+	}
+	if cfg.CPU != 0 {
+		const period = 100000
+		limits["cpu.max"] = fmt.Sprintf("%v %v", cfg.CPU*period, period)
+	}
+	if cfg.Pids != 0 {
+		limits["pids.max"] = fmt.Sprint(cfg.Pids)
+	}
+	for file, value := range limits {
+		if err := osutil.WriteFile(filepath.Join(dir, file), []byte(value)); err != nil {
+			os.Remove(dir)
+			return nil, fmt.Errorf("failed to set cgroup limit: %w", err)
+		}
+	}
+	fd, err := os.Open(dir)
+	if err != nil {
+		os.Remove(dir)
+		return nil, err
+	}
+	return &cgroup{dir: dir, fd: fd}, nil
+}
+
+func (cg *cgroup) kill() {
+	// Note: cgroup.kill requires linux 5.14+, for older kernels we rely on process group kill.
+	osutil.WriteFile(filepath.Join(cg.dir, "cgroup.kill"), []byte("1"))
+}
+
+func (cg *cgroup) destroy() error {
+	if cg.fd != nil {
+		cg.fd.Close()
+		cg.fd = nil
+	}
+	if !osutil.IsExist(cg.dir) {
+		return nil
+	}
+	cg.kill()
+	// Killed processes leave the cgroup asynchronously.
+	var err error
+	for i := 0; i < 100; i++ {
+		if err = os.Remove(cg.dir); err == nil {
+			return nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return fmt.Errorf("failed to remove cgroup: %w", err)
+}
+
+func setupCmd(cmd *exec.Cmd, cfg *Config, cg *cgroup) {
+	attr := cmd.SysProcAttr
+	if cfg.Namespaces {
+		attr.Cloneflags = syscall.CLONE_NEWUSER
+		// Unlike cloning, unsharing of the mount namespace also makes all mounts private,
+		// so that mounts done by the test don't propagate to the host.
+		attr.Unshareflags = syscall.CLONE_NEWNS
+		attr.UidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getuid(), Size: 1}}
+		attr.GidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getgid(), Size: 1}}
+	}
+	if cg != nil {
+		attr.UseCgroupFD = true
+		attr.CgroupFD = int(cg.fd.Fd())
+	}
+}
+
+func killProcessGroup(cmd *exec.Cmd) {
+	syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
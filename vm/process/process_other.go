@@ -0,0 +1,39 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+//go:build !linux
+
+package process
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+type cgroup struct{}
+
+func checkConfig(cfg *Config) error {
+	if cfg.Namespaces {
+		return fmt.Errorf("%w: namespaces are supported only on linux", ErrNoNamespaces)
+	}
+	if cfg.Cgroup != "" {
+		return fmt.Errorf("cgroups are supported only on linux")
+	}
+	return nil
+}
+
+func createCgroup(dir string, cfg *Config) (*cgroup, error) {
+	return nil, fmt.Errorf("cgroups are supported only on linux")
+}
+
+func (cg *cgroup) kill() {}
+
+func (cg *cgroup) destroy() error {
+	return nil
+}
+
+func setupCmd(cmd *exec.Cmd, cfg *Config, cg *cgroup) {}
+
+func killProcessGroup(cmd *exec.Cmd) {
+	cmd.Process.Kill()
+}
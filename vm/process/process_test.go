@@ -0,0 +1,127 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package process
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/google/syzkaller/vm/vmimpl"
+)
+
+func TestProcess(t *testing.T) {
+	if runtime.GOOS == targets.Windows {
+		t.Skip("not supported on windows")
+	}
+	inst := createInstance(t, `{"count": 2, "namespaces": false}`)
+	src := filepath.Join(t.TempDir(), "file")
+	if err := osutil.WriteFile(src, []byte("file contents")); err != nil {
+		t.Fatal(err)
+	}
+	dst, err := inst.Copy(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := runCommand(inst, time.Minute, "cat "+dst+"; echo; echo console >&2")
+	if err != nil {
+		t.Fatalf("command failed: %v\n%s", err, output)
+	}
+	if want := "file contents\nconsole\n"; string(output) != want {
+		t.Fatalf("got output %q, want %q", output, want)
+	}
+	// Background processes must not survive the command timeout.
+	start := time.Now()
+	_, err = runCommand(inst, time.Second, "sleep 1000 & sleep 1000")
+	if err != vmimpl.ErrTimeout {
+		t.Fatalf("got error %v, want timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Minute {
+		t.Fatalf("the command was not killed after %v", elapsed)
+	}
+}
+
+func TestProcessNamespaces(t *testing.T) {
+	if runtime.GOOS != targets.Linux {
+		t.Skip("namespaces are supported only on linux")
+	}
+	if !osutil.IsExist("/proc/self/ns/user") {
+		t.Skip("user namespaces are not supported")
+	}
+	hostNS, err := os.Readlink("/proc/self/ns/user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Namespaces are enabled by default.
+	pool, err := ctor(testEnv(`{}`))
+	if errors.Is(err, ErrNoNamespaces) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	inst := createPoolInstance(t, pool)
+	output, err := runCommand(inst, time.Minute, "id -u; readlink /proc/self/ns/user")
+	if err != nil {
+		t.Fatalf("command failed: %v\n%s", err, output)
+	}
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if len(lines) != 2 || lines[0] != "0" || lines[1] == hostNS {
+		t.Fatalf("the command is not run in a new user namespace (host %v):\n%s", hostNS, output)
+	}
+}
+
+func TestProcessNoNamespaces(t *testing.T) {
+	if runtime.GOOS == targets.Linux {
+		t.Skip("namespaces are supported on linux")
+	}
+	if _, err := ctor(testEnv(`{}`)); !errors.Is(err, ErrNoNamespaces) {
+		t.Fatalf("got error %v, want %v", err, ErrNoNamespaces)
+	}
+}
+
+func testEnv(cfg string) *vmimpl.Env {
+	return &vmimpl.Env{
+		Name:     "test",
+		Timeouts: targets.Timeouts{Scale: 1},
+		Config:   []byte(cfg),
+	}
+}
+
+func createInstance(t *testing.T, cfg string) vmimpl.Instance {
+	pool, err := ctor(testEnv(cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return createPoolInstance(t, pool)
+}
+
+func createPoolInstance(t *testing.T, pool vmimpl.Pool) vmimpl.Instance {
+	workdir := t.TempDir()
+	inst, err := pool.Create(workdir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { inst.Close() })
+	return inst
+}
+
+func runCommand(inst vmimpl.Instance, timeout time.Duration, command string) ([]byte, error) {
+	outc, errc, err := inst.Run(timeout, nil, command)
+	if err != nil {
+		return []byte(err.Error()), err
+	}
+	// The output channel is closed after the command and all its descendants exit.
+	var output []byte
+	for out := range outc {
+		output = append(output, out...)
+	}
+	return output, <-errc
+}
@@ -17,6 +17,9 @@ import (
 	"golang.org/x/sys/unix"
 )
 
+// Name of the snapshot taken after executor setup.
+const baseSnapshot = "syz"
+
 type snapshot struct {
 	ivsListener *net.UnixListener
 	ivsConn     *net.UnixConn
@@ -169,7 +172,7 @@ func (inst *instance) SetupSnapshot(input []byte) error {
 	if _, err := inst.hmp("migrate_set_capability x-ignore-shared on", 0); err != nil {
 		return err
 	}
-	if _, err := inst.hmp("savevm syz", 0); err != nil {
+	if _, err := inst.hmp("savevm "+baseSnapshot, 0); err != nil {
 		return err
 	}
 	if inst.debug {
@@ -182,23 +185,49 @@ func (inst *instance) SetupSnapshot(input []byte) error {
 	return nil
 }
 
-func (inst *instance) RunSnapshot(timeout time.Duration, input []byte) (result, output []byte, err error) {
+func (inst *instance) RunSnapshot(timeout time.Duration, input []byte, restore, save string) (
+	result, output []byte, saved bool, err error) {
+	if restore == "" {
+		restore = baseSnapshot
+	}
 	copy(inst.input, input)
 	inst.header.OutputOffset = 0
 	inst.header.OutputSize = 0
 	inst.header.UpdateState(flatrpc.SnapshotStateExecute)
-	if _, err := inst.hmp("loadvm syz", 0); err != nil {
-		return nil, nil, fmt.Errorf("%w\n%s", err, inst.readOutput())
+	if _, err := inst.hmp("loadvm "+restore, 0); err != nil {
+		return nil, nil, false, fmt.Errorf("%w\n%s", err, inst.readOutput())
+	}
+	deadline := time.Now().Add(timeout)
+	state := flatrpc.SnapshotStateExecute
+	for inst.waitSnapshotStateChange(state, max(time.Until(deadline), time.Millisecond)) {
+		state = inst.header.LoadState()
+		if state != flatrpc.SnapshotStatePrefixReady {
+			break
+		}
+		// Executor has finished the program prefix and waits for us to take a snapshot.
+		if save != "" && !saved {
+			if _, err := inst.hmp("savevm "+save, 0); err != nil {
+				return nil, nil, false, fmt.Errorf("%w\n%s", err, inst.readOutput())
+			}
+			saved = true
+			// Time to take the snapshot does not count towards the program timeout.
+			deadline = time.Now().Add(timeout)
+		}
+		inst.header.UpdateState(flatrpc.SnapshotStateSnapshotted)
+		state = flatrpc.SnapshotStateSnapshotted
 	}
-	inst.waitSnapshotStateChange(flatrpc.SnapshotStateExecute, timeout)
 	resStart := int(flatrpc.ConstMaxInputSize) + int(atomic.LoadUint32(&inst.header.OutputOffset))
 	resEnd := resStart + int(atomic.LoadUint32(&inst.header.OutputSize))
-	var res []byte
 	if resEnd <= len(inst.shmem) {
-		res = inst.shmem[resStart:resEnd:resEnd]
+		result = inst.shmem[resStart:resEnd:resEnd]
 	}
 	output = inst.readOutput()
-	return res, output, nil
+	return result, output, saved, nil
+}
+
+func (inst *instance) DeleteSnapshot(name string) error {
+	_, err := inst.hmp("delvm "+name, 0)
+	return err
 }
 
 func (inst *instance) waitSnapshotStateChange(state flatrpc.SnapshotState, timeout time.Duration) bool {
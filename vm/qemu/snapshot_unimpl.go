@@ -7,6 +7,7 @@ package qemu
 
 import (
 	"fmt"
+	"time"
 )
 
 type snapshot struct{}
@@ -28,6 +29,11 @@ func (inst *instance) SetupSnapshot(input []byte) error {
 	return errNotImplemented
 }
 
-func (inst *instance) RunSnapshot(input []byte) (result, output []byte, err error) {
-	return nil, nil, errNotImplemented
+func (inst *instance) RunSnapshot(timeout time.Duration, input []byte, restore, save string) (
+	result, output []byte, saved bool, err error) {
+	return nil, nil, false, errNotImplemented
+}
+
+func (inst *instance) DeleteSnapshot(name string) error {
+	return errNotImplemented
 }
@@ -35,6 +35,7 @@ import (
 	_ "github.com/google/syzkaller/vm/gce"
 	_ "github.com/google/syzkaller/vm/gvisor"
 	_ "github.com/google/syzkaller/vm/isolated"
+	_ "github.com/google/syzkaller/vm/process"
 	_ "github.com/google/syzkaller/vm/proxyapp"
 	_ "github.com/google/syzkaller/vm/qemu"
 	_ "github.com/google/syzkaller/vm/starnix"
@@ -208,21 +209,41 @@ func (inst *Instance) SetupSnapshot(input []byte) error {
 // Result is the result provided by the executor.
 // Output is the kernel console output during execution of the input.
 func (inst *Instance) RunSnapshot(input []byte) (result, output []byte, err error) {
+	result, output, _, err = inst.RunSnapshotPrefix(input, "", "")
+	return
+}
+
+// RunSnapshotPrefix is like RunSnapshot, but restores the prefix snapshot named restore
+// (or the base snapshot, if restore is empty). If save is not empty, it also takes a new
+// prefix snapshot with this name when executor reaches the end of the program prefix
+// (see flatrpc.SnapshotRequest.SnapshotPrefix). Saved says if the new snapshot was taken.
+func (inst *Instance) RunSnapshotPrefix(input []byte, restore, save string) (
+	result, output []byte, saved bool, err error) {
 	impl, ok := inst.impl.(snapshotter)
 	if !ok {
-		return nil, nil, errors.New("this VM type does not support snapshot mode")
+		return nil, nil, false, errors.New("this VM type does not support snapshot mode")
 	}
 	if !inst.snapshotSetup {
-		return nil, nil, fmt.Errorf("RunSnapshot without SetupSnapshot")
+		return nil, nil, false, fmt.Errorf("RunSnapshot without SetupSnapshot")
 	}
 	// Executor has own timeout logic, so use a slightly larger timeout here.
 	timeout := inst.pool.timeouts.Program / 5 * 7
-	return impl.RunSnapshot(timeout, input)
+	return impl.RunSnapshot(timeout, input, restore, save)
+}
+
+// DeleteSnapshot deletes a prefix snapshot previously taken by RunSnapshotPrefix.
+func (inst *Instance) DeleteSnapshot(name string) error {
+	impl, ok := inst.impl.(snapshotter)
+	if !ok {
+		return errors.New("this VM type does not support snapshot mode")
+	}
+	return impl.DeleteSnapshot(name)
 }
 
 type snapshotter interface {
 	SetupSnapshot([]byte) error
-	RunSnapshot(time.Duration, []byte) ([]byte, []byte, error)
+	RunSnapshot(timeout time.Duration, input []byte, restore, save string) ([]byte, []byte, bool, error)
+	DeleteSnapshot(name string) error
 }
 
 func (inst *Instance) Copy(hostSrc string) (string, error) {